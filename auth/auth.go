@@ -0,0 +1,229 @@
+// Package auth authorizes the RPC calls by bearer tokens, each of which grants a permission scope.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Scope is a permission scope. Each scope includes the permissions of the lower scopes,
+// i.e. an admin token may submit challenges, and a submit token may read.
+type Scope int
+
+const (
+	ScopeRead Scope = iota
+	ScopeSubmit
+	ScopeAdmin
+)
+
+// Scopes are all the permission scopes.
+var Scopes = []Scope{ScopeRead, ScopeSubmit, ScopeAdmin}
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeRead:
+		return "read"
+	case ScopeSubmit:
+		return "submit"
+	case ScopeAdmin:
+		return "admin"
+	default:
+		return fmt.Sprintf("Scope(%d)", int(s))
+	}
+}
+
+const (
+	// MetadataKey is the gRPC metadata key, as well as the HTTP header, of the bearer token.
+	MetadataKey = "authorization"
+
+	// TokenFileExt is the extension of the token files.
+	TokenFileExt = ".token"
+
+	bearerPrefix   = "Bearer "
+	tokenSize      = 32
+	tokenFilePerms = 0600
+)
+
+var (
+	ErrMissingToken = status.Error(codes.Unauthenticated, "authorization token is missing")
+	ErrInvalidToken = status.Error(codes.Unauthenticated, "authorization token is invalid")
+)
+
+// TokenFile returns the path of the token file of a given scope, in a given data directory.
+func TokenFile(datadir string, scope Scope) string {
+	return filepath.Join(datadir, scope.String()+TokenFileExt)
+}
+
+// ReadToken reads the token of a given scope from its file in a given data directory.
+func ReadToken(datadir string, scope Scope) (string, error) {
+	data, err := ioutil.ReadFile(TokenFile(datadir, scope))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Authorizer authorizes RPC calls by the scopes of their tokens.
+type Authorizer struct {
+	tokens map[Scope]string
+
+	// methodScopes are the scopes required by the RPC methods, by their full names.
+	// Methods which aren't listed require the admin scope.
+	methodScopes map[string]Scope
+
+	// minScope is the lowest scope which requires a token. Calls of methods which require a lower scope
+	// are allowed without a token.
+	minScope Scope
+}
+
+// NewAuthorizer loads the tokens of all the scopes from a given data directory, and generates
+// the missing ones into it. methodScopes are the scopes required by the RPC methods, by their
+// full names (e.g. "/api.Poet/Submit"). Methods which aren't listed require the admin scope.
+// minScope is the lowest scope which requires a token, e.g. ScopeAdmin allows the calls of the submit
+// and read scopes methods without a token, while ScopeRead requires a token for all the calls.
+func NewAuthorizer(datadir string, methodScopes map[string]Scope, minScope Scope) (*Authorizer, error) {
+	a := &Authorizer{
+		tokens:       make(map[Scope]string),
+		methodScopes: methodScopes,
+		minScope:     minScope,
+	}
+
+	for _, scope := range Scopes {
+		token, err := ReadToken(datadir, scope)
+		if os.IsNotExist(err) {
+			token, err = generateToken(datadir, scope)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %v token: %v", scope, err)
+		}
+		if token == "" {
+			return nil, fmt.Errorf("%v token file is empty", scope)
+		}
+		a.tokens[scope] = token
+	}
+
+	return a, nil
+}
+
+func generateToken(datadir string, scope Scope) (string, error) {
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	if err := ioutil.WriteFile(TokenFile(datadir, scope), []byte(token+"\n"), tokenFilePerms); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// scope returns the scope which a given token grants.
+func (a *Authorizer) scope(token string) (Scope, bool) {
+	var scope Scope
+	found := false
+	// All the tokens are compared, so that the comparison time doesn't depend on the matching one.
+	for s, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			scope, found = s, true
+		}
+	}
+	return scope, found
+}
+
+func (a *Authorizer) methodScope(fullMethod string) Scope {
+	if scope, ok := a.methodScopes[fullMethod]; ok {
+		return scope
+	}
+	return ScopeAdmin
+}
+
+// Authorize returns an error if the token of the call context doesn't grant a given scope.
+// A call without a token is authorized if the scope doesn't require one, but a specified token must be valid.
+func (a *Authorizer) Authorize(ctx context.Context, required Scope) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(MetadataKey)
+	if len(values) == 0 {
+		if required < a.minScope {
+			return nil
+		}
+		return ErrMissingToken
+	}
+
+	scope, ok := a.scope(strings.TrimPrefix(values[0], bearerPrefix))
+	if !ok {
+		return ErrInvalidToken
+	}
+	if scope < required {
+		return status.Error(codes.PermissionDenied, fmt.Sprintf("%v scope is required", required))
+	}
+	return nil
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor which authorizes unary calls.
+func (a *Authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.Authorize(ctx, a.methodScope(info.FullMethod)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC interceptor which authorizes streaming calls.
+func (a *Authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.Authorize(ss.Context(), a.methodScope(info.FullMethod)); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// HTTPHandler wraps the handler of the REST proxy, rejecting requests with an invalid token, as well as
+// requests without a token if all the scopes require one. The token is forwarded to the gRPC server, where
+// it's authorized for the called method, including the requests without a token.
+func (a *Authorizer) HTTPHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(MetadataKey)
+		if header == "" {
+			if a.minScope > ScopeRead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			http.Error(w, status.Convert(ErrMissingToken).Message(), http.StatusUnauthorized)
+			return
+		}
+		if _, ok := a.scope(strings.TrimPrefix(header, bearerPrefix)); !ok {
+			http.Error(w, status.Convert(ErrInvalidToken).Message(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// TokenCredentials are gRPC per-call credentials, which attach a bearer token to each call.
+type TokenCredentials string
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (t TokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{MetadataKey: bearerPrefix + string(t)}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials. Tokens may be sent over insecure
+// connections, e.g. to a local listener, hence TLS should be enabled for remote listeners.
+func (t TokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
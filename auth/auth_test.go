@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+var testMethodScopes = map[string]Scope{
+	"/api.Poet/Submit":  ScopeSubmit,
+	"/api.Poet/GetInfo": ScopeRead,
+}
+
+func TestNewAuthorizer(t *testing.T) {
+	req := require.New(t)
+
+	tempdir, err := ioutil.TempDir("", "poet-test")
+	req.NoError(err)
+	defer os.RemoveAll(tempdir)
+
+	a, err := NewAuthorizer(tempdir, testMethodScopes, ScopeRead)
+	req.NoError(err)
+
+	// The tokens are generated once, and are loaded on the following starts.
+	tokens := make(map[Scope]string)
+	for _, scope := range Scopes {
+		info, err := os.Stat(TokenFile(tempdir, scope))
+		req.NoError(err)
+		req.Equal(os.FileMode(0600), info.Mode().Perm())
+
+		token, err := ReadToken(tempdir, scope)
+		req.NoError(err)
+		req.Len(token, 2*tokenSize)
+		req.Equal(a.tokens[scope], token)
+		req.NotContains(tokens, token)
+		tokens[scope] = token
+	}
+
+	a, err = NewAuthorizer(tempdir, testMethodScopes, ScopeRead)
+	req.NoError(err)
+	req.Equal(tokens, a.tokens)
+}
+
+func TestAuthorizer_UnaryServerInterceptor(t *testing.T) {
+	req := require.New(t)
+
+	tempdir, err := ioutil.TempDir("", "poet-test")
+	req.NoError(err)
+	defer os.RemoveAll(tempdir)
+
+	a, err := NewAuthorizer(tempdir, testMethodScopes, ScopeRead)
+	req.NoError(err)
+
+	call := func(a *Authorizer, method string, token string) codes.Code {
+		ctx := context.Background()
+		if token != "" {
+			md, err := TokenCredentials(token).GetRequestMetadata(ctx)
+			req.NoError(err)
+			ctx = metadata.NewIncomingContext(ctx, metadata.New(md))
+		}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+		_, err := a.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return status.Code(err)
+	}
+
+	admin, submit, read := a.tokens[ScopeAdmin], a.tokens[ScopeSubmit], a.tokens[ScopeRead]
+	for _, tc := range []struct {
+		method string
+		token  string
+		code   codes.Code
+	}{
+		{"/api.Poet/GetInfo", "", codes.Unauthenticated},
+		{"/api.Poet/GetInfo", "invalid", codes.Unauthenticated},
+		{"/api.Poet/GetInfo", read, codes.OK},
+		{"/api.Poet/GetInfo", submit, codes.OK},
+		{"/api.Poet/GetInfo", admin, codes.OK},
+		{"/api.Poet/Submit", read, codes.PermissionDenied},
+		{"/api.Poet/Submit", submit, codes.OK},
+		{"/api.Poet/Submit", admin, codes.OK},
+		// Methods which aren't listed require the admin scope.
+		{"/api.Poet/Start", read, codes.PermissionDenied},
+		{"/api.Poet/Start", submit, codes.PermissionDenied},
+		{"/api.Poet/Start", admin, codes.OK},
+	} {
+		req.Equal(tc.code, call(a, tc.method, tc.token), "method: %v, token: %q", tc.method, tc.token)
+	}
+
+	// If only the admin scope requires a token, the rest of the calls are allowed without one.
+	a, err = NewAuthorizer(tempdir, testMethodScopes, ScopeAdmin)
+	req.NoError(err)
+	for _, tc := range []struct {
+		method string
+		token  string
+		code   codes.Code
+	}{
+		{"/api.Poet/GetInfo", "", codes.OK},
+		{"/api.Poet/GetInfo", "invalid", codes.Unauthenticated},
+		{"/api.Poet/Submit", "", codes.OK},
+		{"/api.Poet/Submit", read, codes.PermissionDenied},
+		{"/api.Poet/Start", "", codes.Unauthenticated},
+		{"/api.Poet/Start", submit, codes.PermissionDenied},
+		{"/api.Poet/Start", admin, codes.OK},
+	} {
+		req.Equal(tc.code, call(a, tc.method, tc.token), "method: %v, token: %q", tc.method, tc.token)
+	}
+}
+
+func TestAuthorizer_HTTPHandler(t *testing.T) {
+	req := require.New(t)
+
+	tempdir, err := ioutil.TempDir("", "poet-test")
+	req.NoError(err)
+	defer os.RemoveAll(tempdir)
+
+	a, err := NewAuthorizer(tempdir, testMethodScopes, ScopeRead)
+	req.NoError(err)
+
+	serve := func(a *Authorizer, header string) int {
+		handler := a.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		r := httptest.NewRequest(http.MethodGet, "/v1/info", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	req.Equal(http.StatusUnauthorized, serve(a, ""))
+	req.Equal(http.StatusUnauthorized, serve(a, "Bearer invalid"))
+	req.Equal(http.StatusOK, serve(a, "Bearer "+a.tokens[ScopeRead]))
+
+	// Requests without a token are authorized by the gRPC server, if not all the scopes require one.
+	a, err = NewAuthorizer(tempdir, testMethodScopes, ScopeAdmin)
+	req.NoError(err)
+	req.Equal(http.StatusOK, serve(a, ""))
+	req.Equal(http.StatusUnauthorized, serve(a, "Bearer invalid"))
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/spacemeshos/smutil/log"
 	"google.golang.org/genproto/googleapis/rpc/code"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"math/rand"
 	"sync"
 	"time"
 )
@@ -16,23 +19,117 @@ import (
 const (
 	DefaultConnTimeout      = 10 * time.Second
 	DefaultBroadcastTimeout = 30 * time.Second
+
+	// The fraction of the interval between reconnection attempts which is randomized, in each direction.
+	reconnectJitter = 0.2
+)
+
+var (
+	// healthCheckInterval is the duration a gateway connection may stay not ready before it's
+	// considered dead, and is replaced by a new one.
+	healthCheckInterval = 30 * time.Second
+
+	// minReconnectInterval and maxReconnectInterval are the bounds of the interval between reconnection attempts
+	// to a gateway node, as it grows exponentially.
+	minReconnectInterval = 1 * time.Second
+	maxReconnectInterval = 2 * time.Minute
 )
 
+// GatewayStatus is the connection status of a gateway node.
+type GatewayStatus struct {
+	Address string
+
+	// Connected indicates whether the gateway node has a ready connection.
+	Connected bool
+
+	// State is the gRPC connectivity state of the gateway node connection, or empty if there is none.
+	State string
+
+	LastError     string
+	LastErrorTime time.Time
+
+	// LastBroadcast is the time of the last successful broadcast via the gateway node.
+	LastBroadcast time.Time
+}
+
+// GatewaysReporter is a broadcaster which reports the connection status of its gateway nodes.
+type GatewaysReporter interface {
+	Gateways() []GatewayStatus
+}
+
+// gateway is a gateway node, along with its connection, if there is one.
+type gateway struct {
+	address string
+	creds   credentials.TransportCredentials // creds are nil if the connection is insecure.
+
+	sync.Mutex
+	conn          *grpc.ClientConn
+	client        pb.GatewayServiceClient
+	lastError     error
+	lastErrorTime time.Time
+	lastBroadcast time.Time
+}
+
+func (g *gateway) setConn(conn *grpc.ClientConn) {
+	g.Lock()
+	defer g.Unlock()
+	g.conn = conn
+	g.client = nil
+	if conn != nil {
+		g.client = pb.NewGatewayServiceClient(conn)
+	}
+}
+
+func (g *gateway) setError(err error) {
+	g.Lock()
+	defer g.Unlock()
+	g.lastError = err
+	g.lastErrorTime = time.Now()
+}
+
+func (g *gateway) status() GatewayStatus {
+	g.Lock()
+	defer g.Unlock()
+	s := GatewayStatus{
+		Address:       g.address,
+		LastErrorTime: g.lastErrorTime,
+		LastBroadcast: g.lastBroadcast,
+	}
+	if g.conn != nil {
+		state := g.conn.GetState()
+		s.Connected = state == connectivity.Ready
+		s.State = state.String()
+	}
+	if g.lastError != nil {
+		s.LastError = g.lastError.Error()
+	}
+	return s
+}
+
 // Broadcaster is responsible for broadcasting proofs to a list of Spacemesh-compatible gateway nodes.
+// The connections to the gateway nodes are monitored in the background, and the gateway nodes which
+// failed to connect or whose connection died are reconnected.
 type Broadcaster struct {
-	clients               []pb.GatewayServiceClient
-	connections           []*grpc.ClientConn
+	gateways              []*gateway
+	connTimeout           time.Duration
 	broadcastTimeout      time.Duration
 	broadcastAckThreshold uint
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	closeOnce sync.Once
 }
 
 // New instantiate a new Broadcaster for a given list of gateway nodes addresses.
+// tlsConfigs set the TLS configuration of the gateway nodes connections, by their addresses. Connections without one are insecure.
 // disableBroadcast allows to create a disabled Broadcaster instance.
 // connTimeout set the timeout per gRPC connection attempt to a node.
 // connAcksThreshold set the lower-bound of required successful gRPC connections to the nodes. If not met, an error will be returned.
 // broadcastTimeout set the timeout per proof broadcast.
 // broadcastAcksThreshold set the lower-bound of required successful proof broadcasts. If not met, a warning will be logged.
-func New(gatewayAddresses []string, disableBroadcast bool, connTimeout time.Duration, connAcksThreshold uint, broadcastTimeout time.Duration, broadcastAcksThreshold uint) (*Broadcaster, error) {
+// The nodes which failed to connect are kept, and are reconnected in the background.
+func New(gatewayAddresses []string, tlsConfigs map[string]*TLSConfig, disableBroadcast bool, connTimeout time.Duration, connAcksThreshold uint, broadcastTimeout time.Duration, broadcastAcksThreshold uint) (*Broadcaster, error) {
 	if disableBroadcast {
 		log.Info("Broadcast is disabled")
 		return &Broadcaster{}, nil
@@ -54,35 +151,68 @@ func New(gatewayAddresses []string, disableBroadcast bool, connTimeout time.Dura
 		return nil, fmt.Errorf("the successful connections threshold (%d) must be greater than the successful broadcast threshold (%d)", connAcksThreshold, broadcastAcksThreshold)
 	}
 
-	connectionVals := make([]*grpc.ClientConn, len(gatewayAddresses))
+	gateways := make([]*gateway, len(gatewayAddresses))
+	for i, address := range gatewayAddresses {
+		gateways[i] = &gateway{address: address}
+	}
+	for address, tlsConfig := range tlsConfigs {
+		found := false
+		for _, g := range gateways {
+			if g.address != address {
+				continue
+			}
+			creds, err := tlsConfig.credentials()
+			if err != nil {
+				return nil, fmt.Errorf("invalid TLS config of Spacemesh gateway node at \"%v\": %v", address, err)
+			}
+			g.creds = creds
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("TLS config of unknown gateway address \"%v\"", address)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &Broadcaster{
+		gateways:              gateways,
+		connTimeout:           connTimeout,
+		broadcastTimeout:      broadcastTimeout,
+		broadcastAckThreshold: broadcastAcksThreshold,
+		ctx:                   ctx,
+		cancel:                cancel,
+	}
 	errs := make([]error, len(gatewayAddresses))
 
 	log.Info("Attempting to connect to Spacemesh gateway nodes at %v", gatewayAddresses)
 	var wg sync.WaitGroup
 	wg.Add(len(gatewayAddresses))
-	for i, address := range gatewayAddresses {
+	for i, g := range b.gateways {
 		i := i
-		address := address
+		g := g
 		go func() {
 			defer wg.Done()
-			connectionVals[i], errs[i] = newClientConn(address, connTimeout)
+			var conn *grpc.ClientConn
+			conn, errs[i] = b.dial(g)
 			if errs[i] == nil {
-				log.Info("Successfully connected to Spacemesh gateway node at \"%v\"", address)
+				g.setConn(conn)
+				log.Info("Successfully connected to Spacemesh gateway node at \"%v\"", g.address)
 			}
 		}()
 	}
 	wg.Wait()
 
-	// Extract successful connections and concatenate errors of non-successful ones.
+	// Count successful connections and concatenate errors of non-successful ones.
+	var numConnections int
 	var retErr error
-	var connections = make([]*grpc.ClientConn, 0)
 	for i, err := range errs {
 		if err == nil {
-			connections = append(connections, connectionVals[i])
+			numConnections++
 			continue
 		}
 
 		err := fmt.Errorf("failed to connect to Spacemesh gateway node at \"%v\": %v", gatewayAddresses[i], err)
+		b.gateways[i].setError(err)
 		if retErr == nil {
 			retErr = err
 		} else {
@@ -90,53 +220,194 @@ func New(gatewayAddresses []string, disableBroadcast bool, connTimeout time.Dura
 		}
 	}
 
-	// If successful connections threshold wasn't met, return errors concatenation and close the other successful connections.
-	if len(connections) < int(connAcksThreshold) {
-		for _, conn := range connections {
-			_ = conn.Close()
-
-		}
+	// If successful connections threshold wasn't met, return errors concatenation and close the successful connections.
+	if numConnections < int(connAcksThreshold) {
+		_ = b.Close()
 		return nil, retErr
 	}
 
 	// If some connections failed, log it.
 	if retErr != nil {
-		numErrors := len(gatewayAddresses) - len(connections)
-		log.Warning("Failed to connect to %d/%d gateway nodes: %v", numErrors, len(gatewayAddresses), retErr)
+		numErrors := len(gatewayAddresses) - numConnections
+		log.Warning("Failed to connect to %d/%d gateway nodes, reconnecting in the background: %v", numErrors, len(gatewayAddresses), retErr)
 	}
 
-	clients := make([]pb.GatewayServiceClient, len(connections))
-	for i, conn := range connections {
-		clients[i] = pb.NewGatewayServiceClient(conn)
+	b.wg.Add(len(b.gateways))
+	for _, g := range b.gateways {
+		g := g
+		go func() {
+			defer b.wg.Done()
+			b.monitor(g)
+		}()
 	}
 
-	return &Broadcaster{
-		clients:               clients,
-		connections:           connections,
-		broadcastTimeout:      broadcastTimeout,
-		broadcastAckThreshold: broadcastAcksThreshold,
-	}, nil
+	return b, nil
+}
+
+// Close stops the gateway nodes monitoring, and closes their connections.
+func (b *Broadcaster) Close() error {
+	if b.cancel == nil {
+		return nil
+	}
+
+	b.closeOnce.Do(func() {
+		b.cancel()
+		b.wg.Wait()
+		for _, g := range b.gateways {
+			g.Lock()
+			if g.conn != nil {
+				_ = g.conn.Close()
+			}
+			g.Unlock()
+		}
+	})
+	return nil
+}
+
+// Gateways returns the connection status of the gateway nodes.
+func (b *Broadcaster) Gateways() []GatewayStatus {
+	statuses := make([]GatewayStatus, len(b.gateways))
+	for i, g := range b.gateways {
+		statuses[i] = g.status()
+	}
+	return statuses
+}
+
+// monitor keeps a gateway node connected until the broadcaster is closed. A connection which isn't ready
+// for healthCheckInterval is replaced, and failed connection attempts are retried with an exponential backoff.
+func (b *Broadcaster) monitor(g *gateway) {
+	var numFailures uint
+	var notReadySince time.Time
+
+	// The initial connection attempt was made by New.
+	g.Lock()
+	if g.conn == nil {
+		numFailures = 1
+	}
+	g.Unlock()
+
+	for {
+		g.Lock()
+		conn := g.conn
+		g.Unlock()
+
+		if conn == nil {
+			if numFailures > 0 {
+				timer := time.NewTimer(reconnectInterval(numFailures))
+				select {
+				case <-timer.C:
+				case <-b.ctx.Done():
+					timer.Stop()
+					return
+				}
+			}
+
+			conn, err := b.dial(g)
+			if err != nil {
+				if b.ctx.Err() != nil {
+					return
+				}
+				numFailures++
+				g.setError(fmt.Errorf("failed to connect to Spacemesh gateway node at \"%v\": %v", g.address, err))
+				log.Debug("Failed to reconnect to Spacemesh gateway node at \"%v\" (attempt %d): %v", g.address, numFailures, err)
+				continue
+			}
+			g.setConn(conn)
+			numFailures = 0
+			log.Info("Successfully reconnected to Spacemesh gateway node at \"%v\"", g.address)
+			continue
+		}
+
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			numFailures = 0
+			notReadySince = time.Time{}
+		} else if notReadySince.IsZero() {
+			notReadySince = time.Now()
+		}
+
+		if state != connectivity.Ready && time.Since(notReadySince) >= healthCheckInterval {
+			// The connection wasn't ready for a whole health check interval.
+			err := fmt.Errorf("connection to Spacemesh gateway node at \"%v\" is unhealthy (%v)", g.address, state)
+			log.Warning("%v, reconnecting", err)
+			g.setError(err)
+			g.setConn(nil)
+			_ = conn.Close()
+			notReadySince = time.Time{}
+			numFailures++
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(b.ctx, healthCheckInterval)
+		conn.WaitForStateChange(ctx, state)
+		cancel()
+		if b.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (b *Broadcaster) dial(g *gateway) (*grpc.ClientConn, error) {
+	ctx, cancel := context.WithTimeout(b.ctx, b.connTimeout)
+	defer cancel()
+	return newClientConn(ctx, g.address, g.creds)
+}
+
+// reconnectInterval returns the interval before the reconnection attempt which follows a given number of
+// failures. It grows exponentially from minReconnectInterval up to maxReconnectInterval,
+// and is randomized by reconnectJitter.
+func reconnectInterval(numFailures uint) time.Duration {
+	interval := minReconnectInterval
+	for i := uint(1); i < numFailures && interval < maxReconnectInterval; i++ {
+		interval *= 2
+	}
+	if interval > maxReconnectInterval {
+		interval = maxReconnectInterval
+	}
+
+	jitter := (2*rand.Float64() - 1) * reconnectJitter
+	return interval + time.Duration(float64(interval)*jitter)
 }
 
 // BroadcastProof broadcasts a serialized proof of a given round.
 func (b *Broadcaster) BroadcastProof(msg []byte, roundID string, members [][]byte) error {
-	if b.clients == nil {
+	_, err := b.BroadcastProofToGateways(msg, roundID, members, nil)
+	return err
+}
+
+// BroadcastProofToGateways broadcasts a serialized proof of a given round, and returns the broadcast result
+// of each gateway node, by its address, in addition to the overall result. A nil result indicates a successful broadcast.
+// The gateway nodes in acked, which already acknowledged the proof in a previous attempt, are skipped
+// and count as successful broadcasts, and their results aren't returned.
+func (b *Broadcaster) BroadcastProofToGateways(msg []byte, roundID string, members [][]byte, acked map[string]bool) (map[string]error, error) {
+	if b.gateways == nil {
 		log.Info("Broadcast is disabled, not broadcasting round %v proof", roundID)
-		return nil
+		return nil, nil
 	}
 	pbMsg := &pb.BroadcastPoetRequest{Data: msg}
 	ctx, cancel := context.WithTimeout(context.Background(), b.broadcastTimeout)
 	defer cancel()
 
-	responses := make([]*pb.BroadcastPoetResponse, len(b.clients))
-	errs := make([]error, len(b.clients))
+	responses := make([]*pb.BroadcastPoetResponse, len(b.gateways))
+	errs := make([]error, len(b.gateways))
 
 	start := time.Now()
 	var wg sync.WaitGroup
-	wg.Add(len(b.clients))
-	for i, client := range b.clients {
+	wg.Add(len(b.gateways))
+	for i, g := range b.gateways {
 		i := i
-		client := client
+		if acked[g.address] {
+			wg.Done()
+			continue
+		}
+		g.Lock()
+		client := g.client
+		g.Unlock()
+		if client == nil {
+			errs[i] = errors.New("not connected")
+			wg.Done()
+			continue
+		}
 		go func() {
 			defer wg.Done()
 			responses[i], errs[i] = client.BroadcastPoet(ctx, pbMsg)
@@ -148,21 +419,33 @@ func (b *Broadcaster) BroadcastProof(msg []byte, roundID string, members [][]byt
 	// Count successful responses and concatenate errors of non-successful ones.
 	var numAcks int
 	var retErr error
-	for i := range b.clients {
+	results := make(map[string]error, len(b.gateways))
+	for i, g := range b.gateways {
 		res := responses[i]
 		err := errs[i]
+		target := g.address
 
+		if acked[target] {
+			numAcks++
+			continue
+		}
 		if err != nil {
 			err = fmt.Errorf("failed to broadcast via gateway node at \"%v\" after %v: %v",
-				b.connections[i].Target(), elapsed, err)
+				target, elapsed, err)
 		} else if code.Code(res.Status.Code) != code.Code_OK {
 			err = fmt.Errorf("failed to broadcast via gateway node at \"%v\" after %v: node response: %s (%s)",
-				b.connections[i].Target(), elapsed, code.Code(res.Status.Code).String(), res.Status.GetMessage())
+				target, elapsed, code.Code(res.Status.Code).String(), res.Status.GetMessage())
 		} else {
 			// Valid response.
+			g.Lock()
+			g.lastBroadcast = time.Now()
+			g.Unlock()
+			results[target] = nil
 			numAcks++
 			continue
 		}
+		g.setError(err)
+		results[target] = err
 
 		if retErr == nil {
 			retErr = err
@@ -173,25 +456,28 @@ func (b *Broadcaster) BroadcastProof(msg []byte, roundID string, members [][]byt
 
 	// If successful broadcasts threshold wasn't met, return errors concatenation.
 	if numAcks < int(b.broadcastAckThreshold) {
-		return retErr
+		return results, retErr
 	}
 
 	// If some requests failed, log it.
 	if retErr != nil {
-		numErrors := len(b.clients) - numAcks
-		log.Warning("Round %v proof broadcast failed on %d/%d gateway nodes: %v", roundID, numErrors, len(b.clients), retErr)
+		numErrors := len(b.gateways) - numAcks
+		log.Warning("Round %v proof broadcast failed on %d/%d gateway nodes: %v", roundID, numErrors, len(b.gateways), retErr)
 	}
 
 	log.Info("Round %v proof broadcast completed successfully after %v, num of members: %d, proof size: %d", roundID, elapsed, len(members), len(msg))
-	return nil
+	return results, nil
 }
 
 // newClientConn returns a new gRPC client
-// connection to the specified target.
-func newClientConn(target string, timeout time.Duration) (*grpc.ClientConn, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+// connection to the specified target. The connection is insecure if creds are nil.
+func newClientConn(ctx context.Context, target string, creds credentials.TransportCredentials) (*grpc.ClientConn, error) {
+	transportOpt := grpc.WithInsecure()
+	if creds != nil {
+		transportOpt = grpc.WithTransportCredentials(creds)
+	}
 	opts := []grpc.DialOption{
-		grpc.WithInsecure(),
+		transportOpt,
 		grpc.WithBlock(),
 		// XXX: this is done to prevent routers from cleaning up our connections (e.g aws load balances..)
 		// TODO: these parameters work for now but we might need to revisit or add them as configuration
@@ -200,7 +486,6 @@ func newClientConn(target string, timeout time.Duration) (*grpc.ClientConn, erro
 			Timeout:             time.Minute * 3,
 			PermitWithoutStream: true,
 		})}
-	defer cancel()
 
 	conn, err := grpc.DialContext(ctx, target, opts...)
 	if err != nil {
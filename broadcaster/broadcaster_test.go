@@ -1,8 +1,16 @@
 package broadcaster
 
 import (
+	"context"
+	pb "github.com/spacemeshos/api/release/go/spacemesh/v1"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"net"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -11,39 +19,160 @@ func TestNew(t *testing.T) {
 	connTimeout := DefaultConnTimeout
 	broadcastTimeout := DefaultBroadcastTimeout
 
-	b, err := New([]string(nil), true, connTimeout, 0, broadcastTimeout, 0)
+	b, err := New([]string(nil), nil, true, connTimeout, 0, broadcastTimeout, 0)
 	req.NotNil(b)
 	req.NoError(err)
 
-	b, err = New([]string(nil), false, connTimeout, 0, broadcastTimeout, 0)
+	b, err = New([]string(nil), nil, false, connTimeout, 0, broadcastTimeout, 0)
 	req.Nil(b)
 	req.EqualError(err, "number of gateway addresses must be greater than 0")
 
-	b, err = New(make([]string, 0), false, connTimeout, 0, broadcastTimeout, 0)
+	b, err = New(make([]string, 0), nil, false, connTimeout, 0, broadcastTimeout, 0)
 	req.Nil(b)
 	req.EqualError(err, "number of gateway addresses must be greater than 0")
 
-	b, err = New(make([]string, 1), false, connTimeout, 0, broadcastTimeout, 0)
+	b, err = New(make([]string, 1), nil, false, connTimeout, 0, broadcastTimeout, 0)
 	req.Nil(b)
 	req.EqualError(err, "successful connections threshold must be greater than 0")
 
-	b, err = New(make([]string, 1), false, connTimeout, 1, broadcastTimeout, 0)
+	b, err = New(make([]string, 1), nil, false, connTimeout, 1, broadcastTimeout, 0)
 	req.Nil(b)
 	req.EqualError(err, "successful broadcast threshold must be greater than 0")
 
-	b, err = New(make([]string, 1), false, connTimeout, 2, broadcastTimeout, 1)
+	b, err = New(make([]string, 1), nil, false, connTimeout, 2, broadcastTimeout, 1)
 	req.Nil(b)
 	req.EqualError(err, "number of gateway addresses (1) must be greater than the successful connections threshold (2)")
 
-	b, err = New(make([]string, 1), false, connTimeout, 1, broadcastTimeout, 2)
+	b, err = New(make([]string, 1), nil, false, connTimeout, 1, broadcastTimeout, 2)
 	req.Nil(b)
 	req.EqualError(err, "the successful connections threshold (1) must be greater than the successful broadcast threshold (2)")
 
-	b, err = New([]string{"666"}, false, 0, 1, 0, 1)
+	b, err = New([]string{"666"}, nil, false, 0, 1, 0, 1)
 	req.Nil(b)
 	req.EqualError(err, "failed to connect to Spacemesh gateway node at \"666\": failed to connect to rpc server: context deadline exceeded")
 
-	b, err = New([]string{"666", "667"}, false, 0, 1, 0, 1)
+	b, err = New([]string{"666", "667"}, nil, false, 0, 1, 0, 1)
 	req.Nil(b)
 	req.EqualError(err, "failed to connect to Spacemesh gateway node at \"666\": failed to connect to rpc server: context deadline exceeded | failed to connect to Spacemesh gateway node at \"667\": failed to connect to rpc server: context deadline exceeded")
 }
+
+type gatewayServer struct {
+	pb.UnimplementedGatewayServiceServer
+	numBroadcasts int32
+}
+
+func (s *gatewayServer) BroadcastPoet(context.Context, *pb.BroadcastPoetRequest) (*pb.BroadcastPoetResponse, error) {
+	atomic.AddInt32(&s.numBroadcasts, 1)
+	return &pb.BroadcastPoetResponse{Status: &status.Status{Code: int32(code.Code_OK)}}, nil
+}
+
+// startGatewayServer starts a gateway node on a given address, which may have a 0 port.
+func startGatewayServer(t *testing.T, address string, opts ...grpc.ServerOption) (*grpc.Server, *gatewayServer, string) {
+	lis, err := net.Listen("tcp", address)
+	require.NoError(t, err)
+
+	server := grpc.NewServer(opts...)
+	gateway := &gatewayServer{}
+	pb.RegisterGatewayServiceServer(server, gateway)
+	go func() { _ = server.Serve(lis) }()
+	return server, gateway, lis.Addr().String()
+}
+
+// freeAddress returns a local address which isn't listened on.
+func freeAddress(t *testing.T) string {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	address := lis.Addr().String()
+	require.NoError(t, lis.Close())
+	return address
+}
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool, msg string) {
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting: %v", msg)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBroadcaster_Reconnect(t *testing.T) {
+	req := require.New(t)
+
+	prevMin, prevMax, prevHealth := minReconnectInterval, maxReconnectInterval, healthCheckInterval
+	minReconnectInterval, maxReconnectInterval, healthCheckInterval = 20*time.Millisecond, 100*time.Millisecond, 200*time.Millisecond
+	defer func() {
+		minReconnectInterval, maxReconnectInterval, healthCheckInterval = prevMin, prevMax, prevHealth
+	}()
+
+	liveServer, live, liveAddress := startGatewayServer(t, "localhost:0")
+	defer liveServer.Stop()
+	lateAddress := freeAddress(t)
+
+	b, err := New([]string{liveAddress, lateAddress}, nil, false, 500*time.Millisecond, 1, DefaultBroadcastTimeout, 1)
+	req.NoError(err)
+	defer b.Close()
+
+	statuses := b.Gateways()
+	req.Len(statuses, 2)
+	req.Equal(liveAddress, statuses[0].Address)
+	req.True(statuses[0].Connected)
+	req.Equal(lateAddress, statuses[1].Address)
+	req.False(statuses[1].Connected)
+	req.Contains(statuses[1].LastError, "failed to connect to Spacemesh gateway node")
+
+	// The broadcast succeeds via the connected gateway node only.
+	results, err := b.BroadcastProofToGateways([]byte("proof"), "0", nil, nil)
+	req.NoError(err)
+	req.Len(results, 2)
+	req.NoError(results[liveAddress])
+	req.Error(results[lateAddress])
+	req.EqualValues(1, atomic.LoadInt32(&live.numBroadcasts))
+	req.False(b.Gateways()[0].LastBroadcast.IsZero())
+
+	// The late gateway node is connected once it's up.
+	lateServer, late, _ := startGatewayServer(t, lateAddress)
+	defer lateServer.Stop()
+	waitFor(t, 5*time.Second, func() bool { return b.Gateways()[1].Connected }, "late gateway node to connect")
+
+	results, err = b.BroadcastProofToGateways([]byte("proof"), "0", nil, nil)
+	req.NoError(err)
+	req.NoError(results[lateAddress])
+	req.EqualValues(1, atomic.LoadInt32(&late.numBroadcasts))
+
+	// A gateway node which goes down is reported as disconnected, and is reconnected once it's up again.
+	lateServer.Stop()
+	waitFor(t, 5*time.Second, func() bool { return !b.Gateways()[1].Connected }, "late gateway node to disconnect")
+
+	lateServer, late, _ = startGatewayServer(t, lateAddress)
+	defer lateServer.Stop()
+	waitFor(t, 5*time.Second, func() bool { return b.Gateways()[1].Connected }, "late gateway node to reconnect")
+
+	results, err = b.BroadcastProofToGateways([]byte("proof"), "0", nil, nil)
+	req.NoError(err)
+	req.NoError(results[lateAddress])
+	req.EqualValues(1, atomic.LoadInt32(&late.numBroadcasts))
+
+	// The gateway nodes which already acknowledged the proof are skipped.
+	results, err = b.BroadcastProofToGateways([]byte("proof"), "0", nil, map[string]bool{lateAddress: true})
+	req.NoError(err)
+	req.Len(results, 1)
+	req.NoError(results[liveAddress])
+	req.EqualValues(1, atomic.LoadInt32(&late.numBroadcasts))
+}
+
+func TestReconnectInterval(t *testing.T) {
+	req := require.New(t)
+
+	for numFailures, expected := range map[uint]time.Duration{
+		1:   minReconnectInterval,
+		2:   2 * minReconnectInterval,
+		3:   4 * minReconnectInterval,
+		100: maxReconnectInterval,
+	} {
+		interval := reconnectInterval(numFailures)
+		req.True(interval >= time.Duration(float64(expected)*(1-reconnectJitter)), "num failures: %d, interval: %v", numFailures, interval)
+		req.True(interval <= time.Duration(float64(expected)*(1+reconnectJitter)), "num failures: %d, interval: %v", numFailures, interval)
+	}
+}
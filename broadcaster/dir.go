@@ -0,0 +1,40 @@
+package broadcaster
+
+import (
+	"fmt"
+	"github.com/spacemeshos/poet/shared"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// DirSink broadcasts proofs by writing them to a directory, one file per round, named by the round ID.
+// Each file is written atomically, so that readers never observe a partially written proof.
+type DirSink struct {
+	dir string
+}
+
+// NewDirSink creates a directory sink, and creates its directory if it doesn't exist.
+func NewDirSink(dir string) (*DirSink, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	return &DirSink{dir: dir}, nil
+}
+
+// BroadcastProof writes a serialized proof of a given round to the directory.
+func (d *DirSink) BroadcastProof(msg []byte, roundID string, members [][]byte) error {
+	filename := filepath.Join(d.dir, filepath.Base(roundID)+".bin")
+
+	// Write to a temporary file and rename it, so that the proof file appears only once it's complete.
+	tmpFilename := filename + ".tmp"
+	if err := ioutil.WriteFile(tmpFilename, msg, shared.OwnerReadWrite); err != nil {
+		return fmt.Errorf("write to disk failure: %v", err)
+	}
+	if err := os.Rename(tmpFilename, filename); err != nil {
+		return fmt.Errorf("write to disk failure: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package broadcaster
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spacemeshos/smutil/log"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Fanout broadcasts proofs to several sinks concurrently.
+type Fanout struct {
+	names         []string
+	sinks         []Sink
+	acksThreshold uint
+}
+
+// NewFanout creates a fan-out of a given set of sinks, by their names. acksThreshold set the lower-bound of
+// required successful broadcasts, or requires all of them if it's 0.
+func NewFanout(sinks map[string]Sink, acksThreshold uint) (*Fanout, error) {
+	if len(sinks) == 0 {
+		return nil, errors.New("number of sinks must be greater than 0")
+	}
+	if acksThreshold == 0 {
+		acksThreshold = uint(len(sinks))
+	}
+	if acksThreshold > uint(len(sinks)) {
+		return nil, fmt.Errorf("number of sinks (%d) must be greater than the successful broadcast threshold (%d)", len(sinks), acksThreshold)
+	}
+
+	f := &Fanout{acksThreshold: acksThreshold}
+	for name := range sinks {
+		f.names = append(f.names, name)
+	}
+	sort.Strings(f.names)
+	for _, name := range f.names {
+		f.sinks = append(f.sinks, sinks[name])
+	}
+
+	return f, nil
+}
+
+// BroadcastProof broadcasts a serialized proof of a given round to all the sinks.
+func (f *Fanout) BroadcastProof(msg []byte, roundID string, members [][]byte) error {
+	_, err := f.BroadcastProofToGateways(msg, roundID, members, nil)
+	return err
+}
+
+// BroadcastProofToGateways broadcasts a serialized proof of a given round to all the sinks, and returns the broadcast
+// result of each sink, by its name, in addition to the overall result. A nil result indicates a successful broadcast.
+// The results of a sink which reports the results of its gateway nodes are returned for each of them separately,
+// by the sink name and the gateway node address, separated by a slash. The sinks and gateway nodes in acked,
+// which already acknowledged the proof in a previous attempt, are skipped, and their results aren't returned.
+// A sink counts as a successful broadcast if it succeeded, or if it was skipped.
+func (f *Fanout) BroadcastProofToGateways(msg []byte, roundID string, members [][]byte, acked map[string]bool) (map[string]error, error) {
+	errs := make([]error, len(f.sinks))
+	sinksResults := make([]map[string]error, len(f.sinks))
+
+	var wg sync.WaitGroup
+	for i, sink := range f.sinks {
+		name := f.names[i]
+		if acked[name] {
+			continue
+		}
+
+		i := i
+		sink := sink
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if gs, ok := sink.(GatewaysSink); ok {
+				sinksResults[i], errs[i] = gs.BroadcastProofToGateways(msg, roundID, members, sinkAcked(acked, name))
+			} else {
+				errs[i] = sink.BroadcastProof(msg, roundID, members)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Count successful broadcasts and concatenate errors of non-successful ones.
+	var numAcks int
+	var retErr error
+	results := make(map[string]error, len(f.sinks))
+	for i, err := range errs {
+		name := f.names[i]
+		if acked[name] {
+			numAcks++
+			continue
+		}
+
+		if err != nil {
+			err = fmt.Errorf("failed to broadcast via sink %q: %v", name, err)
+		}
+		if len(sinksResults[i]) > 0 {
+			for gateway, gatewayErr := range sinksResults[i] {
+				results[name+"/"+gateway] = gatewayErr
+			}
+		} else {
+			results[name] = err
+		}
+
+		if err == nil {
+			numAcks++
+			continue
+		}
+		if retErr == nil {
+			retErr = err
+		} else {
+			retErr = fmt.Errorf("%v | %v", retErr, err)
+		}
+	}
+
+	// If successful broadcasts threshold wasn't met, return errors concatenation.
+	if numAcks < int(f.acksThreshold) {
+		return results, retErr
+	}
+
+	// If some broadcasts failed, log it.
+	if retErr != nil {
+		log.Warning("Round %v proof broadcast failed on %d/%d sinks: %v", roundID, len(f.sinks)-numAcks, len(f.sinks), retErr)
+	}
+
+	return results, nil
+}
+
+// sinkAcked returns the gateway nodes of a given sink which already acknowledged a proof,
+// out of the acknowledged sinks and gateway nodes of a fan-out.
+func sinkAcked(acked map[string]bool, name string) map[string]bool {
+	prefix := name + "/"
+	sinkAcked := make(map[string]bool)
+	for target := range acked {
+		if strings.HasPrefix(target, prefix) {
+			sinkAcked[strings.TrimPrefix(target, prefix)] = true
+		}
+	}
+	return sinkAcked
+}
+
+// Gateways returns the connection status of the gateway nodes of the sinks which report it.
+func (f *Fanout) Gateways() []GatewayStatus {
+	var statuses []GatewayStatus
+	for _, sink := range f.sinks {
+		if r, ok := sink.(GatewaysReporter); ok {
+			statuses = append(statuses, r.Gateways()...)
+		}
+	}
+	return statuses
+}
+
+// Close closes the sinks which hold resources, such as gateway nodes connections.
+func (f *Fanout) Close() error {
+	var retErr error
+	for _, sink := range f.sinks {
+		if c, ok := sink.(io.Closer); ok {
+			if err := c.Close(); err != nil && retErr == nil {
+				retErr = err
+			}
+		}
+	}
+	return retErr
+}
@@ -0,0 +1,120 @@
+package broadcaster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Sink is a destination which proofs are broadcast to.
+type Sink interface {
+	BroadcastProof(msg []byte, roundID string, members [][]byte) error
+}
+
+// GatewaysSink is a sink which reports the broadcast result of each of its gateway nodes, and which skips
+// the gateway nodes that already acknowledged a proof, so that retries target only the failed ones.
+type GatewaysSink interface {
+	Sink
+	BroadcastProofToGateways(msg []byte, roundID string, members [][]byte, acked map[string]bool) (map[string]error, error)
+}
+
+// SinkFactory creates a sink for a given target, whose format is specific to the sink kind.
+type SinkFactory func(target string) (Sink, error)
+
+var (
+	sinkFactories   = make(map[string]SinkFactory)
+	sinkFactoriesMu sync.Mutex
+)
+
+func init() {
+	RegisterSink("gateway", func(target string) (Sink, error) {
+		return NewGatewaySink(target, nil)
+	})
+	RegisterSink("webhook", func(target string) (Sink, error) {
+		return NewWebhookSink(target, DefaultBroadcastTimeout)
+	})
+	RegisterSink("dir", func(target string) (Sink, error) {
+		return NewDirSink(target)
+	})
+}
+
+// RegisterSink registers a sink factory for a given sink kind, replacing a previously registered one.
+func RegisterSink(kind string, factory SinkFactory) {
+	sinkFactoriesMu.Lock()
+	defer sinkFactoriesMu.Unlock()
+
+	sinkFactories[kind] = factory
+}
+
+// SinkKinds returns the registered sink kinds, sorted.
+func SinkKinds() []string {
+	sinkFactoriesMu.Lock()
+	defer sinkFactoriesMu.Unlock()
+
+	kinds := make([]string, 0, len(sinkFactories))
+	for kind := range sinkFactories {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// ParseSinkSpec parses a sink spec, which is formatted as "kind:target", and returns its kind and target.
+func ParseSinkSpec(spec string) (kind string, target string, err error) {
+	parts := strings.SplitN(spec, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid sink spec %q: expected kind:target", spec)
+	}
+	return parts[0], parts[1], nil
+}
+
+// NewSink creates a sink from its spec, which is formatted as "kind:target",
+// e.g. "webhook:https://example.com/proofs", "dir:/var/lib/proofs" or "gateway:localhost:9091".
+func NewSink(spec string) (Sink, error) {
+	return NewSinkWithTLS(spec, nil)
+}
+
+// NewSinkWithTLS creates a sink from its spec, like NewSink. A gateway sink is connected with the TLS config
+// of its address from a given set of TLS configs, if any, or insecurely otherwise.
+func NewSinkWithTLS(spec string, tlsConfigs map[string]*TLSConfig) (Sink, error) {
+	kind, target, err := ParseSinkSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var factory SinkFactory
+	if tlsConfig, ok := tlsConfigs[target]; ok && kind == "gateway" {
+		factory = func(target string) (Sink, error) {
+			return NewGatewaySink(target, tlsConfig)
+		}
+	} else {
+		sinkFactoriesMu.Lock()
+		factory, ok = sinkFactories[kind]
+		sinkFactoriesMu.Unlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown sink kind %q (registered kinds: %v)", kind, strings.Join(SinkKinds(), ", "))
+		}
+	}
+
+	sink, err := factory(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sink %q: %v", spec, err)
+	}
+	return sink, nil
+}
+
+// NewGatewaySink creates a sink of a single gateway node, which is connected with a given TLS config,
+// or insecurely if it's nil.
+func NewGatewaySink(address string, tlsConfig *TLSConfig) (Sink, error) {
+	var tlsConfigs map[string]*TLSConfig
+	if tlsConfig != nil {
+		tlsConfigs = map[string]*TLSConfig{address: tlsConfig}
+	}
+
+	b, err := New([]string{address}, tlsConfigs, false, DefaultConnTimeout, 1, DefaultBroadcastTimeout, 1)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,154 @@
+package broadcaster
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewSink(t *testing.T) {
+	req := require.New(t)
+
+	_, err := NewSink("webhook")
+	req.EqualError(err, "invalid sink spec \"webhook\": expected kind:target")
+
+	_, err = NewSink("unknown:target")
+	req.EqualError(err, "unknown sink kind \"unknown\" (registered kinds: dir, gateway, webhook)")
+
+	_, err = NewSink("webhook:ftp://host/path")
+	req.EqualError(err, "failed to create sink \"webhook:ftp://host/path\": unsupported webhook url scheme: \"ftp\"")
+
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+	sink, err := NewSink("dir:" + tempdir)
+	req.NoError(err)
+	req.IsType(&DirSink{}, sink)
+
+	sink, err = NewSink("webhook:http://localhost:1234/proofs")
+	req.NoError(err)
+	req.IsType(&WebhookSink{}, sink)
+}
+
+func TestWebhookSink(t *testing.T) {
+	req := require.New(t)
+
+	var received []byte
+	var roundID string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req.Equal(http.MethodPost, r.Method)
+		received, _ = ioutil.ReadAll(r.Body)
+		roundID = r.Header.Get(RoundIDHeader)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(server.URL, DefaultBroadcastTimeout)
+	req.NoError(err)
+
+	req.NoError(sink.BroadcastProof([]byte("proof"), "1", nil))
+	req.Equal([]byte("proof"), received)
+	req.Equal("1", roundID)
+
+	status = http.StatusServiceUnavailable
+	req.EqualError(sink.BroadcastProof([]byte("proof"), "2", nil), "webhook response: 503 Service Unavailable")
+}
+
+func TestDirSink(t *testing.T) {
+	req := require.New(t)
+
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+	dir := filepath.Join(tempdir, "proofs")
+	sink, err := NewDirSink(dir)
+	req.NoError(err)
+
+	req.NoError(sink.BroadcastProof([]byte("proof 1"), "1", nil))
+	req.NoError(sink.BroadcastProof([]byte("proof 2"), "2", nil))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "1.bin"))
+	req.NoError(err)
+	req.Equal([]byte("proof 1"), data)
+
+	entries, err := ioutil.ReadDir(dir)
+	req.NoError(err)
+	req.Len(entries, 2)
+}
+
+type mockSink struct {
+	err error
+}
+
+func (s *mockSink) BroadcastProof(msg []byte, roundID string, members [][]byte) error {
+	return s.err
+}
+
+func TestFanout(t *testing.T) {
+	req := require.New(t)
+
+	_, err := NewFanout(nil, 0)
+	req.EqualError(err, "number of sinks must be greater than 0")
+
+	sinks := map[string]Sink{
+		"a": &mockSink{},
+		"b": &mockSink{err: errors.New("unavailable")},
+		"c": &mockSink{},
+	}
+	_, err = NewFanout(sinks, 4)
+	req.EqualError(err, "number of sinks (3) must be greater than the successful broadcast threshold (4)")
+
+	// All the sinks are required by default.
+	f, err := NewFanout(sinks, 0)
+	req.NoError(err)
+	results, err := f.BroadcastProofToGateways([]byte("proof"), "1", nil, nil)
+	req.EqualError(err, "failed to broadcast via sink \"b\": unavailable")
+	req.Len(results, 3)
+	req.NoError(results["a"])
+	req.Error(results["b"])
+	req.NoError(results["c"])
+
+	f, err = NewFanout(sinks, 2)
+	req.NoError(err)
+	req.NoError(f.BroadcastProof([]byte("proof"), "1", nil))
+
+	// The sinks which already acknowledged the proof are skipped, and count as successful broadcasts.
+	f, err = NewFanout(sinks, 0)
+	req.NoError(err)
+	results, err = f.BroadcastProofToGateways([]byte("proof"), "1", nil, map[string]bool{"b": true})
+	req.NoError(err)
+	req.Len(results, 2)
+
+	// The results of the gateway nodes of a sink are reported separately, and the gateway nodes
+	// which already acknowledged the proof are skipped.
+	gateways := &mockGatewaysSink{}
+	f, err = NewFanout(map[string]Sink{"a": &mockSink{}, "gateways": gateways}, 0)
+	req.NoError(err)
+	results, err = f.BroadcastProofToGateways([]byte("proof"), "1", nil, map[string]bool{"gateways/x": true})
+	req.EqualError(err, "failed to broadcast via sink \"gateways\": unavailable")
+	req.Len(results, 2)
+	req.NoError(results["a"])
+	req.Error(results["gateways/y"])
+	req.Equal(map[string]bool{"x": true}, gateways.acked)
+}
+
+// mockGatewaysSink reports the broadcast results of two gateway nodes, x and y, of which y fails.
+type mockGatewaysSink struct {
+	acked map[string]bool
+}
+
+func (s *mockGatewaysSink) BroadcastProof(msg []byte, roundID string, members [][]byte) error {
+	_, err := s.BroadcastProofToGateways(msg, roundID, members, nil)
+	return err
+}
+
+func (s *mockGatewaysSink) BroadcastProofToGateways(msg []byte, roundID string, members [][]byte, acked map[string]bool) (map[string]error, error) {
+	s.acked = acked
+	err := errors.New("unavailable")
+	results := map[string]error{"y": err}
+	if !acked["x"] {
+		results["x"] = nil
+	}
+	return results, err
+}
@@ -0,0 +1,107 @@
+package broadcaster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/credentials"
+	"io/ioutil"
+	"strings"
+)
+
+// TLSConfig is the TLS configuration of a gateway node connection.
+type TLSConfig struct {
+	// CACertFile is a PEM bundle of the CA certificates which the gateway node certificate is verified against.
+	// If empty, the system CA certificates are used.
+	CACertFile string
+
+	// CertFile and KeyFile are a PEM client certificate and its private key, which are presented to the
+	// gateway node for mutual TLS. Both are empty if the gateway node doesn't authenticate its clients.
+	CertFile string
+	KeyFile  string
+
+	// ServerName overrides the name which the gateway node certificate is verified against.
+	// If empty, the host of the gateway node address is used.
+	ServerName string
+}
+
+// ParseTLSConfig parses the TLS configuration of a gateway node connection from its spec, which is formatted as
+// "address[,ca=file][,cert=file,key=file][,server-name=name]", e.g. "gateway.example.com:9091,ca=/etc/poet/ca.pem".
+// An address without options enables TLS with the system CA certificates.
+func ParseTLSConfig(spec string) (string, *TLSConfig, error) {
+	parts := strings.Split(spec, ",")
+	address := parts[0]
+	if address == "" {
+		return "", nil, fmt.Errorf("invalid gateway TLS spec %q: address is missing", spec)
+	}
+
+	cfg := &TLSConfig{}
+	for _, option := range parts[1:] {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return "", nil, fmt.Errorf("invalid gateway TLS spec %q: expected key=value option, got %q", spec, option)
+		}
+
+		switch kv[0] {
+		case "ca":
+			cfg.CACertFile = kv[1]
+		case "cert":
+			cfg.CertFile = kv[1]
+		case "key":
+			cfg.KeyFile = kv[1]
+		case "server-name":
+			cfg.ServerName = kv[1]
+		default:
+			return "", nil, fmt.Errorf("invalid gateway TLS spec %q: unknown option %q", spec, kv[0])
+		}
+	}
+
+	return address, cfg, nil
+}
+
+// credentials loads the configured certificates, and returns the gRPC transport credentials of the connection.
+func (c *TLSConfig) credentials() (credentials.TransportCredentials, error) {
+	cfg := &tls.Config{ServerName: c.ServerName}
+
+	if c.CACertFile != "" {
+		pem, err := ioutil.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificates: %v", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no CA certificates found in %v", c.CACertFile)
+		}
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, errors.New("client certificate and key must be specified together")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+// ParseTLSConfigs parses the TLS configurations of gateway nodes connections from their specs,
+// and returns them by their addresses.
+func ParseTLSConfigs(specs []string) (map[string]*TLSConfig, error) {
+	configs := make(map[string]*TLSConfig, len(specs))
+	for _, spec := range specs {
+		address, cfg, err := ParseTLSConfig(spec)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := configs[address]; ok {
+			return nil, fmt.Errorf("duplicate gateway TLS config of \"%v\"", address)
+		}
+		configs[address] = cfg
+	}
+	return configs, nil
+}
@@ -0,0 +1,182 @@
+package broadcaster
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseTLSConfig(t *testing.T) {
+	req := require.New(t)
+
+	address, cfg, err := ParseTLSConfig("localhost:9091")
+	req.NoError(err)
+	req.Equal("localhost:9091", address)
+	req.Equal(&TLSConfig{}, cfg)
+
+	address, cfg, err = ParseTLSConfig("localhost:9091,ca=/ca.pem,cert=/cert.pem,key=/key.pem,server-name=gateway")
+	req.NoError(err)
+	req.Equal("localhost:9091", address)
+	req.Equal(&TLSConfig{CACertFile: "/ca.pem", CertFile: "/cert.pem", KeyFile: "/key.pem", ServerName: "gateway"}, cfg)
+
+	_, _, err = ParseTLSConfig(",ca=/ca.pem")
+	req.EqualError(err, "invalid gateway TLS spec \",ca=/ca.pem\": address is missing")
+
+	_, _, err = ParseTLSConfig("localhost:9091,ca")
+	req.EqualError(err, "invalid gateway TLS spec \"localhost:9091,ca\": expected key=value option, got \"ca\"")
+
+	_, _, err = ParseTLSConfig("localhost:9091,foo=bar")
+	req.EqualError(err, "invalid gateway TLS spec \"localhost:9091,foo=bar\": unknown option \"foo\"")
+
+	_, err = ParseTLSConfigs([]string{"localhost:9091", "localhost:9091,ca=/ca.pem"})
+	req.EqualError(err, "duplicate gateway TLS config of \"localhost:9091\"")
+}
+
+// testCert is a PEM certificate and its private key, along with its parsed form.
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert creates a certificate for a given DNS name, which is signed by a given CA,
+// or is a self-signed CA certificate if ca is nil.
+func newTestCert(t *testing.T, name string, ca *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{name},
+	}
+	parent, signer := template, key
+	if ca == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		parent, signer = ca.cert, ca.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+	return path
+}
+
+func TestBroadcaster_MutualTLS(t *testing.T) {
+	req := require.New(t)
+
+	tempdir, err := ioutil.TempDir("", "poet-test")
+	req.NoError(err)
+	defer os.RemoveAll(tempdir)
+
+	ca := newTestCert(t, "ca", nil)
+	serverCert := newTestCert(t, "gateway.test", ca)
+	clientCert := newTestCert(t, "poet.test", ca)
+
+	caFile := writeFile(t, tempdir, "ca.pem", ca.certPEM)
+	certFile := writeFile(t, tempdir, "cert.pem", clientCert.certPEM)
+	keyFile := writeFile(t, tempdir, "key.pem", clientCert.keyPEM)
+
+	serverKeyPair, err := tls.X509KeyPair(serverCert.certPEM, serverCert.keyPEM)
+	req.NoError(err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	server, gateway, address := startGatewayServer(t, "127.0.0.1:0", grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverKeyPair},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	defer server.Stop()
+
+	connTimeout := 500 * time.Millisecond
+
+	// The gateway node certificate isn't issued for its address, hence the server name must be overridden.
+	_, err = New([]string{address}, map[string]*TLSConfig{
+		address: {CACertFile: caFile, CertFile: certFile, KeyFile: keyFile},
+	}, false, connTimeout, 1, DefaultBroadcastTimeout, 1)
+	req.Error(err)
+
+	// An insecure connection isn't accepted.
+	_, err = New([]string{address}, nil, false, connTimeout, 1, DefaultBroadcastTimeout, 1)
+	req.Error(err)
+
+	b, err := New([]string{address}, map[string]*TLSConfig{
+		address: {CACertFile: caFile, CertFile: certFile, KeyFile: keyFile, ServerName: "gateway.test"},
+	}, false, connTimeout, 1, DefaultBroadcastTimeout, 1)
+	req.NoError(err)
+	defer b.Close()
+
+	req.NoError(b.BroadcastProof([]byte("proof"), "0", nil))
+	req.EqualValues(1, atomic.LoadInt32(&gateway.numBroadcasts))
+
+	// A gateway sink is connected with the TLS config of its address.
+	sink, err := NewSinkWithTLS("gateway:"+address, map[string]*TLSConfig{
+		address: {CACertFile: caFile, CertFile: certFile, KeyFile: keyFile, ServerName: "gateway.test"},
+	})
+	req.NoError(err)
+	defer sink.(*Broadcaster).Close()
+
+	req.NoError(sink.BroadcastProof([]byte("proof"), "0", nil))
+	req.EqualValues(2, atomic.LoadInt32(&gateway.numBroadcasts))
+}
+
+func TestNew_InvalidTLSConfig(t *testing.T) {
+	req := require.New(t)
+
+	tempdir, err := ioutil.TempDir("", "poet-test")
+	req.NoError(err)
+	defer os.RemoveAll(tempdir)
+
+	_, err = New([]string{"localhost:9091"}, map[string]*TLSConfig{
+		"localhost:9092": {},
+	}, false, DefaultConnTimeout, 1, DefaultBroadcastTimeout, 1)
+	req.EqualError(err, "TLS config of unknown gateway address \"localhost:9092\"")
+
+	_, err = New([]string{"localhost:9091"}, map[string]*TLSConfig{
+		"localhost:9091": {CertFile: "/cert.pem"},
+	}, false, DefaultConnTimeout, 1, DefaultBroadcastTimeout, 1)
+	req.EqualError(err, "invalid TLS config of Spacemesh gateway node at \"localhost:9091\": client certificate and key must be specified together")
+
+	notPEM := writeFile(t, tempdir, "ca.pem", []byte("not a certificate"))
+	_, err = New([]string{"localhost:9091"}, map[string]*TLSConfig{
+		"localhost:9091": {CACertFile: notPEM},
+	}, false, DefaultConnTimeout, 1, DefaultBroadcastTimeout, 1)
+	req.EqualError(err, "invalid TLS config of Spacemesh gateway node at \"localhost:9091\": no CA certificates found in "+notPEM)
+}
@@ -0,0 +1,66 @@
+package broadcaster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RoundIDHeader is the HTTP header in which the webhook sink specifies the round ID of the proof.
+const RoundIDHeader = "X-Poet-Round-Id"
+
+// WebhookSink broadcasts proofs by POSTing them to an HTTP endpoint. The request body is the serialized
+// proof message, and any 2xx response status is considered a successful broadcast.
+type WebhookSink struct {
+	url     string
+	client  *http.Client
+	timeout time.Duration
+}
+
+// NewWebhookSink creates a webhook sink for a given endpoint URL. timeout set the timeout per proof broadcast.
+func NewWebhookSink(endpoint string, timeout time.Duration) (*WebhookSink, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported webhook url scheme: %q", u.Scheme)
+	}
+
+	return &WebhookSink{
+		url:     endpoint,
+		client:  &http.Client{},
+		timeout: timeout,
+	}, nil
+}
+
+// BroadcastProof posts a serialized proof of a given round to the webhook endpoint.
+func (w *WebhookSink) BroadcastProof(msg []byte, roundID string, members [][]byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(msg))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set(RoundIDHeader, roundID)
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook response: %v", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spacemeshos/poet/service"
+	"github.com/spacemeshos/smutil/log"
+	"os"
+)
+
+// calibrate benchmarks the host, and stores the number of leaves whose execution fits the calibration target
+// duration, so that it could be applied on the following startups.
+func calibrate() error {
+	target := cfg.CalibrationTarget
+	if target == 0 {
+		target = cfg.Service.RoundsDuration
+	}
+	if target == 0 {
+		return errors.New("calibration target duration must be specified if rounds duration isn't")
+	}
+
+	if err := os.MkdirAll(cfg.DataDir, 0700); err != nil {
+		return err
+	}
+
+	log.Info("Calibrating: benchmarking %d leaves, target duration: %v, safety margin: %v",
+		cfg.CalibrationSampleLeaves, target, cfg.CalibrationMargin)
+
+	c, err := service.Calibrate(cfg.Service, cfg.DataDir, target, cfg.CalibrationMargin, cfg.CalibrationSampleLeaves)
+	if err != nil {
+		return fmt.Errorf("calibration failure: %v", err)
+	}
+
+	log.Info("Calibration completed: label hash rate: %.0f/s, leaves rate: %.0f/s, num leaves: %d",
+		c.LabelHashRate, c.LeavesRate, c.NumLeaves)
+	log.Info("Use --apply-calibration to apply the calibration result on startup")
+
+	return nil
+}
+
+// applyCalibration sets the number of leaves per the stored calibration result, if requested.
+// Otherwise, it notifies about an available calibration result which differs from the configured number of leaves.
+func applyCalibration() error {
+	c, err := service.LoadCalibration(cfg.DataDir)
+	if err == service.ErrNoCalibration {
+		if cfg.ApplyCalibration {
+			return errors.New("calibration result not found, use --calibrate to calibrate first")
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to load calibration result: %v", err)
+	}
+
+	if cfg.ApplyCalibration {
+		cfg.Service.NumLeaves = c.NumLeaves
+		log.Info("Calibration result applied: num leaves: %d (calibrated at %v, target duration: %v)",
+			c.NumLeaves, c.Calibrated.Format("2006-01-02 15:04:05"), c.TargetDuration)
+	} else if cfg.Service.NumLeaves != c.NumLeaves {
+		log.Info("Calibration result available: num leaves: %d (target duration: %v). Use --apply-calibration to apply it",
+			c.NumLeaves, c.TargetDuration)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jessevdk/go-flags"
+	"github.com/spacemeshos/poet/shared"
+	"os"
+)
+
+const (
+	formatXDR      = "xdr"
+	formatProtobuf = "protobuf"
+	formatJSON     = "json"
+
+	defaultFormat = formatXDR
+)
+
+// config defines the configuration options for verify.
+type config struct {
+	File          string `short:"f" long:"file" description:"path of the proof file. if not specified, the proof is read from stdin"`
+	Format        string `long:"format" choice:"xdr" choice:"protobuf" choice:"json" description:"proof encoding: an XDR PoetProofMessage, as broadcast by the service, or a protobuf / JSON GetProof response"`
+	N             uint   `short:"n" description:"protocol n param. the proof is required to have 2^n leaves. if neither n nor num-leaves is specified, the proof is reported as unverified"`
+	NumLeaves     uint64 `long:"num-leaves" description:"required number of leaves of the proof. overrides n"`
+	SecurityParam uint32 `long:"security-param" description:"minimal required security param of the proof"`
+	ServicePubKey string `long:"service-pubkey" description:"hex-encoded public key of the expected PoET service. if not specified, or if the proof isn't an XDR proof message, the proof is reported as unverified"`
+	JSON          bool   `long:"json" description:"whether to print the report as JSON"`
+}
+
+// loadConfig initializes and parses the config using command line options.
+func loadConfig() (*config, error) {
+	// Default config.
+	cfg := config{
+		Format:        defaultFormat,
+		SecurityParam: shared.T,
+	}
+
+	// Parse command line options.
+	if _, err := flags.Parse(&cfg); err != nil {
+		if e, ok := err.(*flags.Error); ok && e.Type == flags.ErrHelp {
+		} else {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+		}
+		return nil, err
+	}
+
+	if cfg.N > maxN {
+		err := fmt.Errorf("n must not exceed %d", maxN)
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return nil, err
+	}
+
+	return &cfg, nil
+}
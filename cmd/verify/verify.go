@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/nullstyle/go-xdr/xdr3"
+	"github.com/spacemeshos/poet/rpc/api"
+	"github.com/spacemeshos/poet/shared"
+	"github.com/spacemeshos/poet/verifier"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+)
+
+// Exit codes.
+const (
+	exitValid      = 0
+	exitInvalid    = 1
+	exitError      = 2
+	exitUnverified = 3
+)
+
+// Verification results. A proof is unverified if it passes its checks, but its service or its number of leaves
+// weren't checked, since they weren't specified or since the proof isn't signed.
+const (
+	resultValid      = "valid"
+	resultInvalid    = "invalid"
+	resultUnverified = "unverified"
+)
+
+// maxN is the highest n param of a proof, such that its 2^n leaves fit in a uint64.
+const maxN = 63
+
+// Check statuses.
+const (
+	statusPass    = "pass"
+	statusFail    = "fail"
+	statusSkipped = "skipped"
+)
+
+// report is the verification report of a proof.
+type report struct {
+	Valid         bool     `json:"valid"`
+	Result        string   `json:"result"`
+	Format        string   `json:"format"`
+	RoundID       string   `json:"roundId,omitempty"`
+	ServicePubKey string   `json:"servicePubKey,omitempty"`
+	NumLeaves     uint64   `json:"numLeaves"`
+	SecurityParam uint32   `json:"securityParam"`
+	NumMembers    int      `json:"numMembers"`
+	Root          string   `json:"root"`
+	Checks        []*check `json:"checks"`
+}
+
+type check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (r *report) add(name string, err error) bool {
+	c := &check{Name: name, Status: statusPass}
+	if err != nil {
+		c.Status = statusFail
+		c.Error = err.Error()
+	}
+	r.Checks = append(r.Checks, c)
+	return err == nil
+}
+
+func (r *report) skip(name string, reason string) {
+	r.Checks = append(r.Checks, &check{Name: name, Status: statusSkipped, Error: reason})
+}
+
+func main() {
+	cfg, err := loadConfig()
+	if err != nil {
+		os.Exit(exitError)
+	}
+
+	data, err := readInput(cfg.File)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to read proof: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	var expectedPubKey []byte
+	if cfg.ServicePubKey != "" {
+		expectedPubKey, err = hex.DecodeString(cfg.ServicePubKey)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "invalid service public key: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+
+	r := verify(cfg, data, expectedPubKey)
+	if err := printReport(r, cfg.JSON); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to print report: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	switch r.Result {
+	case resultValid:
+		os.Exit(exitValid)
+	case resultUnverified:
+		os.Exit(exitUnverified)
+	default:
+		os.Exit(exitInvalid)
+	}
+}
+
+func readInput(file string) ([]byte, error) {
+	if file == "" || file == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(file)
+}
+
+// verify decodes a proof and verifies it, and reports the result of each check.
+// Once a check fails, the following checks are not performed.
+func verify(cfg *config, data []byte, expectedPubKey []byte) *report {
+	r := &report{Format: cfg.Format, Result: resultInvalid}
+
+	proof, msg, statement, err := decode(cfg.Format, data)
+	if !r.add("decode", err) {
+		return r
+	}
+	r.NumLeaves = proof.NumLeaves
+	r.SecurityParam = proof.SecurityParam
+	r.NumMembers = len(proof.Members)
+	r.Root = fmt.Sprintf("%x", proof.Root)
+
+	if msg != nil {
+		r.RoundID = msg.RoundID
+		r.ServicePubKey = fmt.Sprintf("%x", msg.ServicePubKey)
+
+		if expectedPubKey != nil {
+			var err error
+			if !bytes.Equal(expectedPubKey, msg.ServicePubKey) {
+				err = verifier.ErrUnexpectedService
+			}
+			if !r.add("service", err) {
+				return r
+			}
+		} else {
+			r.skip("service", "no expected service public key was specified")
+		}
+		if !r.add("signature", shared.VerifyProofMessageSignature(msg)) {
+			return r
+		}
+	} else {
+		r.skip("service", "the proof is not signed")
+		r.skip("signature", "the proof is not signed")
+	}
+
+	if numLeaves := requiredNumLeaves(cfg); numLeaves != 0 {
+		var err error
+		if proof.NumLeaves != numLeaves {
+			err = fmt.Errorf("number of leaves (%d) doesn't match the required number (%d)", proof.NumLeaves, numLeaves)
+		}
+		if !r.add("leaves", err) {
+			return r
+		}
+	} else {
+		r.skip("leaves", "no required number of leaves was specified")
+	}
+	if !r.add("params", checkParams(cfg, proof)) {
+		return r
+	}
+
+	if statement != nil {
+		root, err := verifier.MembersRoot(proof.Members)
+		if err == nil && !bytes.Equal(root, statement) {
+			err = fmt.Errorf("statement %x doesn't match the members root %x", statement, root)
+		}
+		if !r.add("statement", err) {
+			return r
+		}
+	}
+
+	if !r.add("proof", verifier.ValidatePoetProof(proof, requiredNumLeaves(cfg), cfg.SecurityParam)) {
+		return r
+	}
+
+	for _, c := range r.Checks {
+		if c.Status == statusSkipped {
+			r.Result = resultUnverified
+			return r
+		}
+	}
+	r.Valid = true
+	r.Result = resultValid
+	return r
+}
+
+// decode decodes a proof of a given format. The proof message is returned for signed proofs only,
+// and the statement is returned for proofs which specify it.
+func decode(format string, data []byte) (*shared.GossipPoetProof, *shared.PoetProofMessage, []byte, error) {
+	switch format {
+	case formatXDR:
+		msg := new(shared.PoetProofMessage)
+		if _, err := xdr.Unmarshal(bytes.NewReader(data), msg); err != nil {
+			// Avoid printing the values which were read, as they might be large.
+			if e, ok := err.(*xdr.UnmarshalError); ok {
+				return nil, nil, nil, fmt.Errorf("xdr:%s: %s", e.Func, e.Description)
+			}
+			return nil, nil, nil, err
+		}
+		return &msg.GossipPoetProof, msg, nil, nil
+	case formatProtobuf, formatJSON:
+		res := new(api.GetProofResponse)
+		var err error
+		if format == formatProtobuf {
+			err = proto.Unmarshal(data, res)
+		} else {
+			unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+			err = unmarshaler.Unmarshal(bytes.NewReader(data), res)
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if res.Proof == nil {
+			return nil, nil, nil, fmt.Errorf("proof is missing")
+		}
+		proof := &shared.GossipPoetProof{
+			MerkleProof: shared.MerkleProof{
+				Root:         res.Proof.Phi,
+				ProvenLeaves: res.Proof.ProvenLeaves,
+				ProofNodes:   res.Proof.ProofNodes,
+			},
+			Members:       res.Members,
+			NumLeaves:     res.NumLeaves,
+			SecurityParam: res.SecurityParam,
+		}
+		if proof.NumLeaves == 0 {
+			if res.N > maxN {
+				return nil, nil, nil, fmt.Errorf("n (%d) exceeds the limit (%d)", res.N, maxN)
+			}
+			proof.NumLeaves = uint64(1) << res.N
+		}
+		return proof, nil, res.Statement, nil
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown format: %v", format)
+	}
+}
+
+// requiredNumLeaves returns the required number of leaves of the proof, or 0 if it wasn't specified.
+func requiredNumLeaves(cfg *config) uint64 {
+	if cfg.NumLeaves == 0 && cfg.N > 0 {
+		return uint64(1) << cfg.N
+	}
+	return cfg.NumLeaves
+}
+
+// checkParams validates that the proof params meet the required ones.
+func checkParams(cfg *config, proof *shared.GossipPoetProof) error {
+	if proof.SecurityParam < cfg.SecurityParam {
+		return fmt.Errorf("security param (%d) is lower than the required param (%d)", proof.SecurityParam, cfg.SecurityParam)
+	}
+	return nil
+}
+
+func printReport(r *report, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Proof:\t%s\n", r.Result)
+	_, _ = fmt.Fprintf(w, "Format:\t%s\n", r.Format)
+	if r.RoundID != "" {
+		_, _ = fmt.Fprintf(w, "Round:\t%s\n", r.RoundID)
+	}
+	if r.ServicePubKey != "" {
+		_, _ = fmt.Fprintf(w, "Service:\t%s\n", r.ServicePubKey)
+	}
+	_, _ = fmt.Fprintf(w, "Leaves:\t%d\n", r.NumLeaves)
+	_, _ = fmt.Fprintf(w, "Security param:\t%d\n", r.SecurityParam)
+	_, _ = fmt.Fprintf(w, "Members:\t%d\n", r.NumMembers)
+	_, _ = fmt.Fprintf(w, "Root:\t%s\n", r.Root)
+	_, _ = fmt.Fprintln(w, "Checks:")
+	for _, c := range r.Checks {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", c.Name, c.Status, c.Error)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"github.com/nullstyle/go-xdr/xdr3"
+	"github.com/spacemeshos/poet/hash"
+	"github.com/spacemeshos/poet/prover"
+	"github.com/spacemeshos/poet/shared"
+	"github.com/spacemeshos/poet/verifier"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+	"io/ioutil"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	req := require.New(t)
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	req.NoError(err)
+
+	members := [][]byte{[]byte("member 1"), []byte("member 2"), []byte("member 3")}
+	statement, err := verifier.MembersRoot(members)
+	req.NoError(err)
+
+	n := uint(10)
+	securityParam := uint32(8)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+	merkleProof, err := prover.GenerateProofWithoutPersistency(tempdir, hash.GenLabelHashFunc(statement), hash.GenMerkleHashFunc(statement), uint64(1)<<n, securityParam, prover.LowestMerkleMinMemoryLayer)
+	req.NoError(err)
+
+	serialize := func(roundID string, tamper bool) []byte {
+		msg := &shared.PoetProofMessage{
+			GossipPoetProof: shared.GossipPoetProof{
+				MerkleProof:   *merkleProof,
+				Members:       members,
+				NumLeaves:     uint64(1) << n,
+				SecurityParam: securityParam,
+			},
+			ServicePubKey: pubKey,
+			RoundID:       roundID,
+		}
+		req.NoError(shared.SignProofMessage(privKey, msg))
+		if tamper {
+			msg.RoundID = "forged"
+		}
+
+		var buf bytes.Buffer
+		_, err := xdr.Marshal(&buf, msg)
+		req.NoError(err)
+		return buf.Bytes()
+	}
+	valid := serialize("1", false)
+
+	tests := []struct {
+		name          string
+		data          []byte
+		securityParam uint32
+		pubKey        []byte
+		result        string
+		failedCheck   string
+	}{
+		{
+			name:          "valid message",
+			data:          valid,
+			securityParam: securityParam,
+			pubKey:        pubKey,
+			result:        resultValid,
+		},
+		{
+			name:          "unexpected service",
+			data:          valid,
+			securityParam: securityParam,
+			pubKey:        make([]byte, ed25519.PublicKeySize),
+			result:        resultInvalid,
+			failedCheck:   "service",
+		},
+		{
+			name:          "service not specified",
+			data:          valid,
+			securityParam: securityParam,
+			result:        resultUnverified,
+		},
+		{
+			name:          "bad signature",
+			data:          serialize("1", true),
+			securityParam: securityParam,
+			pubKey:        pubKey,
+			result:        resultInvalid,
+			failedCheck:   "signature",
+		},
+		{
+			name:          "security param below the minimum",
+			data:          valid,
+			securityParam: securityParam + 1,
+			pubKey:        pubKey,
+			result:        resultInvalid,
+			failedCheck:   "params",
+		},
+		{
+			name:          "malformed input",
+			data:          valid[:len(valid)/2],
+			securityParam: securityParam,
+			pubKey:        pubKey,
+			result:        resultInvalid,
+			failedCheck:   "decode",
+		},
+		{
+			name:          "empty input",
+			securityParam: securityParam,
+			pubKey:        pubKey,
+			result:        resultInvalid,
+			failedCheck:   "decode",
+		},
+	}
+
+	for _, test := range tests {
+		cfg := &config{Format: formatXDR, N: n, SecurityParam: test.securityParam}
+		r := verify(cfg, test.data, test.pubKey)
+		req.Equal(test.result, r.Result, test.name)
+		req.Equal(test.result == resultValid, r.Valid, test.name)
+
+		// Once a check fails, the following checks are not performed.
+		last := r.Checks[len(r.Checks)-1]
+		if test.failedCheck == "" {
+			req.NotEqual(statusFail, last.Status, test.name)
+			continue
+		}
+		req.Equal(test.failedCheck, last.Name, test.name)
+		req.Equal(statusFail, last.Status, test.name)
+		req.NotEmpty(last.Error, test.name)
+	}
+}
+
+func TestVerify_MalformedProtobuf(t *testing.T) {
+	req := require.New(t)
+
+	for _, format := range []string{formatProtobuf, formatJSON} {
+		r := verify(&config{Format: format}, []byte("{not a proof"), nil)
+		req.Equal(resultInvalid, r.Result, format)
+		req.Len(r.Checks, 1, format)
+		req.Equal("decode", r.Checks[0].Name, format)
+		req.Equal(statusFail, r.Checks[0].Status, format)
+	}
+}
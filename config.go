@@ -8,7 +8,9 @@ import (
 	"fmt"
 	"github.com/btcsuite/btcutil"
 	"github.com/jessevdk/go-flags"
+	"github.com/spacemeshos/poet/rpccore"
 	"github.com/spacemeshos/poet/service"
+	"github.com/spacemeshos/poet/shared"
 	"github.com/spacemeshos/smutil/log"
 	"net"
 	"os"
@@ -35,6 +37,12 @@ const (
 	defaultBroadcastAcksThreshold   = 1
 	defaultBroadcastNumRetries      = 100
 	defaultBroadcastRetriesInterval = 5 * time.Minute
+	defaultArchiveRetentionCount    = 100
+	defaultCalibrationMargin        = 0.2
+	defaultCalibrationSampleLeaves  = 1 << 18
+	defaultTLSReloadInterval        = time.Minute
+	defaultMaxChallengeSize         = 1024
+	defaultCoreMaxJobs              = 1
 )
 
 var (
@@ -44,11 +52,6 @@ var (
 	defaultLogDir     = filepath.Join(defaultPoetDir, defaultLogDirname)
 )
 
-type coreServiceConfig struct {
-	N            int  `long:"n" description:"PoET time parameter"`
-	MemoryLayers uint `long:"memory" description:"Number of top Merkle tree layers to cache in-memory"`
-}
-
 // config defines the configuration options for poet.
 //
 // See loadConfig for further details regarding the
@@ -66,13 +69,27 @@ type config struct {
 	RPCListener     net.Addr
 	RESTListener    net.Addr
 
+	NoAuth  bool `long:"noauth" description:"Disable the RPC authentication. Otherwise, the admin calls (including the core service Compute and CancelJob) must carry an admin token, which is generated into the data directory on first start, along with the submit and read tokens"`
+	AuthAll bool `long:"authall" description:"Require a token of the required scope (submit or read) for the rest of the calls as well. Otherwise, they are allowed without a token, i.e. the submit and read scopes are never enforced"`
+
+	TLSCertFile       string        `long:"tlscert" description:"Path to the TLS certificate of the RPC and REST listeners. If not specified, the listeners are insecure"`
+	TLSKeyFile        string        `long:"tlskey" description:"Path to the TLS private key of the RPC and REST listeners"`
+	TLSClientCAFile   string        `long:"tlsclientca" description:"Path to the CA certificates which clients certificates are verified against. If specified, clients must present a certificate. The listeners certificate must be valid for client authentication as well, since the REST proxy presents it"`
+	TLSReloadInterval time.Duration `long:"tlsreloadinterval" description:"Interval of checking the TLS files for rotated certificates, which are reloaded (0 to disable)"`
+
 	CPUProfile string `long:"cpuprofile" description:"Write CPU profile to the specified file"`
 	Profile    string `long:"profile" description:"Enable HTTP profiling on given port -- must be between 1024 and 65535"`
 
 	CoreServiceMode bool `long:"core" description:"Enable poet in core service mode"`
 
-	CoreService *coreServiceConfig `group:"Core Service" namespace:"core"`
-	Service     *service.Config    `group:"Service"`
+	Calibrate               bool          `long:"calibrate" description:"Benchmark the host, store the number of leaves whose execution fits the calibration target duration, and exit"`
+	CalibrationTarget       time.Duration `long:"calibration-target" description:"Target duration of a round execution (defaults to the rounds duration)"`
+	CalibrationMargin       float64       `long:"calibration-margin" description:"Fraction of the calibration target duration to leave spare"`
+	CalibrationSampleLeaves uint64        `long:"calibration-sample" description:"Number of leaves to generate while benchmarking the host"`
+	ApplyCalibration        bool          `long:"apply-calibration" description:"Set the number of leaves per the stored calibration result"`
+
+	CoreService *rpccore.Config `group:"Core Service" namespace:"core"`
+	Service     *service.Config `group:"Service"`
 }
 
 // loadConfig initializes and parses the config using a config file and command
@@ -85,14 +102,17 @@ type config struct {
 // 	4) Parse CLI options and overwrite/add any specified options
 func loadConfig() (*config, error) {
 	defaultCfg := config{
-		PoetDir:         defaultPoetDir,
-		ConfigFile:      defaultConfigFile,
-		DataDir:         defaultDataDir,
-		LogDir:          defaultLogDir,
-		MaxLogFiles:     defaultMaxLogFiles,
-		MaxLogFileSize:  defaultMaxLogFileSize,
-		RawRPCListener:  fmt.Sprintf("localhost:%d", defaultRPCPort),
-		RawRESTListener: fmt.Sprintf("localhost:%d", defaultRESTPort),
+		PoetDir:                 defaultPoetDir,
+		ConfigFile:              defaultConfigFile,
+		DataDir:                 defaultDataDir,
+		LogDir:                  defaultLogDir,
+		MaxLogFiles:             defaultMaxLogFiles,
+		MaxLogFileSize:          defaultMaxLogFileSize,
+		RawRPCListener:          fmt.Sprintf("localhost:%d", defaultRPCPort),
+		RawRESTListener:         fmt.Sprintf("localhost:%d", defaultRESTPort),
+		CalibrationMargin:       defaultCalibrationMargin,
+		CalibrationSampleLeaves: defaultCalibrationSampleLeaves,
+		TLSReloadInterval:       defaultTLSReloadInterval,
 		Service: &service.Config{
 			N:                        defaultN,
 			MemoryLayers:             defaultMemoryLayers,
@@ -102,10 +122,14 @@ func loadConfig() (*config, error) {
 			BroadcastAcksThreshold:   defaultBroadcastAcksThreshold,
 			BroadcastNumRetries:      defaultBroadcastNumRetries,
 			BroadcastRetriesInterval: defaultBroadcastRetriesInterval,
+			ArchiveRetentionCount:    defaultArchiveRetentionCount,
+			MaxChallengeSize:         defaultMaxChallengeSize,
 		},
-		CoreService: &coreServiceConfig{
-			N:            defaultN,
-			MemoryLayers: defaultMemoryLayers,
+		CoreService: &rpccore.Config{
+			N:             defaultN,
+			MemoryLayers:  defaultMemoryLayers,
+			SecurityParam: shared.T,
+			MaxJobs:       defaultCoreMaxJobs,
 		},
 	}
 
@@ -186,6 +210,9 @@ func loadConfig() (*config, error) {
 	// to use them later on.
 	cfg.DataDir = cleanAndExpandPath(cfg.DataDir)
 	cfg.LogDir = cleanAndExpandPath(cfg.LogDir)
+	cfg.TLSCertFile = cleanAndExpandPath(cfg.TLSCertFile)
+	cfg.TLSKeyFile = cleanAndExpandPath(cfg.TLSKeyFile)
+	cfg.TLSClientCAFile = cleanAndExpandPath(cfg.TLSClientCAFile)
 
 	// Ensure that the user didn't attempt to specify non-positive values
 	// for the poet n parameter
@@ -195,6 +222,19 @@ func loadConfig() (*config, error) {
 		return nil, err
 	}
 
+	// Ensure that the TLS certificate and key are specified together, and
+	// that clients certificates are verified only if TLS is enabled.
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		fmt.Fprintln(os.Stderr, usageMessage)
+		err := fmt.Errorf("%s: tlscert and tlskey must be specified together", funcName)
+		return nil, err
+	}
+	if cfg.TLSClientCAFile != "" && cfg.TLSCertFile == "" {
+		fmt.Fprintln(os.Stderr, usageMessage)
+		err := fmt.Errorf("%s: tlsclientca requires tlscert and tlskey", funcName)
+		return nil, err
+	}
+
 	// Resolve the RPC listener
 	addr, err := net.ResolveTCPAddr("tcp", cfg.RawRPCListener)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"github.com/spacemeshos/poet/auth"
 	"github.com/spacemeshos/poet/rpc/api"
 	"google.golang.org/grpc"
 	"io"
@@ -45,7 +46,7 @@ func NewHarness(cfg *ServerConfig) (*Harness, error) {
 
 	// Verify the client connectivity.
 	// If failed, shutdown the server.
-	conn, err := connectClient(cfg.rpcListen)
+	conn, err := connectClient(cfg.rpcListen, cfg.dataDir)
 	if err != nil {
 		_ = server.shutdown(true)
 		return nil, err
@@ -96,12 +97,13 @@ func (h *Harness) RESTListen() string {
 }
 
 // connectClient attempts to establish a gRPC Client connection
-// to the provided target.
-func connectClient(target string) (*grpc.ClientConn, error) {
+// to the provided target, whose calls carry the admin token from the server data directory.
+func connectClient(target string, dataDir string) (*grpc.ClientConn, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	opts := []grpc.DialOption{
 		grpc.WithInsecure(),
 		grpc.WithBlock(),
+		grpc.WithPerRPCCredentials(adminCredentials(dataDir)),
 	}
 	defer cancel()
 
@@ -113,6 +115,22 @@ func connectClient(target string) (*grpc.ClientConn, error) {
 	return conn, nil
 }
 
+// adminCredentials attach the admin token from a server data directory to each call.
+// The token is read on each call, since it's generated by the server on its first start.
+type adminCredentials string
+
+func (c adminCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := auth.ReadToken(string(c), auth.ScopeAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return auth.TokenCredentials(token).GetRequestMetadata(ctx, uri...)
+}
+
+func (c adminCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
 // baseDir is the directory path of the temp directory for all the harness files.
 func baseDir() (string, error) {
 	baseDir := filepath.Join(os.TempDir(), "poet")
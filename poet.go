@@ -68,6 +68,16 @@ func poetMain() error {
 		defer pprof.StopCPUProfile()
 	}
 
+	if cfg.Calibrate {
+		return calibrate()
+	}
+
+	if !cfg.CoreServiceMode {
+		if err := applyCalibration(); err != nil {
+			return err
+		}
+	}
+
 	if err := startServer(); err != nil {
 		log.Error("failed to start server: %v", err)
 		return err
@@ -48,7 +48,7 @@ func TestHarness(t *testing.T) {
 
 	ctx := context.Background()
 	_, err = h.Submit(ctx, &api.SubmitRequest{Challenge: []byte("this is a commitment")})
-	assert.EqualError(err, "rpc error: code = Unknown desc = service not started")
+	assert.EqualError(err, "rpc error: code = FailedPrecondition desc = service not started")
 
 	_, err = h.Start(ctx, &api.StartRequest{GatewayAddresses: []string{"666"}})
 	assert.EqualError(err, "rpc error: code = Unknown desc = failed to connect to Spacemesh gateway node at \"666\": failed to connect to rpc server: context deadline exceeded")
@@ -57,7 +57,7 @@ func TestHarness(t *testing.T) {
 	assert.NoError(err)
 
 	_, err = h.Start(ctx, &api.StartRequest{DisableBroadcast: true})
-	assert.EqualError(err, "rpc error: code = Unknown desc = already started")
+	assert.EqualError(err, "rpc error: code = FailedPrecondition desc = already started")
 
 	for _, testCase := range testCases {
 		success := t.Run(testCase.name, func(t1 *testing.T) {
@@ -0,0 +1,60 @@
+package prover
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"github.com/spacemeshos/poet/hash"
+	"github.com/spacemeshos/poet/shared"
+	"time"
+)
+
+// Benchmark is the measured throughput of the PoET computation on the host.
+type Benchmark struct {
+	// LabelHashRate is the number of label hashes per second.
+	LabelHashRate float64
+
+	// LeavesRate is the number of leaves per second of the proof generation,
+	// including the labels derivation and the Merkle tree construction.
+	LeavesRate float64
+}
+
+// RunBenchmark measures the throughput of the label hash function and of the proof generation, by hashing and then
+// generating a proof with a given number of leaves. The proof generation Merkle tree cache files are written to datadir.
+func RunBenchmark(datadir string, numLeaves uint64, minMemoryLayer uint) (*Benchmark, error) {
+	if numLeaves == 0 {
+		return nil, errors.New("number of leaves must be positive")
+	}
+
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	labelHashFunc := hash.GenLabelHashFunc(challenge)
+	merkleHashFunc := hash.GenMerkleHashFunc(challenge)
+
+	data := make([]byte, 8)
+	start := time.Now()
+	for i := uint64(0); i < numLeaves; i++ {
+		binary.BigEndian.PutUint64(data, i)
+		labelHashFunc(data)
+	}
+	labelHashElapsed := time.Since(start)
+
+	tree, treeCache, err := makeProofTree(datadir, merkleHashFunc, minMemoryLayer)
+	if err != nil {
+		return nil, err
+	}
+
+	start = time.Now()
+	if _, err := generateProof(context.Background(), sig, labelHashFunc, merkleHashFunc, tree, treeCache, numLeaves, 0, shared.T, persist, progress); err != nil {
+		return nil, err
+	}
+	proofElapsed := time.Since(start)
+
+	return &Benchmark{
+		LabelHashRate: float64(numLeaves) / labelHashElapsed.Seconds(),
+		LeavesRate:    float64(numLeaves) / proofElapsed.Seconds(),
+	}, nil
+}
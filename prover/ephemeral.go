@@ -0,0 +1,106 @@
+package prover
+
+import (
+	"github.com/spacemeshos/merkle-tree"
+	"io"
+)
+
+// ephemeralNodes returns the nodes of an unbalanced tree (num. of leaves is not a power of 2) which are missing their
+// right subtree, by their layer height. These nodes aren't cached, since they are calculated on-the-fly with padding
+// when deriving the root.
+func ephemeralNodes(tree *merkle.Tree, merkleHashFunc func(lChild, rChild []byte) []byte) map[uint][]byte {
+	padding := make([]byte, merkle.NodeSize)
+	parkedNodes := tree.GetParkedNodes()
+	nodes := make(map[uint][]byte)
+
+	var ephemeralNode []byte
+	for height := 0; height < len(parkedNodes)-1; height++ {
+		parking := parkedNodes[height]
+		switch {
+		case parking != nil && ephemeralNode != nil:
+			ephemeralNode = merkleHashFunc(parking, ephemeralNode)
+		case parking != nil:
+			ephemeralNode = merkleHashFunc(parking, padding)
+		case ephemeralNode != nil:
+			ephemeralNode = merkleHashFunc(ephemeralNode, padding)
+		default:
+			continue
+		}
+		nodes[uint(height+1)] = ephemeralNode
+	}
+
+	return nodes
+}
+
+// ephemeralCacheReader is a cache reader which extends each cached layer with its ephemeral node, if it has one.
+// Without them, the proof generation would consider these nodes as padding.
+type ephemeralCacheReader struct {
+	merkle.CacheReader
+	nodes map[uint][]byte
+}
+
+func newEphemeralCacheReader(cacheReader merkle.CacheReader, nodes map[uint][]byte) merkle.CacheReader {
+	if len(nodes) == 0 {
+		return cacheReader
+	}
+	return &ephemeralCacheReader{cacheReader, nodes}
+}
+
+func (c *ephemeralCacheReader) GetLayerReader(layerHeight uint) merkle.LayerReader {
+	reader := c.CacheReader.GetLayerReader(layerHeight)
+	node, ok := c.nodes[layerHeight]
+	if reader == nil || !ok {
+		return reader
+	}
+	return &ephemeralLayerReader{LayerReader: reader, node: node}
+}
+
+// ephemeralLayerReader is a layer reader which reads a given ephemeral node after the end of the layer.
+type ephemeralLayerReader struct {
+	merkle.LayerReader
+	node     []byte
+	atNode   bool
+	nodeRead bool
+}
+
+func (r *ephemeralLayerReader) Seek(index uint64) error {
+	width, err := r.LayerReader.Width()
+	if err != nil {
+		return err
+	}
+
+	r.nodeRead = false
+	r.atNode = index == width
+	if r.atNode {
+		return nil
+	}
+	if index > width {
+		return io.EOF
+	}
+	return r.LayerReader.Seek(index)
+}
+
+func (r *ephemeralLayerReader) ReadNext() ([]byte, error) {
+	if !r.atNode {
+		value, err := r.LayerReader.ReadNext()
+		if err != io.EOF {
+			return value, err
+		}
+	}
+
+	if r.nodeRead {
+		return nil, io.EOF
+	}
+	r.nodeRead = true
+	value := make([]byte, len(r.node))
+	copy(value, r.node)
+	return value, nil
+}
+
+func (r *ephemeralLayerReader) Width() (uint64, error) {
+	width, err := r.LayerReader.Width()
+	if err != nil {
+		return 0, err
+	}
+	return width + 1, nil
+}
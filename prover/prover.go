@@ -1,6 +1,7 @@
 package prover
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/spacemeshos/merkle-tree"
@@ -9,11 +10,13 @@ import (
 	"github.com/spacemeshos/poet/signal"
 	"github.com/spacemeshos/smutil/log"
 	"io/ioutil"
+	"math/bits"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -26,6 +29,9 @@ const (
 	// The rate, in leaves, in which the proof generation state snapshot will be saved to disk
 	// to allow potential crash recovery.
 	hardShutdownCheckpointRate = 1 << 24
+
+	// The rate, in leaves, in which the proof generation progress will be reported.
+	progressReportRate = 1 << 14
 )
 
 var (
@@ -34,9 +40,27 @@ var (
 
 type persistFunc func(tree *merkle.Tree, treeCache *cache.Writer, nextLeafId uint64) error
 
+// Progress describes the progress of a proof generation.
+type Progress struct {
+	// LeavesDone is the number of leaves which were generated.
+	LeavesDone uint64
+
+	// NumLeaves is the total number of leaves to generate.
+	NumLeaves uint64
+
+	// Rate is the number of leaves generated per second, since the proof generation (re)started.
+	Rate float64
+
+	// EstimatedCompletion is the estimated time in which all leaves will be generated, according to Rate.
+	EstimatedCompletion time.Time
+}
+
+type progressFunc func(progress Progress)
+
 var (
-	sig                 = signal.NewSignal()
-	persist persistFunc = func(tree *merkle.Tree, treeCache *cache.Writer, nextLeafId uint64) error { return nil }
+	sig                   = signal.NewSignal()
+	persist  persistFunc  = func(tree *merkle.Tree, treeCache *cache.Writer, nextLeafId uint64) error { return nil }
+	progress progressFunc = func(progress Progress) {}
 )
 
 // GenerateProof computes the PoET DAG, uses Fiat-Shamir to derive a challenge from the Merkle root and generates a Merkle
@@ -47,16 +71,33 @@ func GenerateProof(
 	labelHashFunc func(data []byte) []byte,
 	merkleHashFunc func(lChild, rChild []byte) []byte,
 	numLeaves uint64,
-	securityParam uint8,
+	securityParam uint32,
 	minMemoryLayer uint,
 	persist persistFunc,
+	progress progressFunc,
+) (*shared.MerkleProof, error) {
+	return GenerateProofContext(context.Background(), sig, datadir, labelHashFunc, merkleHashFunc, numLeaves, securityParam, minMemoryLayer, persist, progress)
+}
+
+// GenerateProofContext is GenerateProof, which also stops once ctx is done, returning ctx.Err().
+func GenerateProofContext(
+	ctx context.Context,
+	sig *signal.Signal,
+	datadir string,
+	labelHashFunc func(data []byte) []byte,
+	merkleHashFunc func(lChild, rChild []byte) []byte,
+	numLeaves uint64,
+	securityParam uint32,
+	minMemoryLayer uint,
+	persist persistFunc,
+	progress progressFunc,
 ) (*shared.MerkleProof, error) {
 	tree, treeCache, err := makeProofTree(datadir, merkleHashFunc, minMemoryLayer)
 	if err != nil {
 		return nil, err
 	}
 
-	return generateProof(sig, labelHashFunc, tree, treeCache, numLeaves, 0, securityParam, persist)
+	return generateProof(ctx, sig, labelHashFunc, merkleHashFunc, tree, treeCache, numLeaves, 0, securityParam, persist, progress)
 }
 
 // GenerateProofRecovery recovers proof generation, from a given 'nextLeafID' and for a given 'parkedNodes' snapshot.
@@ -66,30 +107,43 @@ func GenerateProofRecovery(
 	labelHashFunc func(data []byte) []byte,
 	merkleHashFunc func(lChild, rChild []byte) []byte,
 	numLeaves uint64,
-	securityParam uint8,
+	securityParam uint32,
 	nextLeafID uint64,
 	parkedNodes [][]byte,
 	persist persistFunc,
+	progress progressFunc,
 ) (*shared.MerkleProof, error) {
 	treeCache, tree, err := makeRecoveryProofTree(datadir, merkleHashFunc, nextLeafID, parkedNodes)
 	if err != nil {
 		return nil, err
 	}
 
-	return generateProof(sig, labelHashFunc, tree, treeCache, numLeaves, nextLeafID, securityParam, persist)
+	return generateProof(context.Background(), sig, labelHashFunc, merkleHashFunc, tree, treeCache, numLeaves, nextLeafID, securityParam, persist, progress)
 }
 
 // GenerateProofWithoutPersistency calls GenerateProof with disabled persistency functionality
-// and potential soft/hard-shutdown recovery.
+// and potential soft/hard-shutdown recovery, and without progress reporting.
 func GenerateProofWithoutPersistency(
 	datadir string,
 	labelHashFunc func(data []byte) []byte,
 	merkleHashFunc func(lChild, rChild []byte) []byte,
 	numLeaves uint64,
-	securityParam uint8,
+	securityParam uint32,
 	minMemoryLayer uint,
 ) (*shared.MerkleProof, error) {
-	return GenerateProof(sig, datadir, labelHashFunc, merkleHashFunc, numLeaves, securityParam, minMemoryLayer, persist)
+	return GenerateProof(sig, datadir, labelHashFunc, merkleHashFunc, numLeaves, securityParam, minMemoryLayer, persist, progress)
+}
+
+// MinMemoryLayer returns the lowest Merkle tree layer to cache in-memory, so that the top memoryLayers layers
+// of a tree with a given number of leaves are cached in-memory.
+func MinMemoryLayer(numLeaves uint64, memoryLayers uint) uint {
+	// The tree height is derived from the number of leaves, rounded up to a power of 2.
+	height := bits.Len64(numLeaves - 1)
+	layer := height - int(memoryLayers)
+	if layer < LowestMerkleMinMemoryLayer {
+		layer = LowestMerkleMinMemoryLayer
+	}
+	return uint(layer)
 }
 
 func makeProofTree(
@@ -186,18 +240,32 @@ func makeRecoveryProofTree(
 }
 
 func generateProof(
+	ctx context.Context,
 	sig *signal.Signal,
 	labelHashFunc func(data []byte) []byte,
+	merkleHashFunc func(lChild, rChild []byte) []byte,
 	tree *merkle.Tree,
 	treeCache *cache.Writer,
 	numLeaves uint64,
 	nextLeafID uint64,
-	securityParam uint8,
+	securityParam uint32,
 	persist persistFunc,
+	progress progressFunc,
 ) (*shared.MerkleProof, error) {
 	unblock := sig.BlockShutdown()
 	defer unblock()
 
+	start := time.Now()
+	reportProgress := func(leavesDone uint64) {
+		p := Progress{LeavesDone: leavesDone, NumLeaves: numLeaves}
+		if elapsed := time.Since(start); elapsed > 0 && leavesDone > nextLeafID {
+			p.Rate = float64(leavesDone-nextLeafID) / elapsed.Seconds()
+			remaining := time.Duration(float64(numLeaves-leavesDone) / p.Rate * float64(time.Second))
+			p.EstimatedCompletion = time.Now().Add(remaining)
+		}
+		progress(p)
+	}
+
 	makeLabel := shared.MakeLabelFunc()
 	for leafID := nextLeafID; leafID < numLeaves; leafID++ {
 		// Handle persistence.
@@ -206,10 +274,23 @@ func generateProof(
 				return nil, err
 			}
 			return nil, ErrShutdownRequested
-		} else if leafID != 0 && leafID%hardShutdownCheckpointRate == 0 {
+		}
+		select {
+		case <-ctx.Done():
 			if err := persist(tree, treeCache, leafID); err != nil {
 				return nil, err
 			}
+			return nil, ctx.Err()
+		default:
+		}
+		if leafID != 0 && leafID%hardShutdownCheckpointRate == 0 {
+			if err := persist(tree, treeCache, leafID); err != nil {
+				return nil, err
+			}
+		}
+
+		if leafID != nextLeafID && leafID%progressReportRate == 0 {
+			reportProgress(leafID)
 		}
 
 		// Generate the next leaf.
@@ -218,6 +299,7 @@ func generateProof(
 			return nil, err
 		}
 	}
+	reportProgress(numLeaves)
 
 	log.Info("Merkle tree construction finished, generating proof...")
 
@@ -227,6 +309,7 @@ func generateProof(
 	if err != nil {
 		return nil, err
 	}
+	cacheReader = newEphemeralCacheReader(cacheReader, ephemeralNodes(tree, merkleHashFunc))
 	provenLeafIndices := shared.FiatShamir(root, numLeaves, securityParam)
 	_, provenLeaves, proofNodes, err := merkle.GenerateProof(provenLeafIndices, cacheReader)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/spacemeshos/poet/hash"
 	"github.com/spacemeshos/poet/shared"
+	"github.com/spacemeshos/poet/signal"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
 	"testing"
@@ -21,6 +22,32 @@ func TestGetProof(t *testing.T) {
 	fmt.Printf("proof: %x\n", merkleProof.ProvenLeaves)
 }
 
+func TestGenerateProof_Progress(t *testing.T) {
+	r := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	challenge := []byte("challenge this")
+	numLeaves := uint64(progressReportRate * 3)
+
+	var reports []Progress
+	progress := func(p Progress) { reports = append(reports, p) }
+
+	_, err := GenerateProof(signal.NewSignal(), tempdir, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, 5, LowestMerkleMinMemoryLayer, persist, progress)
+	r.NoError(err)
+
+	r.Len(reports, 3)
+	for i, p := range reports {
+		r.Equal(numLeaves, p.NumLeaves)
+		r.True(p.Rate > 0)
+		r.False(p.EstimatedCompletion.IsZero())
+		if i > 0 {
+			r.True(p.LeavesDone > reports[i-1].LeavesDone)
+		}
+	}
+	r.Equal(uint64(progressReportRate), reports[0].LeavesDone)
+	r.Equal(numLeaves, reports[len(reports)-1].LeavesDone)
+}
+
 func BenchmarkGetProof(b *testing.B) {
 	r := require.New(b)
 	tempdir, _ := ioutil.TempDir("", "poet-test")
@@ -5,6 +5,7 @@ import (
 	"github.com/spacemeshos/merkle-tree/cache"
 	"github.com/spacemeshos/merkle-tree/cache/readwriters"
 	"github.com/spacemeshos/smutil/log"
+	"io"
 	"os"
 	"path/filepath"
 )
@@ -44,7 +45,7 @@ func (mf *ReadWriterMetaFactory) GetFactory() cache.LayerFactory {
 			}
 
 			mf.filesCreated[fileName] = true
-			return readWriter, nil
+			return &fileReadWriter{readWriter}, nil
 		}
 		return &readwriters.SliceReadWriter{}, nil
 	}
@@ -66,3 +67,20 @@ func (mf *ReadWriterMetaFactory) Cleanup() {
 func (mf *ReadWriterMetaFactory) makeFileName(layer uint) (string, error) {
 	return filepath.Join(mf.datadir, fmt.Sprintf("layercache_%d.bin", layer)), nil
 }
+
+// fileReadWriter is a file read-writer which returns io.EOF when seeking beyond the end of the layer, similarly to
+// the slice read-writer. Merkle proofs generation relies on it for padding unbalanced trees.
+type fileReadWriter struct {
+	*readwriters.FileReadWriter
+}
+
+func (rw *fileReadWriter) Seek(index uint64) error {
+	width, err := rw.Width()
+	if err != nil {
+		return err
+	}
+	if index >= width {
+		return io.EOF
+	}
+	return rw.FileReadWriter.Seek(index)
+}
@@ -25,14 +25,78 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type RoundPhase int32
+
+const (
+	RoundPhase_OPEN        RoundPhase = 0
+	RoundPhase_EXECUTING   RoundPhase = 1
+	RoundPhase_EXECUTED    RoundPhase = 2
+	RoundPhase_BROADCASTED RoundPhase = 3
+)
+
+var RoundPhase_name = map[int32]string{
+	0: "OPEN",
+	1: "EXECUTING",
+	2: "EXECUTED",
+	3: "BROADCASTED",
+}
+
+var RoundPhase_value = map[string]int32{
+	"OPEN":        0,
+	"EXECUTING":   1,
+	"EXECUTED":    2,
+	"BROADCASTED": 3,
+}
+
+func (x RoundPhase) String() string {
+	return proto.EnumName(RoundPhase_name, int32(x))
+}
+
+func (RoundPhase) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0}
+}
+
+type EventType int32
+
+const (
+	EventType_ROUND_OPENED            EventType = 0
+	EventType_ROUND_EXECUTION_STARTED EventType = 1
+	EventType_ROUND_EXECUTION_ENDED   EventType = 2
+	EventType_ROUND_BROADCASTED       EventType = 3
+)
+
+var EventType_name = map[int32]string{
+	0: "ROUND_OPENED",
+	1: "ROUND_EXECUTION_STARTED",
+	2: "ROUND_EXECUTION_ENDED",
+	3: "ROUND_BROADCASTED",
+}
+
+var EventType_value = map[string]int32{
+	"ROUND_OPENED":            0,
+	"ROUND_EXECUTION_STARTED": 1,
+	"ROUND_EXECUTION_ENDED":   2,
+	"ROUND_BROADCASTED":       3,
+}
+
+func (x EventType) String() string {
+	return proto.EnumName(EventType_name, int32(x))
+}
+
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{1}
+}
+
 type StartRequest struct {
 	GatewayAddresses       []string `protobuf:"bytes,1,rep,name=gatewayAddresses,proto3" json:"gatewayAddresses,omitempty"`
 	DisableBroadcast       bool     `protobuf:"varint,2,opt,name=disableBroadcast,proto3" json:"disableBroadcast,omitempty"`
 	ConnAcksThreshold      int32    `protobuf:"varint,3,opt,name=connAcksThreshold,proto3" json:"connAcksThreshold,omitempty"`
 	BroadcastAcksThreshold int32    `protobuf:"varint,4,opt,name=broadcastAcksThreshold,proto3" json:"broadcastAcksThreshold,omitempty"`
-	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
-	XXX_unrecognized       []byte   `json:"-"`
-	XXX_sizecache          int32    `json:"-"`
+	// gatewayTls are the TLS configs of the gateway nodes connections. Gateway nodes without one are connected insecurely.
+	GatewayTls           []*GatewayTLS `protobuf:"bytes,5,rep,name=gatewayTls,proto3" json:"gatewayTls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *StartRequest) Reset()         { *m = StartRequest{} }
@@ -88,6 +152,13 @@ func (m *StartRequest) GetBroadcastAcksThreshold() int32 {
 	return 0
 }
 
+func (m *StartRequest) GetGatewayTls() []*GatewayTLS {
+	if m != nil {
+		return m.GatewayTls
+	}
+	return nil
+}
+
 type StartResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -124,9 +195,11 @@ type UpdateGatewayRequest struct {
 	DisableBroadcast       bool     `protobuf:"varint,2,opt,name=disableBroadcast,proto3" json:"disableBroadcast,omitempty"`
 	ConnAcksThreshold      int32    `protobuf:"varint,3,opt,name=connAcksThreshold,proto3" json:"connAcksThreshold,omitempty"`
 	BroadcastAcksThreshold int32    `protobuf:"varint,4,opt,name=broadcastAcksThreshold,proto3" json:"broadcastAcksThreshold,omitempty"`
-	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
-	XXX_unrecognized       []byte   `json:"-"`
-	XXX_sizecache          int32    `json:"-"`
+	// gatewayTls are the TLS configs of the gateway nodes connections. Gateway nodes without one are connected insecurely.
+	GatewayTls           []*GatewayTLS `protobuf:"bytes,5,rep,name=gatewayTls,proto3" json:"gatewayTls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *UpdateGatewayRequest) Reset()         { *m = UpdateGatewayRequest{} }
@@ -182,6 +255,13 @@ func (m *UpdateGatewayRequest) GetBroadcastAcksThreshold() int32 {
 	return 0
 }
 
+func (m *UpdateGatewayRequest) GetGatewayTls() []*GatewayTLS {
+	if m != nil {
+		return m.GatewayTls
+	}
+	return nil
+}
+
 type UpdateGatewayResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -213,8 +293,90 @@ func (m *UpdateGatewayResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpdateGatewayResponse proto.InternalMessageInfo
 
+// GatewayTLS is the TLS config of a gateway node connection. The files are PEM files on the service host.
+// If caCertFile isn't specified, the system CA certificates are used. certFile and keyFile are the client
+// certificate for mutual TLS. serverName overrides the name which the gateway node certificate is verified against.
+type GatewayTLS struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	CaCertFile           string   `protobuf:"bytes,2,opt,name=caCertFile,proto3" json:"caCertFile,omitempty"`
+	CertFile             string   `protobuf:"bytes,3,opt,name=certFile,proto3" json:"certFile,omitempty"`
+	KeyFile              string   `protobuf:"bytes,4,opt,name=keyFile,proto3" json:"keyFile,omitempty"`
+	ServerName           string   `protobuf:"bytes,5,opt,name=serverName,proto3" json:"serverName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GatewayTLS) Reset()         { *m = GatewayTLS{} }
+func (m *GatewayTLS) String() string { return proto.CompactTextString(m) }
+func (*GatewayTLS) ProtoMessage()    {}
+func (*GatewayTLS) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+}
+
+func (m *GatewayTLS) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GatewayTLS.Unmarshal(m, b)
+}
+func (m *GatewayTLS) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GatewayTLS.Marshal(b, m, deterministic)
+}
+func (m *GatewayTLS) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GatewayTLS.Merge(m, src)
+}
+func (m *GatewayTLS) XXX_Size() int {
+	return xxx_messageInfo_GatewayTLS.Size(m)
+}
+func (m *GatewayTLS) XXX_DiscardUnknown() {
+	xxx_messageInfo_GatewayTLS.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GatewayTLS proto.InternalMessageInfo
+
+func (m *GatewayTLS) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *GatewayTLS) GetCaCertFile() string {
+	if m != nil {
+		return m.CaCertFile
+	}
+	return ""
+}
+
+func (m *GatewayTLS) GetCertFile() string {
+	if m != nil {
+		return m.CertFile
+	}
+	return ""
+}
+
+func (m *GatewayTLS) GetKeyFile() string {
+	if m != nil {
+		return m.KeyFile
+	}
+	return ""
+}
+
+func (m *GatewayTLS) GetServerName() string {
+	if m != nil {
+		return m.ServerName
+	}
+	return ""
+}
+
 type SubmitRequest struct {
-	Challenge            []byte   `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Challenge []byte `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	// pubKey is the ed25519 public key of the node identity which signed the submission,
+	// or empty if the submission is unsigned.
+	PubKey []byte `protobuf:"bytes,2,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	// roundId is the open round which the submission is signed for.
+	RoundId string `protobuf:"bytes,3,opt,name=roundId,proto3" json:"roundId,omitempty"`
+	// signature is the signature of the node identity over the submission message:
+	// "poet-submission", the roundId length as a 4 bytes big-endian integer, the roundId and the challenge.
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -224,7 +386,7 @@ func (m *SubmitRequest) Reset()         { *m = SubmitRequest{} }
 func (m *SubmitRequest) String() string { return proto.CompactTextString(m) }
 func (*SubmitRequest) ProtoMessage()    {}
 func (*SubmitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
 }
 
 func (m *SubmitRequest) XXX_Unmarshal(b []byte) error {
@@ -252,6 +414,27 @@ func (m *SubmitRequest) GetChallenge() []byte {
 	return nil
 }
 
+func (m *SubmitRequest) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *SubmitRequest) GetRoundId() string {
+	if m != nil {
+		return m.RoundId
+	}
+	return ""
+}
+
+func (m *SubmitRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 type SubmitResponse struct {
 	RoundId              string   `protobuf:"bytes,1,opt,name=roundId,proto3" json:"roundId,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -263,7 +446,7 @@ func (m *SubmitResponse) Reset()         { *m = SubmitResponse{} }
 func (m *SubmitResponse) String() string { return proto.CompactTextString(m) }
 func (*SubmitResponse) ProtoMessage()    {}
 func (*SubmitResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
 }
 
 func (m *SubmitResponse) XXX_Unmarshal(b []byte) error {
@@ -301,7 +484,7 @@ func (m *GetInfoRequest) Reset()         { *m = GetInfoRequest{} }
 func (m *GetInfoRequest) String() string { return proto.CompactTextString(m) }
 func (*GetInfoRequest) ProtoMessage()    {}
 func (*GetInfoRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
 }
 
 func (m *GetInfoRequest) XXX_Unmarshal(b []byte) error {
@@ -323,19 +506,20 @@ func (m *GetInfoRequest) XXX_DiscardUnknown() {
 var xxx_messageInfo_GetInfoRequest proto.InternalMessageInfo
 
 type GetInfoResponse struct {
-	OpenRoundId          string   `protobuf:"bytes,1,opt,name=openRoundId,proto3" json:"openRoundId,omitempty"`
-	ExecutingRoundsIds   []string `protobuf:"bytes,2,rep,name=executingRoundsIds,proto3" json:"executingRoundsIds,omitempty"`
-	ServicePubKey        []byte   `protobuf:"bytes,3,opt,name=servicePubKey,proto3" json:"servicePubKey,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	OpenRoundId             string               `protobuf:"bytes,1,opt,name=openRoundId,proto3" json:"openRoundId,omitempty"`
+	ExecutingRoundsIds      []string             `protobuf:"bytes,2,rep,name=executingRoundsIds,proto3" json:"executingRoundsIds,omitempty"`
+	ServicePubKey           []byte               `protobuf:"bytes,3,opt,name=servicePubKey,proto3" json:"servicePubKey,omitempty"`
+	ExecutingRoundsProgress []*ExecutionProgress `protobuf:"bytes,4,rep,name=executingRoundsProgress,proto3" json:"executingRoundsProgress,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{}             `json:"-"`
+	XXX_unrecognized        []byte               `json:"-"`
+	XXX_sizecache           int32                `json:"-"`
 }
 
 func (m *GetInfoResponse) Reset()         { *m = GetInfoResponse{} }
 func (m *GetInfoResponse) String() string { return proto.CompactTextString(m) }
 func (*GetInfoResponse) ProtoMessage()    {}
 func (*GetInfoResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
 }
 
 func (m *GetInfoResponse) XXX_Unmarshal(b []byte) error {
@@ -377,216 +561,1304 @@ func (m *GetInfoResponse) GetServicePubKey() []byte {
 	return nil
 }
 
-type MembershipProof struct {
-	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Root                 []byte   `protobuf:"bytes,2,opt,name=root,proto3" json:"root,omitempty"`
-	Proof                [][]byte `protobuf:"bytes,3,rep,name=proof,proto3" json:"proof,omitempty"`
+func (m *GetInfoResponse) GetExecutingRoundsProgress() []*ExecutionProgress {
+	if m != nil {
+		return m.ExecutingRoundsProgress
+	}
+	return nil
+}
+
+// ExecutionProgress describes the progress of an executing round proof generation.
+// rate is the number of leaves generated per second, and estimatedCompletion is in unix seconds.
+type ExecutionProgress struct {
+	RoundId              string   `protobuf:"bytes,1,opt,name=roundId,proto3" json:"roundId,omitempty"`
+	LeavesDone           uint64   `protobuf:"varint,2,opt,name=leavesDone,proto3" json:"leavesDone,omitempty"`
+	NumLeaves            uint64   `protobuf:"varint,3,opt,name=numLeaves,proto3" json:"numLeaves,omitempty"`
+	Rate                 float64  `protobuf:"fixed64,4,opt,name=rate,proto3" json:"rate,omitempty"`
+	EstimatedCompletion  int64    `protobuf:"varint,5,opt,name=estimatedCompletion,proto3" json:"estimatedCompletion,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *MembershipProof) Reset()         { *m = MembershipProof{} }
-func (m *MembershipProof) String() string { return proto.CompactTextString(m) }
-func (*MembershipProof) ProtoMessage()    {}
-func (*MembershipProof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
+func (m *ExecutionProgress) Reset()         { *m = ExecutionProgress{} }
+func (m *ExecutionProgress) String() string { return proto.CompactTextString(m) }
+func (*ExecutionProgress) ProtoMessage()    {}
+func (*ExecutionProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
 }
 
-func (m *MembershipProof) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MembershipProof.Unmarshal(m, b)
+func (m *ExecutionProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecutionProgress.Unmarshal(m, b)
 }
-func (m *MembershipProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MembershipProof.Marshal(b, m, deterministic)
+func (m *ExecutionProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecutionProgress.Marshal(b, m, deterministic)
 }
-func (m *MembershipProof) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MembershipProof.Merge(m, src)
+func (m *ExecutionProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecutionProgress.Merge(m, src)
 }
-func (m *MembershipProof) XXX_Size() int {
-	return xxx_messageInfo_MembershipProof.Size(m)
+func (m *ExecutionProgress) XXX_Size() int {
+	return xxx_messageInfo_ExecutionProgress.Size(m)
 }
-func (m *MembershipProof) XXX_DiscardUnknown() {
-	xxx_messageInfo_MembershipProof.DiscardUnknown(m)
+func (m *ExecutionProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecutionProgress.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_MembershipProof proto.InternalMessageInfo
+var xxx_messageInfo_ExecutionProgress proto.InternalMessageInfo
 
-func (m *MembershipProof) GetIndex() int32 {
+func (m *ExecutionProgress) GetRoundId() string {
 	if m != nil {
-		return m.Index
+		return m.RoundId
+	}
+	return ""
+}
+
+func (m *ExecutionProgress) GetLeavesDone() uint64 {
+	if m != nil {
+		return m.LeavesDone
 	}
 	return 0
 }
 
-func (m *MembershipProof) GetRoot() []byte {
+func (m *ExecutionProgress) GetNumLeaves() uint64 {
 	if m != nil {
-		return m.Root
+		return m.NumLeaves
 	}
-	return nil
+	return 0
 }
 
-func (m *MembershipProof) GetProof() [][]byte {
+func (m *ExecutionProgress) GetRate() float64 {
 	if m != nil {
-		return m.Proof
+		return m.Rate
 	}
-	return nil
+	return 0
 }
 
-type PoetProof struct {
-	Phi                  []byte   `protobuf:"bytes,1,opt,name=phi,proto3" json:"phi,omitempty"`
-	ProvenLeaves         [][]byte `protobuf:"bytes,2,rep,name=provenLeaves,proto3" json:"provenLeaves,omitempty"`
-	ProofNodes           [][]byte `protobuf:"bytes,3,rep,name=proofNodes,proto3" json:"proofNodes,omitempty"`
+func (m *ExecutionProgress) GetEstimatedCompletion() int64 {
+	if m != nil {
+		return m.EstimatedCompletion
+	}
+	return 0
+}
+
+type GetMembershipProofRequest struct {
+	RoundId              string   `protobuf:"bytes,1,opt,name=roundId,proto3" json:"roundId,omitempty"`
+	Challenge            []byte   `protobuf:"bytes,2,opt,name=challenge,proto3" json:"challenge,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PoetProof) Reset()         { *m = PoetProof{} }
-func (m *PoetProof) String() string { return proto.CompactTextString(m) }
-func (*PoetProof) ProtoMessage()    {}
-func (*PoetProof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
+func (m *GetMembershipProofRequest) Reset()         { *m = GetMembershipProofRequest{} }
+func (m *GetMembershipProofRequest) String() string { return proto.CompactTextString(m) }
+func (*GetMembershipProofRequest) ProtoMessage()    {}
+func (*GetMembershipProofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{10}
 }
 
-func (m *PoetProof) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PoetProof.Unmarshal(m, b)
+func (m *GetMembershipProofRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMembershipProofRequest.Unmarshal(m, b)
 }
-func (m *PoetProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PoetProof.Marshal(b, m, deterministic)
+func (m *GetMembershipProofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMembershipProofRequest.Marshal(b, m, deterministic)
 }
-func (m *PoetProof) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PoetProof.Merge(m, src)
+func (m *GetMembershipProofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMembershipProofRequest.Merge(m, src)
 }
-func (m *PoetProof) XXX_Size() int {
-	return xxx_messageInfo_PoetProof.Size(m)
+func (m *GetMembershipProofRequest) XXX_Size() int {
+	return xxx_messageInfo_GetMembershipProofRequest.Size(m)
 }
-func (m *PoetProof) XXX_DiscardUnknown() {
-	xxx_messageInfo_PoetProof.DiscardUnknown(m)
+func (m *GetMembershipProofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMembershipProofRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PoetProof proto.InternalMessageInfo
+var xxx_messageInfo_GetMembershipProofRequest proto.InternalMessageInfo
 
-func (m *PoetProof) GetPhi() []byte {
+func (m *GetMembershipProofRequest) GetRoundId() string {
 	if m != nil {
-		return m.Phi
+		return m.RoundId
 	}
-	return nil
+	return ""
 }
 
-func (m *PoetProof) GetProvenLeaves() [][]byte {
+func (m *GetMembershipProofRequest) GetChallenge() []byte {
 	if m != nil {
-		return m.ProvenLeaves
+		return m.Challenge
 	}
 	return nil
 }
 
-func (m *PoetProof) GetProofNodes() [][]byte {
+type GetMembershipProofResponse struct {
+	Mproof               *MembershipProof `protobuf:"bytes,1,opt,name=mproof,proto3" json:"mproof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *GetMembershipProofResponse) Reset()         { *m = GetMembershipProofResponse{} }
+func (m *GetMembershipProofResponse) String() string { return proto.CompactTextString(m) }
+func (*GetMembershipProofResponse) ProtoMessage()    {}
+func (*GetMembershipProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{11}
+}
+
+func (m *GetMembershipProofResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMembershipProofResponse.Unmarshal(m, b)
+}
+func (m *GetMembershipProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMembershipProofResponse.Marshal(b, m, deterministic)
+}
+func (m *GetMembershipProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMembershipProofResponse.Merge(m, src)
+}
+func (m *GetMembershipProofResponse) XXX_Size() int {
+	return xxx_messageInfo_GetMembershipProofResponse.Size(m)
+}
+func (m *GetMembershipProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMembershipProofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMembershipProofResponse proto.InternalMessageInfo
+
+func (m *GetMembershipProofResponse) GetMproof() *MembershipProof {
 	if m != nil {
-		return m.ProofNodes
+		return m.Mproof
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*StartRequest)(nil), "api.StartRequest")
-	proto.RegisterType((*StartResponse)(nil), "api.StartResponse")
-	proto.RegisterType((*UpdateGatewayRequest)(nil), "api.UpdateGatewayRequest")
-	proto.RegisterType((*UpdateGatewayResponse)(nil), "api.UpdateGatewayResponse")
-	proto.RegisterType((*SubmitRequest)(nil), "api.SubmitRequest")
-	proto.RegisterType((*SubmitResponse)(nil), "api.SubmitResponse")
-	proto.RegisterType((*GetInfoRequest)(nil), "api.GetInfoRequest")
-	proto.RegisterType((*GetInfoResponse)(nil), "api.GetInfoResponse")
-	proto.RegisterType((*MembershipProof)(nil), "api.MembershipProof")
-	proto.RegisterType((*PoetProof)(nil), "api.PoetProof")
+type GetProofRequest struct {
+	RoundId              string   `protobuf:"bytes,1,opt,name=roundId,proto3" json:"roundId,omitempty"`
+	Wait                 bool     `protobuf:"varint,2,opt,name=wait,proto3" json:"wait,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
+func (m *GetProofRequest) Reset()         { *m = GetProofRequest{} }
+func (m *GetProofRequest) String() string { return proto.CompactTextString(m) }
+func (*GetProofRequest) ProtoMessage()    {}
+func (*GetProofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{12}
+}
 
-var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 569 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x94, 0xcf, 0x6e, 0xd4, 0x30,
-	0x10, 0xc6, 0x95, 0xdd, 0x6e, 0xdb, 0x0c, 0xd9, 0xee, 0xd6, 0x6c, 0x4b, 0x88, 0x2a, 0x14, 0x59,
-	0x1c, 0x56, 0x15, 0x74, 0x05, 0x48, 0x1c, 0xb8, 0x15, 0x21, 0x4a, 0xc5, 0x1f, 0x15, 0x17, 0x1e,
-	0xc0, 0x89, 0xa7, 0xbb, 0x16, 0x5b, 0x3b, 0xc4, 0xce, 0xd2, 0x5e, 0xb9, 0x71, 0xe6, 0x95, 0x38,
-	0x72, 0x83, 0x47, 0xe0, 0x41, 0x50, 0x1c, 0xb7, 0x6c, 0x68, 0x79, 0x01, 0x6e, 0xf1, 0x37, 0xf3,
-	0xfd, 0xec, 0xb1, 0x67, 0x02, 0x21, 0x2f, 0xe4, 0x5e, 0x51, 0x6a, 0xab, 0x49, 0x97, 0x17, 0x32,
-	0xd9, 0x99, 0x6a, 0x3d, 0x9d, 0xe3, 0x84, 0x17, 0x72, 0xc2, 0x95, 0xd2, 0x96, 0x5b, 0xa9, 0x95,
-	0x69, 0x52, 0xe8, 0xf7, 0x00, 0xa2, 0x63, 0xcb, 0x4b, 0xcb, 0xf0, 0x63, 0x85, 0xc6, 0x92, 0x5d,
-	0x18, 0x4e, 0xb9, 0xc5, 0x4f, 0xfc, 0x7c, 0x5f, 0x88, 0x12, 0x8d, 0x41, 0x13, 0x07, 0x69, 0x77,
-	0x1c, 0xb2, 0x2b, 0x7a, 0x9d, 0x2b, 0xa4, 0xe1, 0xd9, 0x1c, 0x9f, 0x96, 0x9a, 0x8b, 0x9c, 0x1b,
-	0x1b, 0x77, 0xd2, 0x60, 0xbc, 0xce, 0xae, 0xe8, 0xe4, 0x1e, 0x6c, 0xe6, 0x5a, 0xa9, 0xfd, 0xfc,
-	0x83, 0x79, 0x37, 0x2b, 0xd1, 0xcc, 0xf4, 0x5c, 0xc4, 0xdd, 0x34, 0x18, 0xf7, 0xd8, 0xd5, 0x00,
-	0x79, 0x0c, 0xdb, 0xd9, 0x85, 0xb5, 0x6d, 0x59, 0x71, 0x96, 0x7f, 0x44, 0xe9, 0x00, 0xfa, 0xbe,
-	0x1a, 0x53, 0x68, 0x65, 0x90, 0xfe, 0x0c, 0x60, 0xf4, 0xbe, 0x10, 0xdc, 0xe2, 0x41, 0x73, 0xfa,
-	0xff, 0xa3, 0xce, 0x5b, 0xb0, 0xf5, 0x57, 0x55, 0xbe, 0xde, 0xfb, 0xd0, 0x3f, 0xae, 0xb2, 0x53,
-	0x79, 0xf9, 0x9e, 0x3b, 0x10, 0xe6, 0x33, 0x3e, 0x9f, 0xa3, 0x9a, 0x62, 0x1c, 0xa4, 0xc1, 0x38,
-	0x62, 0x7f, 0x04, 0xba, 0x0b, 0x1b, 0x17, 0xe9, 0x0d, 0x80, 0xc4, 0xb0, 0x56, 0xea, 0x4a, 0x89,
-	0x43, 0xe1, 0xb2, 0x43, 0x76, 0xb1, 0xa4, 0x43, 0xd8, 0x38, 0x40, 0x7b, 0xa8, 0x4e, 0xb4, 0x67,
-	0xd3, 0x2f, 0x01, 0x0c, 0x2e, 0x25, 0xef, 0x4f, 0xe1, 0x86, 0x2e, 0x50, 0xb1, 0x16, 0x63, 0x59,
-	0x22, 0x7b, 0x40, 0xf0, 0x0c, 0xf3, 0xca, 0x4a, 0x35, 0x75, 0x9a, 0x39, 0x14, 0x26, 0xee, 0xb8,
-	0xbb, 0xbf, 0x26, 0x42, 0xee, 0x42, 0xdf, 0x60, 0xb9, 0x90, 0x39, 0x1e, 0x55, 0xd9, 0x4b, 0x3c,
-	0x77, 0xb7, 0x19, 0xb1, 0xb6, 0x48, 0xdf, 0xc2, 0xe0, 0x35, 0x9e, 0x66, 0x58, 0x9a, 0x99, 0x2c,
-	0x8e, 0x4a, 0xad, 0x4f, 0xc8, 0x08, 0x7a, 0x52, 0x09, 0x3c, 0x73, 0x87, 0xe8, 0xb1, 0x66, 0x41,
-	0x08, 0xac, 0x94, 0x5a, 0x37, 0x0f, 0x18, 0x31, 0xf7, 0x5d, 0x67, 0x16, 0xb5, 0x25, 0xee, 0xa6,
-	0xdd, 0x71, 0xc4, 0x9a, 0x05, 0xe5, 0x10, 0x1e, 0x69, 0xb4, 0x0d, 0x6c, 0x08, 0xdd, 0x62, 0x26,
-	0xfd, 0x0d, 0xd6, 0x9f, 0x84, 0x42, 0x54, 0x94, 0x7a, 0x81, 0xea, 0x15, 0xf2, 0x05, 0x36, 0x15,
-	0x44, 0xac, 0xa5, 0x91, 0x3b, 0x00, 0x8e, 0xf5, 0x46, 0x0b, 0x34, 0x9e, 0xbe, 0xa4, 0x3c, 0xfc,
-	0xd6, 0x81, 0x95, 0x7a, 0x0f, 0xf2, 0x0c, 0x7a, 0xae, 0x71, 0xc9, 0xe6, 0x5e, 0x3d, 0xbf, 0xcb,
-	0x23, 0x99, 0x90, 0x65, 0xc9, 0xbf, 0xf3, 0xe8, 0xf3, 0x8f, 0x5f, 0x5f, 0x3b, 0x1b, 0x34, 0x9c,
-	0x2c, 0x1e, 0x4c, 0x4c, 0x1d, 0x7a, 0x12, 0xec, 0x12, 0x01, 0xfd, 0x56, 0x5b, 0x90, 0xdb, 0xce,
-	0x7a, 0xdd, 0x00, 0x24, 0xc9, 0x75, 0x21, 0x4f, 0xdf, 0x71, 0xf4, 0x6d, 0xba, 0x59, 0xd3, 0x2b,
-	0x97, 0xe2, 0x87, 0xa2, 0xde, 0xe5, 0x05, 0xac, 0x36, 0x4d, 0x43, 0xfc, 0xc9, 0x96, 0x1b, 0x2e,
-	0xb9, 0xd9, 0xd2, 0x3c, 0x70, 0xcb, 0x01, 0x07, 0x14, 0xdc, 0x71, 0x5d, 0xac, 0x26, 0x3d, 0x87,
-	0x35, 0xdf, 0x3f, 0xa4, 0xb1, 0xb5, 0x1b, 0x2c, 0x19, 0xb5, 0x45, 0x0f, 0x1b, 0x3a, 0x18, 0x90,
-	0xf5, 0x1a, 0x26, 0xd5, 0x89, 0xce, 0x56, 0xdd, 0xcf, 0xec, 0xd1, 0xef, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x48, 0x37, 0xf8, 0xf0, 0xfc, 0x04, 0x00, 0x00,
+func (m *GetProofRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetProofRequest.Unmarshal(m, b)
+}
+func (m *GetProofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetProofRequest.Marshal(b, m, deterministic)
+}
+func (m *GetProofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProofRequest.Merge(m, src)
+}
+func (m *GetProofRequest) XXX_Size() int {
+	return xxx_messageInfo_GetProofRequest.Size(m)
+}
+func (m *GetProofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProofRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_GetProofRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *GetProofRequest) GetRoundId() string {
+	if m != nil {
+		return m.RoundId
+	}
+	return ""
+}
 
-// PoetClient is the client API for Poet service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type PoetClient interface {
-	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error)
-	UpdateGateway(ctx context.Context, in *UpdateGatewayRequest, opts ...grpc.CallOption) (*UpdateGatewayResponse, error)
-	Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error)
-	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+func (m *GetProofRequest) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
 }
 
-type poetClient struct {
-	cc *grpc.ClientConn
+type GetProofResponse struct {
+	Proof                *PoetProof `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof,omitempty"`
+	N                    uint32     `protobuf:"varint,2,opt,name=n,proto3" json:"n,omitempty"`
+	Statement            []byte     `protobuf:"bytes,3,opt,name=statement,proto3" json:"statement,omitempty"`
+	Members              [][]byte   `protobuf:"bytes,4,rep,name=members,proto3" json:"members,omitempty"`
+	NumLeaves            uint64     `protobuf:"varint,5,opt,name=numLeaves,proto3" json:"numLeaves,omitempty"`
+	SecurityParam        uint32     `protobuf:"varint,6,opt,name=securityParam,proto3" json:"securityParam,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
-func NewPoetClient(cc *grpc.ClientConn) PoetClient {
-	return &poetClient{cc}
+func (m *GetProofResponse) Reset()         { *m = GetProofResponse{} }
+func (m *GetProofResponse) String() string { return proto.CompactTextString(m) }
+func (*GetProofResponse) ProtoMessage()    {}
+func (*GetProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13}
 }
 
-func (c *poetClient) Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error) {
-	out := new(StartResponse)
-	err := c.cc.Invoke(ctx, "/api.Poet/Start", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *GetProofResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetProofResponse.Unmarshal(m, b)
+}
+func (m *GetProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetProofResponse.Marshal(b, m, deterministic)
+}
+func (m *GetProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProofResponse.Merge(m, src)
+}
+func (m *GetProofResponse) XXX_Size() int {
+	return xxx_messageInfo_GetProofResponse.Size(m)
+}
+func (m *GetProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProofResponse.DiscardUnknown(m)
 }
 
-func (c *poetClient) UpdateGateway(ctx context.Context, in *UpdateGatewayRequest, opts ...grpc.CallOption) (*UpdateGatewayResponse, error) {
-	out := new(UpdateGatewayResponse)
-	err := c.cc.Invoke(ctx, "/api.Poet/UpdateGateway", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_GetProofResponse proto.InternalMessageInfo
+
+func (m *GetProofResponse) GetProof() *PoetProof {
+	if m != nil {
+		return m.Proof
 	}
-	return out, nil
+	return nil
 }
 
-func (c *poetClient) Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error) {
+func (m *GetProofResponse) GetN() uint32 {
+	if m != nil {
+		return m.N
+	}
+	return 0
+}
+
+func (m *GetProofResponse) GetStatement() []byte {
+	if m != nil {
+		return m.Statement
+	}
+	return nil
+}
+
+func (m *GetProofResponse) GetMembers() [][]byte {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *GetProofResponse) GetNumLeaves() uint64 {
+	if m != nil {
+		return m.NumLeaves
+	}
+	return 0
+}
+
+func (m *GetProofResponse) GetSecurityParam() uint32 {
+	if m != nil {
+		return m.SecurityParam
+	}
+	return 0
+}
+
+type ListRoundsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRoundsRequest) Reset()         { *m = ListRoundsRequest{} }
+func (m *ListRoundsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRoundsRequest) ProtoMessage()    {}
+func (*ListRoundsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
+}
+
+func (m *ListRoundsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRoundsRequest.Unmarshal(m, b)
+}
+func (m *ListRoundsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRoundsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRoundsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRoundsRequest.Merge(m, src)
+}
+func (m *ListRoundsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRoundsRequest.Size(m)
+}
+func (m *ListRoundsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRoundsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRoundsRequest proto.InternalMessageInfo
+
+type ListRoundsResponse struct {
+	Rounds               []*RoundInfo `protobuf:"bytes,1,rep,name=rounds,proto3" json:"rounds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListRoundsResponse) Reset()         { *m = ListRoundsResponse{} }
+func (m *ListRoundsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListRoundsResponse) ProtoMessage()    {}
+func (*ListRoundsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{15}
+}
+
+func (m *ListRoundsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRoundsResponse.Unmarshal(m, b)
+}
+func (m *ListRoundsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRoundsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListRoundsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRoundsResponse.Merge(m, src)
+}
+func (m *ListRoundsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListRoundsResponse.Size(m)
+}
+func (m *ListRoundsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRoundsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRoundsResponse proto.InternalMessageInfo
+
+func (m *ListRoundsResponse) GetRounds() []*RoundInfo {
+	if m != nil {
+		return m.Rounds
+	}
+	return nil
+}
+
+type GetRoundRequest struct {
+	RoundId              string   `protobuf:"bytes,1,opt,name=roundId,proto3" json:"roundId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRoundRequest) Reset()         { *m = GetRoundRequest{} }
+func (m *GetRoundRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRoundRequest) ProtoMessage()    {}
+func (*GetRoundRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{16}
+}
+
+func (m *GetRoundRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRoundRequest.Unmarshal(m, b)
+}
+func (m *GetRoundRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRoundRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRoundRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRoundRequest.Merge(m, src)
+}
+func (m *GetRoundRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRoundRequest.Size(m)
+}
+func (m *GetRoundRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRoundRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRoundRequest proto.InternalMessageInfo
+
+func (m *GetRoundRequest) GetRoundId() string {
+	if m != nil {
+		return m.RoundId
+	}
+	return ""
+}
+
+type GetRoundResponse struct {
+	Round                *RoundInfo `protobuf:"bytes,1,opt,name=round,proto3" json:"round,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GetRoundResponse) Reset()         { *m = GetRoundResponse{} }
+func (m *GetRoundResponse) String() string { return proto.CompactTextString(m) }
+func (*GetRoundResponse) ProtoMessage()    {}
+func (*GetRoundResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{17}
+}
+
+func (m *GetRoundResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRoundResponse.Unmarshal(m, b)
+}
+func (m *GetRoundResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRoundResponse.Marshal(b, m, deterministic)
+}
+func (m *GetRoundResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRoundResponse.Merge(m, src)
+}
+func (m *GetRoundResponse) XXX_Size() int {
+	return xxx_messageInfo_GetRoundResponse.Size(m)
+}
+func (m *GetRoundResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRoundResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRoundResponse proto.InternalMessageInfo
+
+func (m *GetRoundResponse) GetRound() *RoundInfo {
+	if m != nil {
+		return m.Round
+	}
+	return nil
+}
+
+type RebroadcastRequest struct {
+	RoundId              string   `protobuf:"bytes,1,opt,name=roundId,proto3" json:"roundId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RebroadcastRequest) Reset()         { *m = RebroadcastRequest{} }
+func (m *RebroadcastRequest) String() string { return proto.CompactTextString(m) }
+func (*RebroadcastRequest) ProtoMessage()    {}
+func (*RebroadcastRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{18}
+}
+
+func (m *RebroadcastRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RebroadcastRequest.Unmarshal(m, b)
+}
+func (m *RebroadcastRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RebroadcastRequest.Marshal(b, m, deterministic)
+}
+func (m *RebroadcastRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RebroadcastRequest.Merge(m, src)
+}
+func (m *RebroadcastRequest) XXX_Size() int {
+	return xxx_messageInfo_RebroadcastRequest.Size(m)
+}
+func (m *RebroadcastRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RebroadcastRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RebroadcastRequest proto.InternalMessageInfo
+
+func (m *RebroadcastRequest) GetRoundId() string {
+	if m != nil {
+		return m.RoundId
+	}
+	return ""
+}
+
+type RebroadcastResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RebroadcastResponse) Reset()         { *m = RebroadcastResponse{} }
+func (m *RebroadcastResponse) String() string { return proto.CompactTextString(m) }
+func (*RebroadcastResponse) ProtoMessage()    {}
+func (*RebroadcastResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{19}
+}
+
+func (m *RebroadcastResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RebroadcastResponse.Unmarshal(m, b)
+}
+func (m *RebroadcastResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RebroadcastResponse.Marshal(b, m, deterministic)
+}
+func (m *RebroadcastResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RebroadcastResponse.Merge(m, src)
+}
+func (m *RebroadcastResponse) XXX_Size() int {
+	return xxx_messageInfo_RebroadcastResponse.Size(m)
+}
+func (m *RebroadcastResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RebroadcastResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RebroadcastResponse proto.InternalMessageInfo
+
+type GetGatewaysRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetGatewaysRequest) Reset()         { *m = GetGatewaysRequest{} }
+func (m *GetGatewaysRequest) String() string { return proto.CompactTextString(m) }
+func (*GetGatewaysRequest) ProtoMessage()    {}
+func (*GetGatewaysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{20}
+}
+
+func (m *GetGatewaysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetGatewaysRequest.Unmarshal(m, b)
+}
+func (m *GetGatewaysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetGatewaysRequest.Marshal(b, m, deterministic)
+}
+func (m *GetGatewaysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetGatewaysRequest.Merge(m, src)
+}
+func (m *GetGatewaysRequest) XXX_Size() int {
+	return xxx_messageInfo_GetGatewaysRequest.Size(m)
+}
+func (m *GetGatewaysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetGatewaysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetGatewaysRequest proto.InternalMessageInfo
+
+type GetGatewaysResponse struct {
+	Gateways             []*GatewayStatus `protobuf:"bytes,1,rep,name=gateways,proto3" json:"gateways,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *GetGatewaysResponse) Reset()         { *m = GetGatewaysResponse{} }
+func (m *GetGatewaysResponse) String() string { return proto.CompactTextString(m) }
+func (*GetGatewaysResponse) ProtoMessage()    {}
+func (*GetGatewaysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{21}
+}
+
+func (m *GetGatewaysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetGatewaysResponse.Unmarshal(m, b)
+}
+func (m *GetGatewaysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetGatewaysResponse.Marshal(b, m, deterministic)
+}
+func (m *GetGatewaysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetGatewaysResponse.Merge(m, src)
+}
+func (m *GetGatewaysResponse) XXX_Size() int {
+	return xxx_messageInfo_GetGatewaysResponse.Size(m)
+}
+func (m *GetGatewaysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetGatewaysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetGatewaysResponse proto.InternalMessageInfo
+
+func (m *GetGatewaysResponse) GetGateways() []*GatewayStatus {
+	if m != nil {
+		return m.Gateways
+	}
+	return nil
+}
+
+// Gateway status times are in unix seconds, and are 0 if not applicable.
+type GatewayStatus struct {
+	Address   string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Connected bool   `protobuf:"varint,2,opt,name=connected,proto3" json:"connected,omitempty"`
+	// state is the gRPC connectivity state of the gateway node connection (e.g. READY), or empty if there is none.
+	State         string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	LastError     string `protobuf:"bytes,4,opt,name=lastError,proto3" json:"lastError,omitempty"`
+	LastErrorTime int64  `protobuf:"varint,5,opt,name=lastErrorTime,proto3" json:"lastErrorTime,omitempty"`
+	// lastBroadcast is the time of the last successful broadcast via the gateway node.
+	LastBroadcast        int64    `protobuf:"varint,6,opt,name=lastBroadcast,proto3" json:"lastBroadcast,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GatewayStatus) Reset()         { *m = GatewayStatus{} }
+func (m *GatewayStatus) String() string { return proto.CompactTextString(m) }
+func (*GatewayStatus) ProtoMessage()    {}
+func (*GatewayStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{22}
+}
+
+func (m *GatewayStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GatewayStatus.Unmarshal(m, b)
+}
+func (m *GatewayStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GatewayStatus.Marshal(b, m, deterministic)
+}
+func (m *GatewayStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GatewayStatus.Merge(m, src)
+}
+func (m *GatewayStatus) XXX_Size() int {
+	return xxx_messageInfo_GatewayStatus.Size(m)
+}
+func (m *GatewayStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_GatewayStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GatewayStatus proto.InternalMessageInfo
+
+func (m *GatewayStatus) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *GatewayStatus) GetConnected() bool {
+	if m != nil {
+		return m.Connected
+	}
+	return false
+}
+
+func (m *GatewayStatus) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *GatewayStatus) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *GatewayStatus) GetLastErrorTime() int64 {
+	if m != nil {
+		return m.LastErrorTime
+	}
+	return 0
+}
+
+func (m *GatewayStatus) GetLastBroadcast() int64 {
+	if m != nil {
+		return m.LastBroadcast
+	}
+	return 0
+}
+
+// RoundInfo timestamps are in unix seconds, and are 0 if not applicable.
+type RoundInfo struct {
+	Id                   string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Phase                RoundPhase `protobuf:"varint,2,opt,name=phase,proto3,enum=api.RoundPhase" json:"phase,omitempty"`
+	Opened               int64      `protobuf:"varint,3,opt,name=opened,proto3" json:"opened,omitempty"`
+	ExecutionStarted     int64      `protobuf:"varint,4,opt,name=executionStarted,proto3" json:"executionStarted,omitempty"`
+	NumMembers           uint32     `protobuf:"varint,5,opt,name=numMembers,proto3" json:"numMembers,omitempty"`
+	NextLeafId           uint64     `protobuf:"varint,6,opt,name=nextLeafId,proto3" json:"nextLeafId,omitempty"`
+	NumLeaves            uint64     `protobuf:"varint,7,opt,name=numLeaves,proto3" json:"numLeaves,omitempty"`
+	LastBroadcastAttempt int64      `protobuf:"varint,8,opt,name=lastBroadcastAttempt,proto3" json:"lastBroadcastAttempt,omitempty"`
+	// The proof broadcast record of an executed round. nextBroadcastRetry is in unix seconds,
+	// and broadcastExhausted indicates that the broadcast retries ran out.
+	BroadcastAttempts    []*BroadcastAttempt `protobuf:"bytes,9,rep,name=broadcastAttempts,proto3" json:"broadcastAttempts,omitempty"`
+	NextBroadcastRetry   int64               `protobuf:"varint,10,opt,name=nextBroadcastRetry,proto3" json:"nextBroadcastRetry,omitempty"`
+	BroadcastExhausted   bool                `protobuf:"varint,11,opt,name=broadcastExhausted,proto3" json:"broadcastExhausted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *RoundInfo) Reset()         { *m = RoundInfo{} }
+func (m *RoundInfo) String() string { return proto.CompactTextString(m) }
+func (*RoundInfo) ProtoMessage()    {}
+func (*RoundInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{23}
+}
+
+func (m *RoundInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoundInfo.Unmarshal(m, b)
+}
+func (m *RoundInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoundInfo.Marshal(b, m, deterministic)
+}
+func (m *RoundInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoundInfo.Merge(m, src)
+}
+func (m *RoundInfo) XXX_Size() int {
+	return xxx_messageInfo_RoundInfo.Size(m)
+}
+func (m *RoundInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoundInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoundInfo proto.InternalMessageInfo
+
+func (m *RoundInfo) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RoundInfo) GetPhase() RoundPhase {
+	if m != nil {
+		return m.Phase
+	}
+	return RoundPhase_OPEN
+}
+
+func (m *RoundInfo) GetOpened() int64 {
+	if m != nil {
+		return m.Opened
+	}
+	return 0
+}
+
+func (m *RoundInfo) GetExecutionStarted() int64 {
+	if m != nil {
+		return m.ExecutionStarted
+	}
+	return 0
+}
+
+func (m *RoundInfo) GetNumMembers() uint32 {
+	if m != nil {
+		return m.NumMembers
+	}
+	return 0
+}
+
+func (m *RoundInfo) GetNextLeafId() uint64 {
+	if m != nil {
+		return m.NextLeafId
+	}
+	return 0
+}
+
+func (m *RoundInfo) GetNumLeaves() uint64 {
+	if m != nil {
+		return m.NumLeaves
+	}
+	return 0
+}
+
+func (m *RoundInfo) GetLastBroadcastAttempt() int64 {
+	if m != nil {
+		return m.LastBroadcastAttempt
+	}
+	return 0
+}
+
+func (m *RoundInfo) GetBroadcastAttempts() []*BroadcastAttempt {
+	if m != nil {
+		return m.BroadcastAttempts
+	}
+	return nil
+}
+
+func (m *RoundInfo) GetNextBroadcastRetry() int64 {
+	if m != nil {
+		return m.NextBroadcastRetry
+	}
+	return 0
+}
+
+func (m *RoundInfo) GetBroadcastExhausted() bool {
+	if m != nil {
+		return m.BroadcastExhausted
+	}
+	return false
+}
+
+// BroadcastAttempt is the result of a proof broadcast attempt via a gateway node. time is in unix seconds.
+// gateway is empty if the results of the gateway nodes aren't reported separately,
+// and error is empty if the attempt succeeded.
+type BroadcastAttempt struct {
+	Time                 int64    `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Gateway              string   `protobuf:"bytes,2,opt,name=gateway,proto3" json:"gateway,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BroadcastAttempt) Reset()         { *m = BroadcastAttempt{} }
+func (m *BroadcastAttempt) String() string { return proto.CompactTextString(m) }
+func (*BroadcastAttempt) ProtoMessage()    {}
+func (*BroadcastAttempt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{24}
+}
+
+func (m *BroadcastAttempt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BroadcastAttempt.Unmarshal(m, b)
+}
+func (m *BroadcastAttempt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BroadcastAttempt.Marshal(b, m, deterministic)
+}
+func (m *BroadcastAttempt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BroadcastAttempt.Merge(m, src)
+}
+func (m *BroadcastAttempt) XXX_Size() int {
+	return xxx_messageInfo_BroadcastAttempt.Size(m)
+}
+func (m *BroadcastAttempt) XXX_DiscardUnknown() {
+	xxx_messageInfo_BroadcastAttempt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BroadcastAttempt proto.InternalMessageInfo
+
+func (m *BroadcastAttempt) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *BroadcastAttempt) GetGateway() string {
+	if m != nil {
+		return m.Gateway
+	}
+	return ""
+}
+
+func (m *BroadcastAttempt) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type SubscribeEventsRequest struct {
+	FromSeq              uint64   `protobuf:"varint,1,opt,name=fromSeq,proto3" json:"fromSeq,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeEventsRequest) Reset()         { *m = SubscribeEventsRequest{} }
+func (m *SubscribeEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeEventsRequest) ProtoMessage()    {}
+func (*SubscribeEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{25}
+}
+
+func (m *SubscribeEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeEventsRequest.Unmarshal(m, b)
+}
+func (m *SubscribeEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeEventsRequest.Merge(m, src)
+}
+func (m *SubscribeEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeEventsRequest.Size(m)
+}
+func (m *SubscribeEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeEventsRequest proto.InternalMessageInfo
+
+func (m *SubscribeEventsRequest) GetFromSeq() uint64 {
+	if m != nil {
+		return m.FromSeq
+	}
+	return 0
+}
+
+// Event time is in unix seconds. Its payload is the proof root (phi) for ROUND_EXECUTION_ENDED events,
+// and is empty otherwise.
+type Event struct {
+	Seq                  uint64    `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Type                 EventType `protobuf:"varint,2,opt,name=type,proto3,enum=api.EventType" json:"type,omitempty"`
+	RoundId              string    `protobuf:"bytes,3,opt,name=roundId,proto3" json:"roundId,omitempty"`
+	Time                 int64     `protobuf:"varint,4,opt,name=time,proto3" json:"time,omitempty"`
+	Payload              []byte    `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{26}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *Event) GetType() EventType {
+	if m != nil {
+		return m.Type
+	}
+	return EventType_ROUND_OPENED
+}
+
+func (m *Event) GetRoundId() string {
+	if m != nil {
+		return m.RoundId
+	}
+	return ""
+}
+
+func (m *Event) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Event) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+type MembershipProof struct {
+	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Root                 []byte   `protobuf:"bytes,2,opt,name=root,proto3" json:"root,omitempty"`
+	Proof                [][]byte `protobuf:"bytes,3,rep,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MembershipProof) Reset()         { *m = MembershipProof{} }
+func (m *MembershipProof) String() string { return proto.CompactTextString(m) }
+func (*MembershipProof) ProtoMessage()    {}
+func (*MembershipProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{27}
+}
+
+func (m *MembershipProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MembershipProof.Unmarshal(m, b)
+}
+func (m *MembershipProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MembershipProof.Marshal(b, m, deterministic)
+}
+func (m *MembershipProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MembershipProof.Merge(m, src)
+}
+func (m *MembershipProof) XXX_Size() int {
+	return xxx_messageInfo_MembershipProof.Size(m)
+}
+func (m *MembershipProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_MembershipProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MembershipProof proto.InternalMessageInfo
+
+func (m *MembershipProof) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *MembershipProof) GetRoot() []byte {
+	if m != nil {
+		return m.Root
+	}
+	return nil
+}
+
+func (m *MembershipProof) GetProof() [][]byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+type PoetProof struct {
+	Phi                  []byte   `protobuf:"bytes,1,opt,name=phi,proto3" json:"phi,omitempty"`
+	ProvenLeaves         [][]byte `protobuf:"bytes,2,rep,name=provenLeaves,proto3" json:"provenLeaves,omitempty"`
+	ProofNodes           [][]byte `protobuf:"bytes,3,rep,name=proofNodes,proto3" json:"proofNodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PoetProof) Reset()         { *m = PoetProof{} }
+func (m *PoetProof) String() string { return proto.CompactTextString(m) }
+func (*PoetProof) ProtoMessage()    {}
+func (*PoetProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{28}
+}
+
+func (m *PoetProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PoetProof.Unmarshal(m, b)
+}
+func (m *PoetProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PoetProof.Marshal(b, m, deterministic)
+}
+func (m *PoetProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PoetProof.Merge(m, src)
+}
+func (m *PoetProof) XXX_Size() int {
+	return xxx_messageInfo_PoetProof.Size(m)
+}
+func (m *PoetProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_PoetProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PoetProof proto.InternalMessageInfo
+
+func (m *PoetProof) GetPhi() []byte {
+	if m != nil {
+		return m.Phi
+	}
+	return nil
+}
+
+func (m *PoetProof) GetProvenLeaves() [][]byte {
+	if m != nil {
+		return m.ProvenLeaves
+	}
+	return nil
+}
+
+func (m *PoetProof) GetProofNodes() [][]byte {
+	if m != nil {
+		return m.ProofNodes
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("api.RoundPhase", RoundPhase_name, RoundPhase_value)
+	proto.RegisterEnum("api.EventType", EventType_name, EventType_value)
+	proto.RegisterType((*StartRequest)(nil), "api.StartRequest")
+	proto.RegisterType((*StartResponse)(nil), "api.StartResponse")
+	proto.RegisterType((*UpdateGatewayRequest)(nil), "api.UpdateGatewayRequest")
+	proto.RegisterType((*UpdateGatewayResponse)(nil), "api.UpdateGatewayResponse")
+	proto.RegisterType((*GatewayTLS)(nil), "api.GatewayTLS")
+	proto.RegisterType((*SubmitRequest)(nil), "api.SubmitRequest")
+	proto.RegisterType((*SubmitResponse)(nil), "api.SubmitResponse")
+	proto.RegisterType((*GetInfoRequest)(nil), "api.GetInfoRequest")
+	proto.RegisterType((*GetInfoResponse)(nil), "api.GetInfoResponse")
+	proto.RegisterType((*ExecutionProgress)(nil), "api.ExecutionProgress")
+	proto.RegisterType((*GetMembershipProofRequest)(nil), "api.GetMembershipProofRequest")
+	proto.RegisterType((*GetMembershipProofResponse)(nil), "api.GetMembershipProofResponse")
+	proto.RegisterType((*GetProofRequest)(nil), "api.GetProofRequest")
+	proto.RegisterType((*GetProofResponse)(nil), "api.GetProofResponse")
+	proto.RegisterType((*ListRoundsRequest)(nil), "api.ListRoundsRequest")
+	proto.RegisterType((*ListRoundsResponse)(nil), "api.ListRoundsResponse")
+	proto.RegisterType((*GetRoundRequest)(nil), "api.GetRoundRequest")
+	proto.RegisterType((*GetRoundResponse)(nil), "api.GetRoundResponse")
+	proto.RegisterType((*RebroadcastRequest)(nil), "api.RebroadcastRequest")
+	proto.RegisterType((*RebroadcastResponse)(nil), "api.RebroadcastResponse")
+	proto.RegisterType((*GetGatewaysRequest)(nil), "api.GetGatewaysRequest")
+	proto.RegisterType((*GetGatewaysResponse)(nil), "api.GetGatewaysResponse")
+	proto.RegisterType((*GatewayStatus)(nil), "api.GatewayStatus")
+	proto.RegisterType((*RoundInfo)(nil), "api.RoundInfo")
+	proto.RegisterType((*BroadcastAttempt)(nil), "api.BroadcastAttempt")
+	proto.RegisterType((*SubscribeEventsRequest)(nil), "api.SubscribeEventsRequest")
+	proto.RegisterType((*Event)(nil), "api.Event")
+	proto.RegisterType((*MembershipProof)(nil), "api.MembershipProof")
+	proto.RegisterType((*PoetProof)(nil), "api.PoetProof")
+}
+
+func init() {
+	proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c)
+}
+
+var fileDescriptor_00212fb1f9d3bf1c = []byte{
+	// 1609 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x58, 0xcd, 0x6e, 0x1b, 0xb7,
+	0x16, 0xce, 0xe8, 0xc7, 0x96, 0x8e, 0x24, 0x5b, 0xa2, 0x2d, 0x5b, 0x51, 0x82, 0x5c, 0x63, 0x90,
+	0x5c, 0x18, 0x4e, 0x60, 0xe7, 0xfa, 0x02, 0x17, 0x17, 0x45, 0x81, 0xc2, 0xb1, 0x14, 0xd7, 0xa9,
+	0x6b, 0x2b, 0x94, 0x1d, 0x74, 0x17, 0x50, 0x1a, 0xda, 0x1a, 0x44, 0xf3, 0x93, 0x19, 0xca, 0xb1,
+	0x50, 0x64, 0x53, 0x20, 0x4f, 0xd0, 0x6d, 0x5f, 0xa2, 0x0f, 0xd0, 0x4d, 0x37, 0x79, 0x80, 0x2e,
+	0xbb, 0xed, 0x6b, 0x14, 0x28, 0x78, 0xc8, 0xf9, 0x93, 0xe4, 0xa4, 0xfb, 0xee, 0x86, 0xdf, 0x39,
+	0xfc, 0x78, 0x78, 0xf8, 0xf1, 0xf0, 0x48, 0x50, 0x66, 0xbe, 0xbd, 0xeb, 0x07, 0x9e, 0xf0, 0x48,
+	0x9e, 0xf9, 0x76, 0xfb, 0xfe, 0x95, 0xe7, 0x5d, 0x8d, 0xf9, 0x1e, 0xf3, 0xed, 0x3d, 0xe6, 0xba,
+	0x9e, 0x60, 0xc2, 0xf6, 0xdc, 0x50, 0xb9, 0x98, 0x7f, 0x1a, 0x50, 0xed, 0x0b, 0x16, 0x08, 0xca,
+	0xdf, 0x4e, 0x78, 0x28, 0xc8, 0x0e, 0xd4, 0xaf, 0x98, 0xe0, 0xef, 0xd8, 0xf4, 0xc0, 0xb2, 0x02,
+	0x1e, 0x86, 0x3c, 0x6c, 0x19, 0x5b, 0xf9, 0xed, 0x32, 0x9d, 0xc3, 0xa5, 0xaf, 0x65, 0x87, 0x6c,
+	0x30, 0xe6, 0xcf, 0x02, 0x8f, 0x59, 0x43, 0x16, 0x8a, 0x56, 0x6e, 0xcb, 0xd8, 0x2e, 0xd1, 0x39,
+	0x9c, 0x3c, 0x81, 0xc6, 0xd0, 0x73, 0xdd, 0x83, 0xe1, 0x9b, 0xf0, 0x7c, 0x14, 0xf0, 0x70, 0xe4,
+	0x8d, 0xad, 0x56, 0x7e, 0xcb, 0xd8, 0x2e, 0xd2, 0x79, 0x03, 0xf9, 0x1f, 0x6c, 0x0c, 0xa2, 0xa9,
+	0xd9, 0x29, 0x05, 0x9c, 0x72, 0x8b, 0x95, 0xec, 0x01, 0xe8, 0x28, 0xcf, 0xc7, 0x61, 0xab, 0xb8,
+	0x95, 0xdf, 0xae, 0xec, 0xaf, 0xee, 0xca, 0x8c, 0x1c, 0x69, 0xf8, 0xa4, 0x4f, 0x53, 0x2e, 0xe6,
+	0x2a, 0xd4, 0xf4, 0xf6, 0x43, 0xdf, 0x73, 0x43, 0x6e, 0x7e, 0xc8, 0xc1, 0xfa, 0x85, 0x6f, 0x31,
+	0xc1, 0xf5, 0x8c, 0x7f, 0x68, 0x62, 0x36, 0xa1, 0x39, 0x93, 0x06, 0x9d, 0xa0, 0x9f, 0x0c, 0x80,
+	0x64, 0x0e, 0x69, 0xc1, 0x32, 0x53, 0xfb, 0x6e, 0x19, 0x5b, 0xc6, 0x76, 0x99, 0x46, 0x43, 0xf2,
+	0x00, 0x60, 0xc8, 0x0e, 0x79, 0x20, 0x9e, 0xdb, 0x63, 0x8e, 0xdb, 0x2f, 0xd3, 0x14, 0x42, 0xda,
+	0x50, 0x1a, 0x46, 0xd6, 0x3c, 0x5a, 0xe3, 0xb1, 0x64, 0x7d, 0xc3, 0xa7, 0x68, 0x2a, 0x28, 0x56,
+	0x3d, 0x94, 0xac, 0x21, 0x0f, 0xae, 0x79, 0x70, 0xca, 0x1c, 0xde, 0x2a, 0x2a, 0xd6, 0x04, 0x31,
+	0xdf, 0x43, 0xad, 0x3f, 0x19, 0x38, 0x76, 0x2c, 0xe8, 0xfb, 0x50, 0x1e, 0x8e, 0xd8, 0x78, 0xcc,
+	0xdd, 0x2b, 0x8e, 0x21, 0x56, 0x69, 0x02, 0x90, 0x0d, 0x58, 0xf2, 0x27, 0x83, 0x6f, 0xf8, 0x14,
+	0x03, 0xac, 0x52, 0x3d, 0x92, 0x01, 0x04, 0xde, 0xc4, 0xb5, 0x8e, 0x2d, 0x1d, 0x5b, 0x34, 0x94,
+	0x7c, 0xa1, 0x7d, 0xe5, 0x32, 0x31, 0x09, 0x54, 0x70, 0x55, 0x9a, 0x00, 0xe6, 0x0e, 0xac, 0x44,
+	0xcb, 0xab, 0x7c, 0xa5, 0x99, 0x8c, 0x0c, 0x93, 0x59, 0x87, 0x95, 0x23, 0x2e, 0x8e, 0xdd, 0x4b,
+	0x4f, 0xc7, 0x6a, 0xfe, 0x6e, 0xc0, 0x6a, 0x0c, 0xe9, 0xf9, 0x5b, 0x50, 0xf1, 0x7c, 0xee, 0xd2,
+	0x0c, 0x47, 0x1a, 0x22, 0xbb, 0x40, 0xf8, 0x0d, 0x1f, 0x4e, 0x84, 0xed, 0x5e, 0x21, 0x16, 0x1e,
+	0x5b, 0x61, 0x2b, 0x87, 0xda, 0x5c, 0x60, 0x21, 0x0f, 0xa1, 0x26, 0x13, 0x66, 0x0f, 0x79, 0x4f,
+	0x6d, 0x3d, 0x8f, 0xbb, 0xc8, 0x82, 0xa4, 0x07, 0x9b, 0x33, 0x73, 0x7b, 0x81, 0x77, 0x85, 0x07,
+	0x5d, 0x40, 0xf9, 0x6c, 0xa0, 0x7c, 0xba, 0xca, 0xc7, 0x73, 0x23, 0x2b, 0xbd, 0x6d, 0x9a, 0xf9,
+	0xb3, 0x01, 0x8d, 0x39, 0xf7, 0xdb, 0xf3, 0x23, 0x8f, 0x7a, 0xcc, 0xd9, 0x35, 0x0f, 0x3b, 0x9e,
+	0xab, 0x04, 0x54, 0xa0, 0x29, 0x44, 0x9e, 0x84, 0x3b, 0x71, 0x4e, 0x10, 0xc0, 0x3d, 0x14, 0x68,
+	0x02, 0x10, 0x02, 0x85, 0x80, 0x09, 0x75, 0x44, 0x06, 0xc5, 0x6f, 0xf2, 0x14, 0xd6, 0x78, 0x28,
+	0x6c, 0x87, 0x09, 0x6e, 0x1d, 0x7a, 0x8e, 0x3f, 0xe6, 0x32, 0x14, 0x54, 0x51, 0x9e, 0x2e, 0x32,
+	0x99, 0x7d, 0xb8, 0x7b, 0xc4, 0xc5, 0xb7, 0xdc, 0x19, 0xf0, 0x20, 0x1c, 0xd9, 0x7e, 0x2f, 0xf0,
+	0xbc, 0xcb, 0x48, 0x5a, 0xb7, 0x87, 0x9e, 0x11, 0x5d, 0x6e, 0x46, 0x74, 0xe6, 0x0b, 0x68, 0x2f,
+	0x22, 0xd5, 0x07, 0xfe, 0x04, 0x96, 0x1c, 0x5f, 0x22, 0x48, 0x5a, 0xd9, 0x5f, 0xc7, 0x3c, 0xcf,
+	0x7a, 0x6b, 0x1f, 0xf3, 0x2b, 0x54, 0xcc, 0xdf, 0x0c, 0x8b, 0x40, 0xe1, 0x1d, 0xb3, 0xa3, 0x5a,
+	0x84, 0xdf, 0xe6, 0xaf, 0x06, 0xd4, 0x13, 0x06, 0x1d, 0xc3, 0x43, 0x28, 0xa6, 0x43, 0x58, 0xc1,
+	0x10, 0x7a, 0x5e, 0xe4, 0xa6, 0x8c, 0xa4, 0x0a, 0x86, 0x8b, 0x5c, 0x35, 0x6a, 0xb8, 0x78, 0x31,
+	0x04, 0x13, 0xdc, 0xe1, 0xae, 0xd0, 0x92, 0x4a, 0x00, 0x19, 0x94, 0xa3, 0xb6, 0x80, 0xf2, 0xa9,
+	0xd2, 0x68, 0x98, 0x3d, 0xc6, 0xe2, 0xec, 0x31, 0xa2, 0x58, 0x87, 0x93, 0xc0, 0x16, 0xd3, 0x1e,
+	0x0b, 0x98, 0xd3, 0x5a, 0xc2, 0xf5, 0xb2, 0xa0, 0xb9, 0x06, 0x8d, 0x13, 0x3b, 0x14, 0x4a, 0x70,
+	0xd1, 0x6d, 0xfa, 0x12, 0x48, 0x1a, 0xd4, 0x5b, 0xfb, 0x37, 0x2c, 0x61, 0x3a, 0x54, 0xf5, 0x8e,
+	0xf6, 0xa6, 0xee, 0x92, 0xbc, 0x77, 0xda, 0x6a, 0x3e, 0xc6, 0xc4, 0x22, 0xfe, 0xd9, 0xc4, 0x9a,
+	0xff, 0x87, 0x7a, 0xe2, 0x9c, 0xe4, 0x10, 0xcd, 0x99, 0x1c, 0x26, 0xeb, 0x28, 0xa3, 0xb9, 0x0b,
+	0x84, 0xf2, 0xb8, 0x68, 0x7f, 0x7e, 0xa5, 0x26, 0xac, 0x65, 0xfc, 0x75, 0x55, 0x5e, 0x07, 0x72,
+	0xc4, 0x85, 0xae, 0xcb, 0x71, 0x06, 0xba, 0xb0, 0x96, 0x41, 0x75, 0x64, 0xbb, 0x50, 0xd2, 0x95,
+	0x3e, 0x4a, 0x02, 0x49, 0x3f, 0x05, 0x7d, 0xc1, 0xc4, 0x24, 0xa4, 0xb1, 0x8f, 0xf9, 0xd1, 0x80,
+	0x5a, 0xc6, 0xf6, 0x89, 0xaa, 0x2f, 0x95, 0xef, 0xb9, 0x2e, 0x1f, 0x0a, 0x6e, 0x69, 0x9d, 0x25,
+	0x00, 0x59, 0x87, 0x22, 0x4a, 0x42, 0x17, 0x55, 0x35, 0x90, 0x73, 0xc6, 0x2c, 0x14, 0xdd, 0x20,
+	0xf0, 0x02, 0x5d, 0xef, 0x13, 0x40, 0x2a, 0x20, 0x1e, 0x9c, 0xdb, 0xba, 0xe8, 0xe7, 0x69, 0x16,
+	0x8c, 0xbc, 0x92, 0xf7, 0x76, 0x29, 0xf1, 0x8a, 0x41, 0xf3, 0x97, 0x3c, 0x94, 0xe3, 0x23, 0x20,
+	0x2b, 0x90, 0xb3, 0xa3, 0x04, 0xe7, 0x6c, 0x8b, 0x3c, 0x82, 0xa2, 0x3f, 0x62, 0xa1, 0xba, 0xb1,
+	0x2b, 0xfa, 0x7d, 0x44, 0xf7, 0x9e, 0x84, 0xa9, 0xb2, 0xca, 0x37, 0x43, 0x96, 0x5f, 0xae, 0x9e,
+	0x86, 0x3c, 0xd5, 0x23, 0xf9, 0xea, 0xf3, 0xa8, 0xbc, 0x61, 0x53, 0xc1, 0xd5, 0xab, 0x9c, 0xa7,
+	0x73, 0xb8, 0xac, 0x6d, 0xee, 0xc4, 0xd1, 0x97, 0x1a, 0x77, 0x54, 0xa3, 0x29, 0x04, 0xed, 0xfc,
+	0x46, 0x9c, 0x70, 0x76, 0x79, 0x6c, 0xe1, 0x5e, 0x0a, 0x34, 0x85, 0x64, 0x2f, 0xcd, 0xf2, 0xec,
+	0xa5, 0xd9, 0x87, 0xf5, 0xcc, 0xbe, 0x0f, 0x84, 0xe0, 0x8e, 0x2f, 0x5a, 0x25, 0x8c, 0x66, 0xa1,
+	0x8d, 0x1c, 0x42, 0x63, 0x30, 0x83, 0x85, 0xad, 0x32, 0xaa, 0xa3, 0x89, 0x89, 0x98, 0x9d, 0x41,
+	0xe7, 0xfd, 0xe5, 0x53, 0x24, 0x83, 0x7c, 0x96, 0xe8, 0x53, 0x04, 0xd3, 0x16, 0xe0, 0xb2, 0x0b,
+	0x2c, 0xd2, 0x3f, 0x26, 0xe9, 0xde, 0x8c, 0xd8, 0x24, 0x94, 0x49, 0xab, 0xa0, 0x6c, 0x16, 0x58,
+	0xcc, 0x57, 0x50, 0x9f, 0x0b, 0x9c, 0x40, 0x41, 0x48, 0x59, 0x18, 0xb8, 0x0a, 0x7e, 0x4b, 0x7d,
+	0x6a, 0xf5, 0xea, 0xc6, 0x23, 0x1a, 0x4a, 0x05, 0x72, 0xd4, 0x99, 0x56, 0x20, 0x0e, 0xcc, 0x7d,
+	0xd8, 0xe8, 0x4f, 0x06, 0xe1, 0x30, 0xb0, 0x07, 0xbc, 0x7b, 0xcd, 0x5d, 0x11, 0xa6, 0x6e, 0xe2,
+	0x65, 0xe0, 0x39, 0x7d, 0xfe, 0x16, 0x17, 0x28, 0xd0, 0x68, 0x68, 0x7e, 0x30, 0xa0, 0x88, 0xbe,
+	0xa4, 0x0e, 0xf9, 0x30, 0xb6, 0xcb, 0x4f, 0x62, 0x42, 0x41, 0x4c, 0xfd, 0x48, 0x48, 0xea, 0xea,
+	0xa3, 0xef, 0xf9, 0xd4, 0xe7, 0x14, 0x6d, 0x9f, 0x68, 0x31, 0xa2, 0x1d, 0x15, 0xb2, 0x3b, 0xf2,
+	0xd9, 0x74, 0xec, 0x31, 0x0b, 0xd5, 0x52, 0xa5, 0xd1, 0xd0, 0x7c, 0x09, 0xab, 0x33, 0x8f, 0x83,
+	0xdc, 0xa4, 0xed, 0x5a, 0xfc, 0x06, 0x43, 0x2a, 0x52, 0x35, 0xc0, 0x17, 0xd1, 0xf3, 0x84, 0x7e,
+	0x8f, 0xf0, 0x5b, 0x7a, 0xaa, 0x42, 0x9f, 0xc7, 0xa2, 0xac, 0x06, 0x26, 0x83, 0x72, 0x5c, 0xec,
+	0xe5, 0xee, 0xfc, 0x91, 0xad, 0x5b, 0x27, 0xf9, 0x49, 0x4c, 0xa8, 0xfa, 0x81, 0x77, 0xcd, 0x5d,
+	0xad, 0xbf, 0x1c, 0xce, 0xcd, 0x60, 0x52, 0xc0, 0xc8, 0x75, 0xea, 0x59, 0xf8, 0x3a, 0x4b, 0x8f,
+	0x14, 0xb2, 0xd3, 0x01, 0x48, 0x6e, 0x16, 0x29, 0x41, 0xe1, 0xac, 0xd7, 0x3d, 0xad, 0xdf, 0x21,
+	0x35, 0x28, 0x77, 0xbf, 0xeb, 0x1e, 0x5e, 0x9c, 0x1f, 0x9f, 0x1e, 0xd5, 0x0d, 0x52, 0x85, 0x92,
+	0x1a, 0x76, 0x3b, 0xf5, 0x1c, 0x59, 0x85, 0xca, 0x33, 0x7a, 0x76, 0xd0, 0x39, 0x3c, 0xe8, 0x4b,
+	0x20, 0xbf, 0x33, 0x86, 0x72, 0x9c, 0x56, 0x52, 0x87, 0x2a, 0x3d, 0xbb, 0x38, 0xed, 0xbc, 0x96,
+	0x54, 0xdd, 0x4e, 0xfd, 0x0e, 0xb9, 0x07, 0x9b, 0x0a, 0xd1, 0x94, 0x67, 0xa7, 0xaf, 0xfb, 0xe7,
+	0x07, 0x54, 0xce, 0x35, 0xc8, 0x5d, 0x68, 0xce, 0x1a, 0xbb, 0xa7, 0x1d, 0x5c, 0xa7, 0x09, 0x0d,
+	0x65, 0xca, 0xac, 0xb6, 0xff, 0x71, 0x19, 0x0a, 0x32, 0x2f, 0xa4, 0x03, 0x45, 0xbc, 0xc8, 0xa4,
+	0x81, 0x27, 0x9b, 0xfe, 0x01, 0xd5, 0x26, 0x69, 0x28, 0xaa, 0xce, 0x3f, 0xfc, 0xf6, 0xc7, 0x8f,
+	0xb9, 0x15, 0xb3, 0xbc, 0x77, 0xfd, 0x9f, 0xbd, 0x50, 0x9a, 0xbe, 0x30, 0x76, 0x88, 0x05, 0xb5,
+	0x4c, 0x8b, 0x4d, 0xee, 0xe2, 0xd4, 0x45, 0xbf, 0x3e, 0xda, 0xed, 0x45, 0x26, 0xcd, 0x7e, 0x1f,
+	0xd9, 0x37, 0xcc, 0x86, 0x64, 0x9f, 0xa0, 0x8b, 0x56, 0xbb, 0x5c, 0xe5, 0x6b, 0x58, 0x52, 0x1d,
+	0x29, 0xd1, 0x91, 0xa5, 0xbb, 0xe3, 0xf6, 0x5a, 0x06, 0xd3, 0x84, 0x4d, 0x24, 0x5c, 0x35, 0x01,
+	0xc3, 0x45, 0x9b, 0x64, 0x7a, 0x0e, 0xcb, 0xba, 0x39, 0x25, 0x6a, 0x5a, 0xb6, 0x7b, 0x6d, 0xaf,
+	0x67, 0x41, 0x4d, 0x56, 0x47, 0x32, 0x20, 0x25, 0x49, 0x66, 0xcb, 0xc9, 0x01, 0xbe, 0x55, 0xb3,
+	0x9a, 0x7d, 0x10, 0xcd, 0x5e, 0xdc, 0x6c, 0xb5, 0xff, 0x75, 0xab, 0x5d, 0x2f, 0x74, 0x0f, 0x17,
+	0x6a, 0x92, 0x35, 0xb9, 0x90, 0x13, 0x3b, 0xa9, 0x56, 0xe5, 0x05, 0x94, 0xa2, 0x26, 0x87, 0xc4,
+	0x71, 0x66, 0xf8, 0x9b, 0x33, 0xa8, 0x66, 0x6d, 0x20, 0x6b, 0x85, 0xe0, 0xd1, 0x29, 0xae, 0x97,
+	0x00, 0x49, 0x5f, 0x41, 0x54, 0x1b, 0x3c, 0xd7, 0x7d, 0xb4, 0x37, 0xe7, 0x70, 0xcd, 0x48, 0x90,
+	0xb1, 0x4a, 0x30, 0xbb, 0xaa, 0xd9, 0x20, 0x17, 0x18, 0x1e, 0x3a, 0x26, 0xe1, 0xa5, 0x7b, 0x8f,
+	0x76, 0x73, 0x06, 0xcd, 0x9e, 0x3d, 0x59, 0x4f, 0xc8, 0xf6, 0xbe, 0xd7, 0x75, 0xe4, 0x3d, 0x71,
+	0xa0, 0x92, 0x6a, 0x16, 0x88, 0x0a, 0x69, 0xbe, 0xdd, 0x68, 0xb7, 0xe6, 0x0d, 0x9a, 0xff, 0x31,
+	0xf2, 0x3f, 0x32, 0xb7, 0x16, 0xf1, 0xef, 0x05, 0xc9, 0x0c, 0x29, 0x90, 0x57, 0x50, 0x49, 0xb5,
+	0x1b, 0x7a, 0xb9, 0xf9, 0xb6, 0xa4, 0xdd, 0x9a, 0x37, 0x64, 0x2f, 0x0a, 0xa9, 0xca, 0xe5, 0xa2,
+	0xfe, 0x83, 0xf4, 0x60, 0x75, 0xa6, 0x3a, 0x93, 0x7b, 0x91, 0x6e, 0x17, 0xd4, 0xec, 0x36, 0x24,
+	0xf5, 0x36, 0x9b, 0x6d, 0x8e, 0x6e, 0x4f, 0x8d, 0xc1, 0x12, 0xfe, 0xfb, 0xf1, 0xdf, 0xbf, 0x06,
+	0x00, 0x8d, 0xf0, 0xa4, 0x02, 0x2d, 0x11, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PoetClient is the client API for Poet service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PoetClient interface {
+	//*
+	//Start is used to start the service.
+	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error)
+	//*
+	//UpdateGateway allows to update the list of gateway addresses (with additional broadcasting config),
+	//similar to the Start rpc, but after the service already started.
+	UpdateGateway(ctx context.Context, in *UpdateGatewayRequest, opts ...grpc.CallOption) (*UpdateGatewayResponse, error)
+	//*
+	//Submit adds a challenge to the service's current open round,
+	//to be included its later generated proof.
+	//If the submission is rejected by an admission limit, a ResourceExhausted error is returned, whose details
+	//include an ErrorInfo with the limit as its reason (e.g. ROUND_FULL) and a retryNextRound metadata entry,
+	//and a RetryInfo if the submission was rate limited.
+	//A submission may be signed by the node identity, over the challenge and the round it's submitted to
+	//(see SubmitRequest). Each identity may submit a single challenge per round.
+	Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error)
+	//*
+	//GetInfo returns general information concerning the service,
+	//including its identity pubkey.
+	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+	//*
+	//GetMembershipProof returns a Merkle proof of the membership of a challenge
+	//in the members list of an executing or executed round, whose root is the round statement.
+	GetMembershipProof(ctx context.Context, in *GetMembershipProofRequest, opts ...grpc.CallOption) (*GetMembershipProofResponse, error)
+	//*
+	//GetProof returns the proof of a round whose execution ended, along with its members list.
+	//If wait is set, the call blocks until the round execution ends.
+	GetProof(ctx context.Context, in *GetProofRequest, opts ...grpc.CallOption) (*GetProofResponse, error)
+	//*
+	//ListRounds returns the status of all the rounds known to the service,
+	//including the archived ones, ordered by their opening time.
+	ListRounds(ctx context.Context, in *ListRoundsRequest, opts ...grpc.CallOption) (*ListRoundsResponse, error)
+	//*
+	//GetRound returns the status of a given round.
+	GetRound(ctx context.Context, in *GetRoundRequest, opts ...grpc.CallOption) (*GetRoundResponse, error)
+	//*
+	//Rebroadcast forces an immediate re-broadcast of the proof of a given round, which its broadcast is pending,
+	//either since it waits for a retry or since its retries ran out. The round broadcast retries are restarted.
+	Rebroadcast(ctx context.Context, in *RebroadcastRequest, opts ...grpc.CallOption) (*RebroadcastResponse, error)
+	//*
+	//GetGateways returns the connection status of the gateway nodes which proofs are broadcast to.
+	//Gateway nodes which aren't connected are reconnected in the background.
+	GetGateways(ctx context.Context, in *GetGatewaysRequest, opts ...grpc.CallOption) (*GetGatewaysResponse, error)
+	//*
+	//SubscribeEvents streams the rounds lifecycle events.
+	//If fromSeq is set, the stream resumes from the event with the given sequence number,
+	//as long as it is still kept by the service. Otherwise, only new events are streamed.
+	//The high 32 bits of the sequence numbers are the service start time, hence the events of
+	//a previous run of the service are reported as unavailable rather than being silently missed.
+	SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Poet_SubscribeEventsClient, error)
+}
+
+type poetClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPoetClient(cc grpc.ClientConnInterface) PoetClient {
+	return &poetClient{cc}
+}
+
+func (c *poetClient) Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error) {
+	out := new(StartResponse)
+	err := c.cc.Invoke(ctx, "/api.Poet/Start", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poetClient) UpdateGateway(ctx context.Context, in *UpdateGatewayRequest, opts ...grpc.CallOption) (*UpdateGatewayResponse, error) {
+	out := new(UpdateGatewayResponse)
+	err := c.cc.Invoke(ctx, "/api.Poet/UpdateGateway", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poetClient) Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error) {
 	out := new(SubmitResponse)
 	err := c.cc.Invoke(ctx, "/api.Poet/Submit", in, out, opts...)
 	if err != nil {
@@ -604,12 +1876,144 @@ func (c *poetClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...gr
 	return out, nil
 }
 
+func (c *poetClient) GetMembershipProof(ctx context.Context, in *GetMembershipProofRequest, opts ...grpc.CallOption) (*GetMembershipProofResponse, error) {
+	out := new(GetMembershipProofResponse)
+	err := c.cc.Invoke(ctx, "/api.Poet/GetMembershipProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poetClient) GetProof(ctx context.Context, in *GetProofRequest, opts ...grpc.CallOption) (*GetProofResponse, error) {
+	out := new(GetProofResponse)
+	err := c.cc.Invoke(ctx, "/api.Poet/GetProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poetClient) ListRounds(ctx context.Context, in *ListRoundsRequest, opts ...grpc.CallOption) (*ListRoundsResponse, error) {
+	out := new(ListRoundsResponse)
+	err := c.cc.Invoke(ctx, "/api.Poet/ListRounds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poetClient) GetRound(ctx context.Context, in *GetRoundRequest, opts ...grpc.CallOption) (*GetRoundResponse, error) {
+	out := new(GetRoundResponse)
+	err := c.cc.Invoke(ctx, "/api.Poet/GetRound", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poetClient) Rebroadcast(ctx context.Context, in *RebroadcastRequest, opts ...grpc.CallOption) (*RebroadcastResponse, error) {
+	out := new(RebroadcastResponse)
+	err := c.cc.Invoke(ctx, "/api.Poet/Rebroadcast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poetClient) GetGateways(ctx context.Context, in *GetGatewaysRequest, opts ...grpc.CallOption) (*GetGatewaysResponse, error) {
+	out := new(GetGatewaysResponse)
+	err := c.cc.Invoke(ctx, "/api.Poet/GetGateways", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poetClient) SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Poet_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Poet_serviceDesc.Streams[0], "/api.Poet/SubscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &poetSubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Poet_SubscribeEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type poetSubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *poetSubscribeEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PoetServer is the server API for Poet service.
 type PoetServer interface {
+	//*
+	//Start is used to start the service.
 	Start(context.Context, *StartRequest) (*StartResponse, error)
+	//*
+	//UpdateGateway allows to update the list of gateway addresses (with additional broadcasting config),
+	//similar to the Start rpc, but after the service already started.
 	UpdateGateway(context.Context, *UpdateGatewayRequest) (*UpdateGatewayResponse, error)
+	//*
+	//Submit adds a challenge to the service's current open round,
+	//to be included its later generated proof.
+	//If the submission is rejected by an admission limit, a ResourceExhausted error is returned, whose details
+	//include an ErrorInfo with the limit as its reason (e.g. ROUND_FULL) and a retryNextRound metadata entry,
+	//and a RetryInfo if the submission was rate limited.
+	//A submission may be signed by the node identity, over the challenge and the round it's submitted to
+	//(see SubmitRequest). Each identity may submit a single challenge per round.
 	Submit(context.Context, *SubmitRequest) (*SubmitResponse, error)
+	//*
+	//GetInfo returns general information concerning the service,
+	//including its identity pubkey.
 	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
+	//*
+	//GetMembershipProof returns a Merkle proof of the membership of a challenge
+	//in the members list of an executing or executed round, whose root is the round statement.
+	GetMembershipProof(context.Context, *GetMembershipProofRequest) (*GetMembershipProofResponse, error)
+	//*
+	//GetProof returns the proof of a round whose execution ended, along with its members list.
+	//If wait is set, the call blocks until the round execution ends.
+	GetProof(context.Context, *GetProofRequest) (*GetProofResponse, error)
+	//*
+	//ListRounds returns the status of all the rounds known to the service,
+	//including the archived ones, ordered by their opening time.
+	ListRounds(context.Context, *ListRoundsRequest) (*ListRoundsResponse, error)
+	//*
+	//GetRound returns the status of a given round.
+	GetRound(context.Context, *GetRoundRequest) (*GetRoundResponse, error)
+	//*
+	//Rebroadcast forces an immediate re-broadcast of the proof of a given round, which its broadcast is pending,
+	//either since it waits for a retry or since its retries ran out. The round broadcast retries are restarted.
+	Rebroadcast(context.Context, *RebroadcastRequest) (*RebroadcastResponse, error)
+	//*
+	//GetGateways returns the connection status of the gateway nodes which proofs are broadcast to.
+	//Gateway nodes which aren't connected are reconnected in the background.
+	GetGateways(context.Context, *GetGatewaysRequest) (*GetGatewaysResponse, error)
+	//*
+	//SubscribeEvents streams the rounds lifecycle events.
+	//If fromSeq is set, the stream resumes from the event with the given sequence number,
+	//as long as it is still kept by the service. Otherwise, only new events are streamed.
+	//The high 32 bits of the sequence numbers are the service start time, hence the events of
+	//a previous run of the service are reported as unavailable rather than being silently missed.
+	SubscribeEvents(*SubscribeEventsRequest, Poet_SubscribeEventsServer) error
 }
 
 // UnimplementedPoetServer can be embedded to have forward compatible implementations.
@@ -628,6 +2032,27 @@ func (*UnimplementedPoetServer) Submit(ctx context.Context, req *SubmitRequest)
 func (*UnimplementedPoetServer) GetInfo(ctx context.Context, req *GetInfoRequest) (*GetInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
 }
+func (*UnimplementedPoetServer) GetMembershipProof(ctx context.Context, req *GetMembershipProofRequest) (*GetMembershipProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMembershipProof not implemented")
+}
+func (*UnimplementedPoetServer) GetProof(ctx context.Context, req *GetProofRequest) (*GetProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProof not implemented")
+}
+func (*UnimplementedPoetServer) ListRounds(ctx context.Context, req *ListRoundsRequest) (*ListRoundsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRounds not implemented")
+}
+func (*UnimplementedPoetServer) GetRound(ctx context.Context, req *GetRoundRequest) (*GetRoundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRound not implemented")
+}
+func (*UnimplementedPoetServer) Rebroadcast(ctx context.Context, req *RebroadcastRequest) (*RebroadcastResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rebroadcast not implemented")
+}
+func (*UnimplementedPoetServer) GetGateways(ctx context.Context, req *GetGatewaysRequest) (*GetGatewaysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGateways not implemented")
+}
+func (*UnimplementedPoetServer) SubscribeEvents(req *SubscribeEventsRequest, srv Poet_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
 
 func RegisterPoetServer(s *grpc.Server, srv PoetServer) {
 	s.RegisterService(&_Poet_serviceDesc, srv)
@@ -705,6 +2130,135 @@ func _Poet_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Poet_GetMembershipProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMembershipProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoetServer).GetMembershipProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Poet/GetMembershipProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoetServer).GetMembershipProof(ctx, req.(*GetMembershipProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poet_GetProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoetServer).GetProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Poet/GetProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoetServer).GetProof(ctx, req.(*GetProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poet_ListRounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoetServer).ListRounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Poet/ListRounds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoetServer).ListRounds(ctx, req.(*ListRoundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poet_GetRound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoetServer).GetRound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Poet/GetRound",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoetServer).GetRound(ctx, req.(*GetRoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poet_Rebroadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebroadcastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoetServer).Rebroadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Poet/Rebroadcast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoetServer).Rebroadcast(ctx, req.(*RebroadcastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poet_GetGateways_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGatewaysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoetServer).GetGateways(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Poet/GetGateways",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoetServer).GetGateways(ctx, req.(*GetGatewaysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poet_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PoetServer).SubscribeEvents(m, &poetSubscribeEventsServer{stream})
+}
+
+type Poet_SubscribeEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type poetSubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *poetSubscribeEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Poet_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "api.Poet",
 	HandlerType: (*PoetServer)(nil),
@@ -725,7 +2279,37 @@ var _Poet_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetInfo",
 			Handler:    _Poet_GetInfo_Handler,
 		},
+		{
+			MethodName: "GetMembershipProof",
+			Handler:    _Poet_GetMembershipProof_Handler,
+		},
+		{
+			MethodName: "GetProof",
+			Handler:    _Poet_GetProof_Handler,
+		},
+		{
+			MethodName: "ListRounds",
+			Handler:    _Poet_ListRounds_Handler,
+		},
+		{
+			MethodName: "GetRound",
+			Handler:    _Poet_GetRound_Handler,
+		},
+		{
+			MethodName: "Rebroadcast",
+			Handler:    _Poet_Rebroadcast_Handler,
+		},
+		{
+			MethodName: "GetGateways",
+			Handler:    _Poet_GetGateways_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _Poet_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api.proto",
 }
@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spacemeshos/poet/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strconv"
+)
+
+// errorInfoDomain is the domain of the ErrorInfo details of the submission errors.
+const errorInfoDomain = "poet"
+
+// submitSignatureCodes are the gRPC status codes of the submission signature errors.
+var submitSignatureCodes = map[error]codes.Code{
+	service.ErrSubmissionSignatureRequired: codes.Unauthenticated,
+	service.ErrInvalidSubmissionSignature:  codes.Unauthenticated,
+	service.ErrSubmissionRoundNotOpen:      codes.FailedPrecondition,
+	service.ErrIdentityNotAllowed:          codes.PermissionDenied,
+	service.ErrIdentityAlreadySubmitted:    codes.AlreadyExists,
+}
+
+// serviceErrorCodes are the gRPC status codes of the service errors.
+var serviceErrorCodes = map[error]codes.Code{
+	service.ErrNotStarted:              codes.FailedPrecondition,
+	service.ErrAlreadyStarted:          codes.FailedPrecondition,
+	service.ErrRoundNotFound:           codes.NotFound,
+	service.ErrNotMember:               codes.NotFound,
+	service.ErrRoundMembersUnavailable: codes.FailedPrecondition,
+	service.ErrRoundNotBroadcasting:    codes.FailedPrecondition,
+	service.ErrRoundNotOpened:          codes.FailedPrecondition,
+	service.ErrRoundOpen:               codes.FailedPrecondition,
+	service.ErrRoundExecuting:          codes.FailedPrecondition,
+	service.ErrRoundNotExecuted:        codes.FailedPrecondition,
+	service.ErrEventsUnavailable:       codes.OutOfRange,
+	service.ErrSubscriptionOverflow:    codes.ResourceExhausted,
+	context.Canceled:                   codes.Canceled,
+	context.DeadlineExceeded:           codes.DeadlineExceeded,
+}
+
+// serviceError maps a service error to a gRPC status error, so that clients can tell a missing round
+// or a round which isn't in the requested phase from an internal failure. Other errors are returned as is.
+func serviceError(err error) error {
+	for serviceErr, code := range serviceErrorCodes {
+		if errors.Is(err, serviceErr) {
+			return status.Error(code, err.Error())
+		}
+	}
+	return err
+}
+
+// submitError maps a submission admission error to a ResourceExhausted gRPC status error. The status details
+// include an ErrorInfo, whose reason identifies the limit and whose metadata indicates whether to retry
+// in the next round, and a RetryInfo if the submission was rate limited. Submission signature errors are
+// mapped to their status codes, and other errors are mapped by serviceError.
+func submitError(err error) error {
+	for sigErr, code := range submitSignatureCodes {
+		if errors.Is(err, sigErr) {
+			return status.Error(code, err.Error())
+		}
+	}
+
+	var limitErr *service.SubmitLimitError
+	if !errors.As(err, &limitErr) {
+		return serviceError(err)
+	}
+
+	details := []proto.Message{&errdetails.ErrorInfo{
+		Reason:   string(limitErr.Limit),
+		Domain:   errorInfoDomain,
+		Metadata: map[string]string{"retryNextRound": strconv.FormatBool(limitErr.RetryNextRound)},
+	}}
+	if limitErr.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(limitErr.RetryAfter)})
+	}
+
+	st := status.New(codes.ResourceExhausted, err.Error())
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spacemeshos/poet/service"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestSubmitError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		code           codes.Code
+		reason         string
+		retryNextRound string
+		retryAfter     time.Duration
+	}{
+		{
+			name:           "rate limited",
+			err:            &service.SubmitLimitError{Limit: service.LimitPeerRate, Msg: "peer rate limited", RetryAfter: 3 * time.Second},
+			code:           codes.ResourceExhausted,
+			reason:         string(service.LimitPeerRate),
+			retryNextRound: "false",
+			retryAfter:     3 * time.Second,
+		},
+		{
+			name:           "round full",
+			err:            fmt.Errorf("submit: %w", &service.SubmitLimitError{Limit: service.LimitRoundMembers, Msg: "round is full", RetryNextRound: true}),
+			code:           codes.ResourceExhausted,
+			reason:         string(service.LimitRoundMembers),
+			retryNextRound: "true",
+		},
+		{
+			name: "signature required",
+			err:  service.ErrSubmissionSignatureRequired,
+			code: codes.Unauthenticated,
+		},
+		{
+			name: "not started",
+			err:  service.ErrNotStarted,
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "other",
+			err:  errors.New("failure"),
+			code: codes.Unknown,
+		},
+	}
+
+	for _, test := range tests {
+		req := require.New(t)
+		st := status.Convert(submitError(test.err))
+		req.Equal(test.code, st.Code(), test.name)
+		req.Equal(test.err.Error(), st.Message(), test.name)
+
+		details := st.Details()
+		if test.reason == "" {
+			req.Empty(details, test.name)
+			continue
+		}
+
+		info, ok := details[0].(*errdetails.ErrorInfo)
+		req.True(ok, test.name)
+		req.Equal(test.reason, info.Reason, test.name)
+		req.Equal(errorInfoDomain, info.Domain, test.name)
+		req.Equal(map[string]string{"retryNextRound": test.retryNextRound}, info.Metadata, test.name)
+
+		if test.retryAfter == 0 {
+			req.Len(details, 1, test.name)
+			continue
+		}
+		req.Len(details, 2, test.name)
+		retryInfo, ok := details[1].(*errdetails.RetryInfo)
+		req.True(ok, test.name)
+		retryAfter, err := ptypes.Duration(retryInfo.RetryDelay)
+		req.NoError(err)
+		req.Equal(test.retryAfter, retryAfter, test.name)
+	}
+}
+
+func TestServiceError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{service.ErrRoundNotFound, codes.NotFound},
+		{service.ErrNotMember, codes.NotFound},
+		{service.ErrRoundOpen, codes.FailedPrecondition},
+		{service.ErrRoundNotExecuted, codes.FailedPrecondition},
+		{service.ErrRoundNotBroadcasting, codes.FailedPrecondition},
+		{fmt.Errorf("round 1: %w", service.ErrRoundNotFound), codes.NotFound},
+		{errors.New("failure"), codes.Unknown},
+	}
+
+	for _, test := range tests {
+		st := status.Convert(serviceError(test.err))
+		require.Equal(t, test.code, st.Code(), test.err.Error())
+		require.Equal(t, test.err.Error(), st.Message())
+	}
+}
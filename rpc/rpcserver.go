@@ -1,17 +1,30 @@
 package rpc
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"github.com/spacemeshos/poet/broadcaster"
 	"github.com/spacemeshos/poet/rpc/api"
 	"github.com/spacemeshos/poet/service"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net"
+	"strings"
 	"sync"
+	"time"
 )
 
+// ProxyMetadataKey is the gRPC metadata key of the secret, by which the in-process REST proxy identifies its calls.
+const ProxyMetadataKey = "x-poet-proxy"
+
 // rpcServer is a gRPC, RPC front end to poet
 type rpcServer struct {
 	s *service.Service
+
+	// proxySecret identifies the calls of the in-process REST proxy, whose x-forwarded-for metadata is trusted.
+	proxySecret string
+
 	sync.Mutex
 }
 
@@ -19,10 +32,12 @@ type rpcServer struct {
 // the PoetServer gRPC rpc.
 var _ api.PoetServer = (*rpcServer)(nil)
 
-// NewRPCServer creates and returns a new instance of the rpcServer.
-func NewRPCServer(service *service.Service) *rpcServer {
+// NewRPCServer creates and returns a new instance of the rpcServer. proxySecret is the secret which the
+// in-process REST proxy attaches to its calls by the ProxyMetadataKey metadata.
+func NewRPCServer(service *service.Service, proxySecret string) *rpcServer {
 	return &rpcServer{
-		s: service,
+		s:           service,
+		proxySecret: proxySecret,
 	}
 }
 
@@ -31,7 +46,7 @@ func (r *rpcServer) Start(ctx context.Context, in *api.StartRequest) (*api.Start
 	defer r.Unlock()
 
 	if r.s.Started() {
-		return nil, service.ErrAlreadyStarted
+		return nil, serviceError(service.ErrAlreadyStarted)
 	}
 
 	connAcks := in.ConnAcksThreshold
@@ -46,6 +61,7 @@ func (r *rpcServer) Start(ctx context.Context, in *api.StartRequest) (*api.Start
 
 	b, err := broadcaster.New(
 		in.GatewayAddresses,
+		tlsConfigsFromWire(in.GatewayTls),
 		in.DisableBroadcast,
 		broadcaster.DefaultConnTimeout,
 		uint(connAcks),
@@ -57,6 +73,7 @@ func (r *rpcServer) Start(ctx context.Context, in *api.StartRequest) (*api.Start
 	}
 
 	if err := r.s.Start(b); err != nil {
+		b.Close()
 		return nil, fmt.Errorf("failed to start service: %v", err)
 	}
 	return &api.StartResponse{}, nil
@@ -67,7 +84,7 @@ func (r *rpcServer) UpdateGateway(ctx context.Context, in *api.UpdateGatewayRequ
 	defer r.Unlock()
 
 	if !r.s.Started() {
-		return nil, service.ErrNotStarted
+		return nil, serviceError(service.ErrNotStarted)
 	}
 
 	connAcks := in.ConnAcksThreshold
@@ -82,6 +99,7 @@ func (r *rpcServer) UpdateGateway(ctx context.Context, in *api.UpdateGatewayRequ
 
 	b, err := broadcaster.New(
 		in.GatewayAddresses,
+		tlsConfigsFromWire(in.GatewayTls),
 		in.DisableBroadcast,
 		broadcaster.DefaultConnTimeout,
 		uint(connAcks),
@@ -92,15 +110,28 @@ func (r *rpcServer) UpdateGateway(ctx context.Context, in *api.UpdateGatewayRequ
 		return nil, err
 	}
 
-	r.s.SetBroadcaster(b)
+	// The configured sinks are kept, and only the gateway nodes are replaced.
+	if err := r.s.SetBroadcaster(b); err != nil {
+		b.Close()
+		return nil, err
+	}
 
 	return &api.UpdateGatewayResponse{}, nil
 }
 
 func (r *rpcServer) Submit(ctx context.Context, in *api.SubmitRequest) (*api.SubmitResponse, error) {
-	round, err := r.s.Submit(in.Challenge)
+	var sig *service.SubmissionSignature
+	if len(in.PubKey) > 0 {
+		sig = &service.SubmissionSignature{
+			PubKey:    in.PubKey,
+			RoundID:   in.RoundId,
+			Signature: in.Signature,
+		}
+	}
+
+	round, err := r.s.SubmitSigned(in.Challenge, r.peerID(ctx), sig)
 	if err != nil {
-		return nil, err
+		return nil, submitError(err)
 	}
 
 	out := new(api.SubmitResponse)
@@ -111,7 +142,7 @@ func (r *rpcServer) Submit(ctx context.Context, in *api.SubmitRequest) (*api.Sub
 func (r *rpcServer) GetInfo(ctx context.Context, in *api.GetInfoRequest) (*api.GetInfoResponse, error) {
 	info, err := r.s.Info()
 	if err != nil {
-		return nil, err
+		return nil, serviceError(err)
 	}
 
 	out := new(api.GetInfoResponse)
@@ -124,5 +155,223 @@ func (r *rpcServer) GetInfo(ctx context.Context, in *api.GetInfoRequest) (*api.G
 	out.ExecutingRoundsIds = ids
 	out.ServicePubKey = r.s.PubKey
 
+	for _, id := range info.ExecutingRoundsIds {
+		progress, ok := info.ExecutingRoundsProgress[id]
+		if !ok {
+			continue
+		}
+		out.ExecutingRoundsProgress = append(out.ExecutingRoundsProgress, &api.ExecutionProgress{
+			RoundId:             id,
+			LeavesDone:          progress.LeavesDone,
+			NumLeaves:           progress.NumLeaves,
+			Rate:                progress.Rate,
+			EstimatedCompletion: unixTime(progress.EstimatedCompletion),
+		})
+	}
+
+	return out, nil
+}
+
+func (r *rpcServer) GetMembershipProof(ctx context.Context, in *api.GetMembershipProofRequest) (*api.GetMembershipProofResponse, error) {
+	mproof, err := r.s.MembershipProof(in.RoundId, in.Challenge)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	out := new(api.GetMembershipProofResponse)
+	out.Mproof = &api.MembershipProof{
+		Index: int32(mproof.Index),
+		Root:  mproof.Root,
+		Proof: mproof.Proof,
+	}
+
+	return out, nil
+}
+
+func (r *rpcServer) GetProof(ctx context.Context, in *api.GetProofRequest) (*api.GetProofResponse, error) {
+	proof, err := r.s.Proof(ctx, in.RoundId, in.Wait)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	out := new(api.GetProofResponse)
+	out.Proof = &api.PoetProof{
+		Phi:          proof.Proof.Root,
+		ProvenLeaves: proof.Proof.ProvenLeaves,
+		ProofNodes:   proof.Proof.ProofNodes,
+	}
+	out.N = uint32(proof.N)
+	out.NumLeaves = proof.NumLeaves
+	out.SecurityParam = proof.SecurityParam
+	out.Statement = proof.Statement
+	out.Members = proof.Members
+
+	return out, nil
+}
+
+func (r *rpcServer) ListRounds(ctx context.Context, in *api.ListRoundsRequest) (*api.ListRoundsResponse, error) {
+	rounds, err := r.s.Rounds()
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	out := new(api.ListRoundsResponse)
+	out.Rounds = make([]*api.RoundInfo, len(rounds))
+	for i, info := range rounds {
+		out.Rounds[i] = wireRoundInfo(info)
+	}
+
+	return out, nil
+}
+
+func (r *rpcServer) GetRound(ctx context.Context, in *api.GetRoundRequest) (*api.GetRoundResponse, error) {
+	info, err := r.s.Round(in.RoundId)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	out := new(api.GetRoundResponse)
+	out.Round = wireRoundInfo(info)
+
 	return out, nil
 }
+
+func (r *rpcServer) Rebroadcast(ctx context.Context, in *api.RebroadcastRequest) (*api.RebroadcastResponse, error) {
+	if err := r.s.Rebroadcast(in.RoundId); err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &api.RebroadcastResponse{}, nil
+}
+
+func (r *rpcServer) GetGateways(ctx context.Context, in *api.GetGatewaysRequest) (*api.GetGatewaysResponse, error) {
+	gateways, err := r.s.Gateways()
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	out := &api.GetGatewaysResponse{}
+	for _, g := range gateways {
+		out.Gateways = append(out.Gateways, &api.GatewayStatus{
+			Address:       g.Address,
+			Connected:     g.Connected,
+			State:         g.State,
+			LastError:     g.LastError,
+			LastErrorTime: unixTime(g.LastErrorTime),
+			LastBroadcast: unixTime(g.LastBroadcast),
+		})
+	}
+
+	return out, nil
+}
+
+func (r *rpcServer) SubscribeEvents(in *api.SubscribeEventsRequest, stream api.Poet_SubscribeEventsServer) error {
+	sub, err := r.s.SubscribeEvents(in.FromSeq)
+	if err != nil {
+		return serviceError(err)
+	}
+	defer sub.Close()
+
+	for {
+		e, err := sub.Next(stream.Context())
+		if err != nil {
+			return serviceError(err)
+		}
+
+		out := &api.Event{
+			Seq:     e.Seq,
+			Type:    api.EventType(e.Type),
+			RoundId: e.RoundID,
+			Time:    unixTime(e.Time),
+			Payload: e.Payload,
+		}
+		if err := stream.Send(out); err != nil {
+			return err
+		}
+	}
+}
+
+func wireRoundInfo(info *service.RoundInfo) *api.RoundInfo {
+	out := &api.RoundInfo{
+		Id:                   info.ID,
+		Phase:                api.RoundPhase(info.Phase),
+		Opened:               unixTime(info.Opened),
+		ExecutionStarted:     unixTime(info.ExecutionStarted),
+		NumMembers:           uint32(info.NumMembers),
+		NextLeafId:           info.NextLeafID,
+		NumLeaves:            info.NumLeaves,
+		LastBroadcastAttempt: unixTime(info.LastBroadcastAttempt),
+	}
+
+	if info.Outbox != nil {
+		for _, attempt := range info.Outbox.Attempts {
+			out.BroadcastAttempts = append(out.BroadcastAttempts, &api.BroadcastAttempt{
+				Time:    unixTime(attempt.Time),
+				Gateway: attempt.Gateway,
+				Error:   attempt.Error,
+			})
+		}
+		out.NextBroadcastRetry = unixTime(info.Outbox.NextRetry)
+		out.BroadcastExhausted = info.Outbox.Exhausted
+	}
+
+	return out
+}
+
+// peerID identifies the peer of a call by its host, for applying the per-peer submissions rate limit.
+// Calls which carry the proxy secret came via the in-process REST proxy, hence their peer is the last
+// x-forwarded-for address, which the proxy appends (the preceding ones are set by the client).
+func (r *rpcServer) peerID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if r.fromProxy(md) {
+		if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+			addrs := strings.Split(forwarded[len(forwarded)-1], ",")
+			return strings.TrimSpace(addrs[len(addrs)-1])
+		}
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return host
+}
+
+// fromProxy returns whether a call was made by the in-process REST proxy, by the proxy secret of its metadata.
+func (r *rpcServer) fromProxy(md metadata.MD) bool {
+	if r.proxySecret == "" {
+		return false
+	}
+	for _, secret := range md.Get(ProxyMetadataKey) {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(r.proxySecret)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// tlsConfigsFromWire returns the gateway nodes TLS configs, by their addresses.
+func tlsConfigsFromWire(wireConfigs []*api.GatewayTLS) map[string]*broadcaster.TLSConfig {
+	configs := make(map[string]*broadcaster.TLSConfig, len(wireConfigs))
+	for _, c := range wireConfigs {
+		configs[c.Address] = &broadcaster.TLSConfig{
+			CACertFile: c.CaCertFile,
+			CertFile:   c.CertFile,
+			KeyFile:    c.KeyFile,
+			ServerName: c.ServerName,
+		}
+	}
+	return configs
+}
+
+// unixTime returns the unix time of t in seconds, or 0 if t is zero.
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
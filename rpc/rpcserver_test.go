@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net"
+	"testing"
+)
+
+func TestRPCServer_PeerID(t *testing.T) {
+	const secret = "secret"
+	r := NewRPCServer(nil, secret)
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		peer string
+	}{
+		{
+			name: "direct call",
+			peer: "10.0.0.1",
+		},
+		{
+			name: "proxied call",
+			md:   metadata.Pairs(ProxyMetadataKey, secret, "x-forwarded-for", "192.168.0.1"),
+			peer: "192.168.0.1",
+		},
+		{
+			// The proxy appends the client address, while the preceding ones are set by the client.
+			name: "proxied call with a forwarding chain",
+			md:   metadata.Pairs(ProxyMetadataKey, secret, "x-forwarded-for", "1.1.1.1, 192.168.0.1"),
+			peer: "192.168.0.1",
+		},
+		{
+			name: "proxied call with multiple forwarding values",
+			md:   metadata.Pairs(ProxyMetadataKey, secret, "x-forwarded-for", "1.1.1.1", "x-forwarded-for", "192.168.0.2"),
+			peer: "192.168.0.2",
+		},
+		{
+			name: "forged forwarding without the secret",
+			md:   metadata.Pairs("x-forwarded-for", "1.1.1.1"),
+			peer: "10.0.0.1",
+		},
+		{
+			name: "forged forwarding with a wrong secret",
+			md:   metadata.Pairs(ProxyMetadataKey, "guess", "x-forwarded-for", "1.1.1.1"),
+			peer: "10.0.0.1",
+		},
+	}
+
+	for _, test := range tests {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+		if test.md != nil {
+			ctx = metadata.NewIncomingContext(ctx, test.md)
+		}
+		require.Equal(t, test.peer, r.peerID(ctx), test.name)
+	}
+
+	// Forwarding isn't trusted if the proxy secret isn't set.
+	r = NewRPCServer(nil, "")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ProxyMetadataKey, "", "x-forwarded-for", "1.1.1.1"))
+	require.Equal(t, "10.0.0.1", r.peerID(ctx))
+}
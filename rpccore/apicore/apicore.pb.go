@@ -3,14 +3,15 @@
 
 package apicore
 
-import proto "github.com/golang/protobuf/proto"
-import fmt "fmt"
-import math "math"
-import _ "google.golang.org/genproto/googleapis/api/annotations"
-
 import (
-	context "golang.org/x/net/context"
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -22,10 +23,42 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type JobStatus int32
+
+const (
+	JobStatus_RUNNING   JobStatus = 0
+	JobStatus_COMPLETED JobStatus = 1
+	JobStatus_FAILED    JobStatus = 2
+	JobStatus_CANCELLED JobStatus = 3
+)
+
+var JobStatus_name = map[int32]string{
+	0: "RUNNING",
+	1: "COMPLETED",
+	2: "FAILED",
+	3: "CANCELLED",
+}
+
+var JobStatus_value = map[string]int32{
+	"RUNNING":   0,
+	"COMPLETED": 1,
+	"FAILED":    2,
+	"CANCELLED": 3,
+}
+
+func (x JobStatus) String() string {
+	return proto.EnumName(JobStatus_name, int32(x))
+}
+
+func (JobStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_6d9c877e9a8fea47, []int{0}
+}
 
 type ComputeRequest struct {
 	D                    *DagParams `protobuf:"bytes,1,opt,name=d,proto3" json:"d,omitempty"`
+	Wait                 bool       `protobuf:"varint,2,opt,name=wait,proto3" json:"wait,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
 	XXX_unrecognized     []byte     `json:"-"`
 	XXX_sizecache        int32      `json:"-"`
@@ -35,16 +68,17 @@ func (m *ComputeRequest) Reset()         { *m = ComputeRequest{} }
 func (m *ComputeRequest) String() string { return proto.CompactTextString(m) }
 func (*ComputeRequest) ProtoMessage()    {}
 func (*ComputeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_apicore_a9b93ffafa709c25, []int{0}
+	return fileDescriptor_6d9c877e9a8fea47, []int{0}
 }
+
 func (m *ComputeRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ComputeRequest.Unmarshal(m, b)
 }
 func (m *ComputeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_ComputeRequest.Marshal(b, m, deterministic)
 }
-func (dst *ComputeRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ComputeRequest.Merge(dst, src)
+func (m *ComputeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ComputeRequest.Merge(m, src)
 }
 func (m *ComputeRequest) XXX_Size() int {
 	return xxx_messageInfo_ComputeRequest.Size(m)
@@ -62,8 +96,16 @@ func (m *ComputeRequest) GetD() *DagParams {
 	return nil
 }
 
+func (m *ComputeRequest) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
+}
+
 type ComputeResponse struct {
 	Phi                  []byte   `protobuf:"bytes,1,opt,name=phi,proto3" json:"phi,omitempty"`
+	JobId                string   `protobuf:"bytes,2,opt,name=jobId,json=job_id,proto3" json:"jobId,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -73,16 +115,17 @@ func (m *ComputeResponse) Reset()         { *m = ComputeResponse{} }
 func (m *ComputeResponse) String() string { return proto.CompactTextString(m) }
 func (*ComputeResponse) ProtoMessage()    {}
 func (*ComputeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_apicore_a9b93ffafa709c25, []int{1}
+	return fileDescriptor_6d9c877e9a8fea47, []int{1}
 }
+
 func (m *ComputeResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ComputeResponse.Unmarshal(m, b)
 }
 func (m *ComputeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_ComputeResponse.Marshal(b, m, deterministic)
 }
-func (dst *ComputeResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ComputeResponse.Merge(dst, src)
+func (m *ComputeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ComputeResponse.Merge(m, src)
 }
 func (m *ComputeResponse) XXX_Size() int {
 	return xxx_messageInfo_ComputeResponse.Size(m)
@@ -100,7 +143,251 @@ func (m *ComputeResponse) GetPhi() []byte {
 	return nil
 }
 
+func (m *ComputeResponse) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+type GetJobRequest struct {
+	JobId                string   `protobuf:"bytes,1,opt,name=jobId,json=job_id,proto3" json:"jobId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetJobRequest) Reset()         { *m = GetJobRequest{} }
+func (m *GetJobRequest) String() string { return proto.CompactTextString(m) }
+func (*GetJobRequest) ProtoMessage()    {}
+func (*GetJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6d9c877e9a8fea47, []int{2}
+}
+
+func (m *GetJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobRequest.Unmarshal(m, b)
+}
+func (m *GetJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobRequest.Marshal(b, m, deterministic)
+}
+func (m *GetJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobRequest.Merge(m, src)
+}
+func (m *GetJobRequest) XXX_Size() int {
+	return xxx_messageInfo_GetJobRequest.Size(m)
+}
+func (m *GetJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobRequest proto.InternalMessageInfo
+
+func (m *GetJobRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+type GetJobResponse struct {
+	Job                  *Job     `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetJobResponse) Reset()         { *m = GetJobResponse{} }
+func (m *GetJobResponse) String() string { return proto.CompactTextString(m) }
+func (*GetJobResponse) ProtoMessage()    {}
+func (*GetJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6d9c877e9a8fea47, []int{3}
+}
+
+func (m *GetJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobResponse.Unmarshal(m, b)
+}
+func (m *GetJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobResponse.Marshal(b, m, deterministic)
+}
+func (m *GetJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobResponse.Merge(m, src)
+}
+func (m *GetJobResponse) XXX_Size() int {
+	return xxx_messageInfo_GetJobResponse.Size(m)
+}
+func (m *GetJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobResponse proto.InternalMessageInfo
+
+func (m *GetJobResponse) GetJob() *Job {
+	if m != nil {
+		return m.Job
+	}
+	return nil
+}
+
+type CancelJobRequest struct {
+	JobId                string   `protobuf:"bytes,1,opt,name=jobId,json=job_id,proto3" json:"jobId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelJobRequest) Reset()         { *m = CancelJobRequest{} }
+func (m *CancelJobRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelJobRequest) ProtoMessage()    {}
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6d9c877e9a8fea47, []int{4}
+}
+
+func (m *CancelJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelJobRequest.Unmarshal(m, b)
+}
+func (m *CancelJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelJobRequest.Marshal(b, m, deterministic)
+}
+func (m *CancelJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelJobRequest.Merge(m, src)
+}
+func (m *CancelJobRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelJobRequest.Size(m)
+}
+func (m *CancelJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelJobRequest proto.InternalMessageInfo
+
+func (m *CancelJobRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+type CancelJobResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelJobResponse) Reset()         { *m = CancelJobResponse{} }
+func (m *CancelJobResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelJobResponse) ProtoMessage()    {}
+func (*CancelJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6d9c877e9a8fea47, []int{5}
+}
+
+func (m *CancelJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelJobResponse.Unmarshal(m, b)
+}
+func (m *CancelJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelJobResponse.Marshal(b, m, deterministic)
+}
+func (m *CancelJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelJobResponse.Merge(m, src)
+}
+func (m *CancelJobResponse) XXX_Size() int {
+	return xxx_messageInfo_CancelJobResponse.Size(m)
+}
+func (m *CancelJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelJobResponse proto.InternalMessageInfo
+
+// Job estimated completion time is in unix seconds, and is 0 if not applicable.
+type Job struct {
+	Id                   string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status               JobStatus `protobuf:"varint,2,opt,name=status,proto3,enum=apicore.JobStatus" json:"status,omitempty"`
+	LeavesDone           uint64    `protobuf:"varint,3,opt,name=leavesDone,json=leaves_done,proto3" json:"leavesDone,omitempty"`
+	NumLeaves            uint64    `protobuf:"varint,4,opt,name=numLeaves,json=num_leaves,proto3" json:"numLeaves,omitempty"`
+	Rate                 float64   `protobuf:"fixed64,5,opt,name=rate,proto3" json:"rate,omitempty"`
+	EstimatedCompletion  int64     `protobuf:"varint,6,opt,name=estimatedCompletion,json=estimated_completion,proto3" json:"estimatedCompletion,omitempty"`
+	Error                string    `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Job) Reset()         { *m = Job{} }
+func (m *Job) String() string { return proto.CompactTextString(m) }
+func (*Job) ProtoMessage()    {}
+func (*Job) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6d9c877e9a8fea47, []int{6}
+}
+
+func (m *Job) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Job.Unmarshal(m, b)
+}
+func (m *Job) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Job.Marshal(b, m, deterministic)
+}
+func (m *Job) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Job.Merge(m, src)
+}
+func (m *Job) XXX_Size() int {
+	return xxx_messageInfo_Job.Size(m)
+}
+func (m *Job) XXX_DiscardUnknown() {
+	xxx_messageInfo_Job.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Job proto.InternalMessageInfo
+
+func (m *Job) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Job) GetStatus() JobStatus {
+	if m != nil {
+		return m.Status
+	}
+	return JobStatus_RUNNING
+}
+
+func (m *Job) GetLeavesDone() uint64 {
+	if m != nil {
+		return m.LeavesDone
+	}
+	return 0
+}
+
+func (m *Job) GetNumLeaves() uint64 {
+	if m != nil {
+		return m.NumLeaves
+	}
+	return 0
+}
+
+func (m *Job) GetRate() float64 {
+	if m != nil {
+		return m.Rate
+	}
+	return 0
+}
+
+func (m *Job) GetEstimatedCompletion() int64 {
+	if m != nil {
+		return m.EstimatedCompletion
+	}
+	return 0
+}
+
+func (m *Job) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type GetNIPRequest struct {
+	JobId                string   `protobuf:"bytes,1,opt,name=jobId,json=job_id,proto3" json:"jobId,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -110,16 +397,17 @@ func (m *GetNIPRequest) Reset()         { *m = GetNIPRequest{} }
 func (m *GetNIPRequest) String() string { return proto.CompactTextString(m) }
 func (*GetNIPRequest) ProtoMessage()    {}
 func (*GetNIPRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_apicore_a9b93ffafa709c25, []int{2}
+	return fileDescriptor_6d9c877e9a8fea47, []int{7}
 }
+
 func (m *GetNIPRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetNIPRequest.Unmarshal(m, b)
 }
 func (m *GetNIPRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_GetNIPRequest.Marshal(b, m, deterministic)
 }
-func (dst *GetNIPRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetNIPRequest.Merge(dst, src)
+func (m *GetNIPRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetNIPRequest.Merge(m, src)
 }
 func (m *GetNIPRequest) XXX_Size() int {
 	return xxx_messageInfo_GetNIPRequest.Size(m)
@@ -130,6 +418,13 @@ func (m *GetNIPRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_GetNIPRequest proto.InternalMessageInfo
 
+func (m *GetNIPRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
 type GetNIPResponse struct {
 	Proof                *Proof   `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -141,16 +436,17 @@ func (m *GetNIPResponse) Reset()         { *m = GetNIPResponse{} }
 func (m *GetNIPResponse) String() string { return proto.CompactTextString(m) }
 func (*GetNIPResponse) ProtoMessage()    {}
 func (*GetNIPResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_apicore_a9b93ffafa709c25, []int{3}
+	return fileDescriptor_6d9c877e9a8fea47, []int{8}
 }
+
 func (m *GetNIPResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetNIPResponse.Unmarshal(m, b)
 }
 func (m *GetNIPResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_GetNIPResponse.Marshal(b, m, deterministic)
 }
-func (dst *GetNIPResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetNIPResponse.Merge(dst, src)
+func (m *GetNIPResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetNIPResponse.Merge(m, src)
 }
 func (m *GetNIPResponse) XXX_Size() int {
 	return xxx_messageInfo_GetNIPResponse.Size(m)
@@ -178,16 +474,17 @@ func (m *ShutdownRequest) Reset()         { *m = ShutdownRequest{} }
 func (m *ShutdownRequest) String() string { return proto.CompactTextString(m) }
 func (*ShutdownRequest) ProtoMessage()    {}
 func (*ShutdownRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_apicore_a9b93ffafa709c25, []int{4}
+	return fileDescriptor_6d9c877e9a8fea47, []int{9}
 }
+
 func (m *ShutdownRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ShutdownRequest.Unmarshal(m, b)
 }
 func (m *ShutdownRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_ShutdownRequest.Marshal(b, m, deterministic)
 }
-func (dst *ShutdownRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ShutdownRequest.Merge(dst, src)
+func (m *ShutdownRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShutdownRequest.Merge(m, src)
 }
 func (m *ShutdownRequest) XXX_Size() int {
 	return xxx_messageInfo_ShutdownRequest.Size(m)
@@ -208,16 +505,17 @@ func (m *ShutdownResponse) Reset()         { *m = ShutdownResponse{} }
 func (m *ShutdownResponse) String() string { return proto.CompactTextString(m) }
 func (*ShutdownResponse) ProtoMessage()    {}
 func (*ShutdownResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_apicore_a9b93ffafa709c25, []int{5}
+	return fileDescriptor_6d9c877e9a8fea47, []int{10}
 }
+
 func (m *ShutdownResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ShutdownResponse.Unmarshal(m, b)
 }
 func (m *ShutdownResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_ShutdownResponse.Marshal(b, m, deterministic)
 }
-func (dst *ShutdownResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ShutdownResponse.Merge(dst, src)
+func (m *ShutdownResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShutdownResponse.Merge(m, src)
 }
 func (m *ShutdownResponse) XXX_Size() int {
 	return xxx_messageInfo_ShutdownResponse.Size(m)
@@ -240,16 +538,17 @@ func (m *VerifyNIPRequest) Reset()         { *m = VerifyNIPRequest{} }
 func (m *VerifyNIPRequest) String() string { return proto.CompactTextString(m) }
 func (*VerifyNIPRequest) ProtoMessage()    {}
 func (*VerifyNIPRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_apicore_a9b93ffafa709c25, []int{6}
+	return fileDescriptor_6d9c877e9a8fea47, []int{11}
 }
+
 func (m *VerifyNIPRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VerifyNIPRequest.Unmarshal(m, b)
 }
 func (m *VerifyNIPRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_VerifyNIPRequest.Marshal(b, m, deterministic)
 }
-func (dst *VerifyNIPRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_VerifyNIPRequest.Merge(dst, src)
+func (m *VerifyNIPRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyNIPRequest.Merge(m, src)
 }
 func (m *VerifyNIPRequest) XXX_Size() int {
 	return xxx_messageInfo_VerifyNIPRequest.Size(m)
@@ -275,7 +574,11 @@ func (m *VerifyNIPRequest) GetP() *Proof {
 }
 
 type VerifyNIPResponse struct {
-	Verified             bool     `protobuf:"varint,1,opt,name=verified,proto3" json:"verified,omitempty"`
+	Verified bool `protobuf:"varint,1,opt,name=verified,proto3" json:"verified,omitempty"`
+	// error is the reason the NIP wasn't verified, if it wasn't.
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// reason identifies the verification failure, e.g. INVALID_MERKLE_PROOF or LABEL_MISMATCH, if the NIP wasn't verified.
+	Reason               string   `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -285,16 +588,17 @@ func (m *VerifyNIPResponse) Reset()         { *m = VerifyNIPResponse{} }
 func (m *VerifyNIPResponse) String() string { return proto.CompactTextString(m) }
 func (*VerifyNIPResponse) ProtoMessage()    {}
 func (*VerifyNIPResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_apicore_a9b93ffafa709c25, []int{7}
+	return fileDescriptor_6d9c877e9a8fea47, []int{12}
 }
+
 func (m *VerifyNIPResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VerifyNIPResponse.Unmarshal(m, b)
 }
 func (m *VerifyNIPResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_VerifyNIPResponse.Marshal(b, m, deterministic)
 }
-func (dst *VerifyNIPResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_VerifyNIPResponse.Merge(dst, src)
+func (m *VerifyNIPResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyNIPResponse.Merge(m, src)
 }
 func (m *VerifyNIPResponse) XXX_Size() int {
 	return xxx_messageInfo_VerifyNIPResponse.Size(m)
@@ -312,9 +616,156 @@ func (m *VerifyNIPResponse) GetVerified() bool {
 	return false
 }
 
+func (m *VerifyNIPResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *VerifyNIPResponse) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type VerifyNIPBatchRequest struct {
+	Requests             []*VerifyNIPRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *VerifyNIPBatchRequest) Reset()         { *m = VerifyNIPBatchRequest{} }
+func (m *VerifyNIPBatchRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyNIPBatchRequest) ProtoMessage()    {}
+func (*VerifyNIPBatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6d9c877e9a8fea47, []int{13}
+}
+
+func (m *VerifyNIPBatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyNIPBatchRequest.Unmarshal(m, b)
+}
+func (m *VerifyNIPBatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyNIPBatchRequest.Marshal(b, m, deterministic)
+}
+func (m *VerifyNIPBatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyNIPBatchRequest.Merge(m, src)
+}
+func (m *VerifyNIPBatchRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyNIPBatchRequest.Size(m)
+}
+func (m *VerifyNIPBatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyNIPBatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyNIPBatchRequest proto.InternalMessageInfo
+
+func (m *VerifyNIPBatchRequest) GetRequests() []*VerifyNIPRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+type VerifyNIPResult struct {
+	Verified bool `protobuf:"varint,1,opt,name=verified,proto3" json:"verified,omitempty"`
+	// error is the reason the NIP wasn't verified, if it wasn't.
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyNIPResult) Reset()         { *m = VerifyNIPResult{} }
+func (m *VerifyNIPResult) String() string { return proto.CompactTextString(m) }
+func (*VerifyNIPResult) ProtoMessage()    {}
+func (*VerifyNIPResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6d9c877e9a8fea47, []int{14}
+}
+
+func (m *VerifyNIPResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyNIPResult.Unmarshal(m, b)
+}
+func (m *VerifyNIPResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyNIPResult.Marshal(b, m, deterministic)
+}
+func (m *VerifyNIPResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyNIPResult.Merge(m, src)
+}
+func (m *VerifyNIPResult) XXX_Size() int {
+	return xxx_messageInfo_VerifyNIPResult.Size(m)
+}
+func (m *VerifyNIPResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyNIPResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyNIPResult proto.InternalMessageInfo
+
+func (m *VerifyNIPResult) GetVerified() bool {
+	if m != nil {
+		return m.Verified
+	}
+	return false
+}
+
+func (m *VerifyNIPResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type VerifyNIPBatchResponse struct {
+	Results              []*VerifyNIPResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *VerifyNIPBatchResponse) Reset()         { *m = VerifyNIPBatchResponse{} }
+func (m *VerifyNIPBatchResponse) String() string { return proto.CompactTextString(m) }
+func (*VerifyNIPBatchResponse) ProtoMessage()    {}
+func (*VerifyNIPBatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6d9c877e9a8fea47, []int{15}
+}
+
+func (m *VerifyNIPBatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyNIPBatchResponse.Unmarshal(m, b)
+}
+func (m *VerifyNIPBatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyNIPBatchResponse.Marshal(b, m, deterministic)
+}
+func (m *VerifyNIPBatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyNIPBatchResponse.Merge(m, src)
+}
+func (m *VerifyNIPBatchResponse) XXX_Size() int {
+	return xxx_messageInfo_VerifyNIPBatchResponse.Size(m)
+}
+func (m *VerifyNIPBatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyNIPBatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyNIPBatchResponse proto.InternalMessageInfo
+
+func (m *VerifyNIPBatchResponse) GetResults() []*VerifyNIPResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
 type DagParams struct {
-	X                    []byte   `protobuf:"bytes,1,opt,name=x,proto3" json:"x,omitempty"`
-	N                    uint32   `protobuf:"varint,2,opt,name=n,proto3" json:"n,omitempty"`
+	X []byte `protobuf:"bytes,1,opt,name=x,proto3" json:"x,omitempty"`
+	N uint32 `protobuf:"varint,2,opt,name=n,proto3" json:"n,omitempty"`
+	// numLeaves is the number of leaves of the DAG. If not specified, 2^n leaves are used.
+	NumLeaves uint64 `protobuf:"varint,3,opt,name=numLeaves,proto3" json:"numLeaves,omitempty"`
+	// memoryLayers is the number of top Merkle tree layers to cache in-memory.
+	// If not specified, the configured number is used.
+	MemoryLayers uint32 `protobuf:"varint,4,opt,name=memoryLayers,json=memory_layers,proto3" json:"memoryLayers,omitempty"`
+	// securityParam is the number of proven leaves. If not specified, the configured param is used.
+	SecurityParam        uint32   `protobuf:"varint,5,opt,name=securityParam,json=security_param,proto3" json:"securityParam,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -324,16 +775,17 @@ func (m *DagParams) Reset()         { *m = DagParams{} }
 func (m *DagParams) String() string { return proto.CompactTextString(m) }
 func (*DagParams) ProtoMessage()    {}
 func (*DagParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_apicore_a9b93ffafa709c25, []int{8}
+	return fileDescriptor_6d9c877e9a8fea47, []int{16}
 }
+
 func (m *DagParams) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DagParams.Unmarshal(m, b)
 }
 func (m *DagParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_DagParams.Marshal(b, m, deterministic)
 }
-func (dst *DagParams) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DagParams.Merge(dst, src)
+func (m *DagParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DagParams.Merge(m, src)
 }
 func (m *DagParams) XXX_Size() int {
 	return xxx_messageInfo_DagParams.Size(m)
@@ -358,6 +810,27 @@ func (m *DagParams) GetN() uint32 {
 	return 0
 }
 
+func (m *DagParams) GetNumLeaves() uint64 {
+	if m != nil {
+		return m.NumLeaves
+	}
+	return 0
+}
+
+func (m *DagParams) GetMemoryLayers() uint32 {
+	if m != nil {
+		return m.MemoryLayers
+	}
+	return 0
+}
+
+func (m *DagParams) GetSecurityParam() uint32 {
+	if m != nil {
+		return m.SecurityParam
+	}
+	return 0
+}
+
 type Proof struct {
 	Phi                  []byte   `protobuf:"bytes,1,opt,name=phi,proto3" json:"phi,omitempty"`
 	ProvenLeaves         [][]byte `protobuf:"bytes,2,rep,name=provenLeaves,json=proven_leaves,proto3" json:"provenLeaves,omitempty"`
@@ -371,16 +844,17 @@ func (m *Proof) Reset()         { *m = Proof{} }
 func (m *Proof) String() string { return proto.CompactTextString(m) }
 func (*Proof) ProtoMessage()    {}
 func (*Proof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_apicore_a9b93ffafa709c25, []int{9}
+	return fileDescriptor_6d9c877e9a8fea47, []int{17}
 }
+
 func (m *Proof) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Proof.Unmarshal(m, b)
 }
 func (m *Proof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_Proof.Marshal(b, m, deterministic)
 }
-func (dst *Proof) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Proof.Merge(dst, src)
+func (m *Proof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Proof.Merge(m, src)
 }
 func (m *Proof) XXX_Size() int {
 	return xxx_messageInfo_Proof.Size(m)
@@ -413,40 +887,132 @@ func (m *Proof) GetProofNodes() [][]byte {
 }
 
 func init() {
+	proto.RegisterEnum("apicore.JobStatus", JobStatus_name, JobStatus_value)
 	proto.RegisterType((*ComputeRequest)(nil), "apicore.ComputeRequest")
 	proto.RegisterType((*ComputeResponse)(nil), "apicore.ComputeResponse")
+	proto.RegisterType((*GetJobRequest)(nil), "apicore.GetJobRequest")
+	proto.RegisterType((*GetJobResponse)(nil), "apicore.GetJobResponse")
+	proto.RegisterType((*CancelJobRequest)(nil), "apicore.CancelJobRequest")
+	proto.RegisterType((*CancelJobResponse)(nil), "apicore.CancelJobResponse")
+	proto.RegisterType((*Job)(nil), "apicore.Job")
 	proto.RegisterType((*GetNIPRequest)(nil), "apicore.GetNIPRequest")
 	proto.RegisterType((*GetNIPResponse)(nil), "apicore.GetNIPResponse")
 	proto.RegisterType((*ShutdownRequest)(nil), "apicore.ShutdownRequest")
 	proto.RegisterType((*ShutdownResponse)(nil), "apicore.ShutdownResponse")
 	proto.RegisterType((*VerifyNIPRequest)(nil), "apicore.VerifyNIPRequest")
 	proto.RegisterType((*VerifyNIPResponse)(nil), "apicore.VerifyNIPResponse")
+	proto.RegisterType((*VerifyNIPBatchRequest)(nil), "apicore.VerifyNIPBatchRequest")
+	proto.RegisterType((*VerifyNIPResult)(nil), "apicore.VerifyNIPResult")
+	proto.RegisterType((*VerifyNIPBatchResponse)(nil), "apicore.VerifyNIPBatchResponse")
 	proto.RegisterType((*DagParams)(nil), "apicore.DagParams")
 	proto.RegisterType((*Proof)(nil), "apicore.Proof")
 }
 
+func init() {
+	proto.RegisterFile("apicore.proto", fileDescriptor_6d9c877e9a8fea47)
+}
+
+var fileDescriptor_6d9c877e9a8fea47 = []byte{
+	// 953 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0x4d, 0x6f, 0xdb, 0x46,
+	0x10, 0xed, 0x4a, 0xd1, 0xd7, 0xe8, 0xc3, 0xf2, 0xf8, 0x8b, 0x66, 0x5c, 0x5b, 0xa0, 0xdb, 0x40,
+	0xf1, 0x21, 0x6a, 0x54, 0xb4, 0x07, 0xdf, 0x12, 0xd9, 0x09, 0x64, 0xa8, 0xaa, 0xb0, 0x49, 0x83,
+	0xa2, 0x68, 0x21, 0x90, 0xe2, 0xc6, 0xa6, 0x21, 0x71, 0xd9, 0x25, 0xe5, 0x44, 0x28, 0x7a, 0xe9,
+	0x3f, 0x28, 0xfa, 0xd3, 0x7a, 0xef, 0xa9, 0x97, 0x1e, 0xfa, 0x1f, 0x8a, 0x5d, 0x2e, 0x29, 0xca,
+	0x96, 0x61, 0xf4, 0xb6, 0xfb, 0xe6, 0xf1, 0xbd, 0xd9, 0xd9, 0x9d, 0x01, 0xa1, 0x6e, 0x07, 0xde,
+	0x84, 0x0b, 0xf6, 0x2c, 0x10, 0x3c, 0xe2, 0x58, 0xd2, 0x5b, 0xf3, 0xe0, 0x92, 0xf3, 0xcb, 0x29,
+	0xeb, 0xd8, 0x81, 0xd7, 0xb1, 0x7d, 0x9f, 0x47, 0x76, 0xe4, 0x71, 0x3f, 0x8c, 0x69, 0xd6, 0x2b,
+	0x68, 0xf4, 0xf8, 0x2c, 0x98, 0x47, 0x8c, 0xb2, 0x9f, 0xe7, 0x2c, 0x8c, 0xb0, 0x05, 0xc4, 0x35,
+	0x48, 0x8b, 0xb4, 0xab, 0x5d, 0x7c, 0x96, 0x68, 0x9e, 0xd9, 0x97, 0x23, 0x5b, 0xd8, 0xb3, 0x90,
+	0x12, 0x17, 0x11, 0x1e, 0x7d, 0xb0, 0xbd, 0xc8, 0xc8, 0xb5, 0x48, 0xbb, 0x4c, 0xd5, 0xda, 0x3a,
+	0x85, 0x8d, 0x54, 0x27, 0x0c, 0xb8, 0x1f, 0x32, 0x6c, 0x42, 0x3e, 0xb8, 0xf2, 0x94, 0x54, 0x8d,
+	0xca, 0x25, 0xee, 0x40, 0xe1, 0x9a, 0x3b, 0x7d, 0x57, 0x7d, 0x59, 0xa1, 0xc5, 0x6b, 0xee, 0x8c,
+	0x3d, 0xd7, 0x7a, 0x02, 0xf5, 0xd7, 0x2c, 0xba, 0xe0, 0x4e, 0x92, 0x42, 0xca, 0x23, 0x2b, 0xbc,
+	0x2f, 0xa0, 0x91, 0xf0, 0xb4, 0xc5, 0x21, 0xe4, 0xaf, 0xb9, 0xa3, 0xb3, 0xad, 0xa5, 0xd9, 0x4a,
+	0x8a, 0x0c, 0x58, 0x4f, 0xa1, 0xd9, 0xb3, 0xfd, 0x09, 0x9b, 0x3e, 0x2c, 0xbe, 0x05, 0x9b, 0x19,
+	0x6a, 0xac, 0x6f, 0xfd, 0x45, 0x20, 0x7f, 0xc1, 0x1d, 0x6c, 0x40, 0xce, 0x4b, 0x3e, 0xc8, 0x79,
+	0x2e, 0x9e, 0x40, 0x31, 0x8c, 0xec, 0x68, 0x1e, 0xaa, 0x93, 0x34, 0x32, 0x85, 0xba, 0xe0, 0xce,
+	0x1b, 0x15, 0xa1, 0x9a, 0x81, 0x47, 0x00, 0x53, 0x66, 0xdf, 0xb0, 0xf0, 0x8c, 0xfb, 0xcc, 0xc8,
+	0xb7, 0x48, 0xfb, 0x11, 0xad, 0xc6, 0xc8, 0xd8, 0xe5, 0x3e, 0xc3, 0x4f, 0xa1, 0xe2, 0xcf, 0x67,
+	0x03, 0x85, 0x18, 0x8f, 0x54, 0x1c, 0xfc, 0xf9, 0x6c, 0x1c, 0x73, 0x64, 0xb5, 0x85, 0x1d, 0x31,
+	0xa3, 0xd0, 0x22, 0x6d, 0x42, 0xd5, 0x1a, 0x9f, 0xc3, 0x16, 0x0b, 0x23, 0x6f, 0x66, 0x47, 0xcc,
+	0x95, 0x65, 0x9f, 0x32, 0x79, 0xa7, 0x46, 0xb1, 0x45, 0xda, 0x79, 0xba, 0x9d, 0x86, 0xc6, 0x93,
+	0x34, 0x86, 0xdb, 0x50, 0x60, 0x42, 0x70, 0x61, 0x94, 0xd4, 0x29, 0xe2, 0x8d, 0x2e, 0xfd, 0xb0,
+	0x3f, 0x7a, 0xa0, 0x3a, 0x5f, 0x43, 0x23, 0xe1, 0xe9, 0xd2, 0x7f, 0x06, 0x85, 0x40, 0x70, 0xfe,
+	0x5e, 0x17, 0xbf, 0x91, 0x56, 0x60, 0x24, 0x51, 0x1a, 0x07, 0xad, 0x4d, 0xd8, 0x78, 0x73, 0x35,
+	0x8f, 0x5c, 0xfe, 0xc1, 0xd7, 0x0e, 0x16, 0x42, 0x73, 0x09, 0xe9, 0x3a, 0x53, 0x68, 0xbe, 0x63,
+	0xc2, 0x7b, 0xbf, 0xc8, 0x64, 0xf2, 0xf0, 0x3b, 0x3c, 0x00, 0x12, 0x18, 0xb9, 0xb5, 0xf6, 0x24,
+	0xb0, 0x7e, 0x82, 0xcd, 0x8c, 0xa6, 0xce, 0xda, 0x84, 0xf2, 0x8d, 0x04, 0x3d, 0x16, 0x6b, 0x97,
+	0x69, 0xba, 0x5f, 0x56, 0x28, 0x97, 0xa9, 0x10, 0xee, 0x42, 0x51, 0x30, 0x3b, 0xe4, 0xbe, 0xba,
+	0xba, 0x0a, 0xd5, 0x3b, 0x6b, 0x08, 0x3b, 0xa9, 0xfc, 0x4b, 0x3b, 0x9a, 0x5c, 0x25, 0x79, 0x7f,
+	0x05, 0x65, 0x11, 0x2f, 0x43, 0x83, 0xb4, 0xf2, 0xed, 0x6a, 0x77, 0x3f, 0x4d, 0xee, 0xf6, 0x21,
+	0x69, 0x4a, 0xb5, 0x7a, 0xb0, 0x91, 0x4d, 0x77, 0x3e, 0x8d, 0xfe, 0x7f, 0xb2, 0xd6, 0x00, 0x76,
+	0x6f, 0x27, 0xa5, 0x0f, 0xde, 0x85, 0x92, 0x50, 0xaa, 0x49, 0x52, 0xc6, 0xba, 0xa4, 0x24, 0x81,
+	0x26, 0x44, 0xeb, 0x77, 0x02, 0x95, 0xb4, 0xe0, 0x58, 0x03, 0xf2, 0x51, 0x37, 0x33, 0xf9, 0x28,
+	0x77, 0xbe, 0xf2, 0xae, 0x53, 0xe2, 0xe3, 0x41, 0xf6, 0x09, 0xc7, 0x4f, 0x7c, 0x09, 0xe0, 0x31,
+	0xd4, 0x66, 0x6c, 0xc6, 0xc5, 0x62, 0x60, 0x2f, 0x98, 0x88, 0xdf, 0x78, 0x9d, 0xd6, 0x63, 0x6c,
+	0x3c, 0x55, 0x20, 0x7e, 0x0e, 0xf5, 0x90, 0x4d, 0xe6, 0xc2, 0x8b, 0x16, 0xca, 0x50, 0xbd, 0xf7,
+	0x3a, 0x6d, 0x24, 0xe0, 0x38, 0x90, 0xa8, 0x35, 0x86, 0x82, 0xba, 0xe1, 0x35, 0xd3, 0xe5, 0x18,
+	0x6a, 0x81, 0xe0, 0x37, 0xcc, 0xd7, 0x79, 0xe4, 0x5a, 0xf9, 0x76, 0x8d, 0xd6, 0x63, 0x2c, 0xe9,
+	0xa6, 0x23, 0x00, 0xf5, 0x32, 0x87, 0xdc, 0x55, 0xa9, 0x4a, 0x4a, 0x55, 0x21, 0x63, 0x5f, 0x42,
+	0x27, 0x2f, 0xa1, 0x92, 0xf6, 0x30, 0x56, 0xa1, 0x44, 0xbf, 0x1b, 0x0e, 0xfb, 0xc3, 0xd7, 0xcd,
+	0x4f, 0xb0, 0x0e, 0x95, 0xde, 0xb7, 0xdf, 0x8c, 0x06, 0xe7, 0x6f, 0xcf, 0xcf, 0x9a, 0x04, 0x01,
+	0x8a, 0xaf, 0x5e, 0xf4, 0x07, 0xe7, 0x67, 0xcd, 0x9c, 0x0a, 0xbd, 0x18, 0xf6, 0xce, 0x07, 0x72,
+	0x9b, 0xef, 0xfe, 0x93, 0x87, 0xc6, 0x88, 0xb3, 0xa8, 0xc7, 0x05, 0x1b, 0x49, 0x7b, 0x81, 0xdf,
+	0x43, 0x49, 0xcf, 0x47, 0xdc, 0x4b, 0x2b, 0xbf, 0x3a, 0x79, 0x4d, 0xe3, 0x6e, 0x40, 0xf7, 0x87,
+	0xf9, 0xdb, 0x9f, 0x7f, 0xff, 0x91, 0xdb, 0x46, 0xec, 0xdc, 0x3c, 0xef, 0xa8, 0x03, 0x89, 0xce,
+	0x44, 0xcb, 0xfd, 0x00, 0xc5, 0x78, 0x2a, 0xe2, 0x6e, 0xfa, 0xfd, 0xca, 0x38, 0x35, 0xf7, 0xee,
+	0xe0, 0x5a, 0xf6, 0x48, 0xc9, 0xee, 0xe3, 0x5e, 0x46, 0xf6, 0x9a, 0x3b, 0x61, 0xe7, 0x17, 0x35,
+	0x03, 0x7e, 0xc5, 0x19, 0x54, 0xd2, 0xa1, 0x88, 0xcb, 0x67, 0x7c, 0x7b, 0xa6, 0x9a, 0xe6, 0xba,
+	0x90, 0x36, 0x79, 0xaa, 0x4c, 0x8e, 0x4f, 0xc9, 0x89, 0x75, 0x78, 0x8f, 0x4f, 0x67, 0xa2, 0xbe,
+	0xc2, 0xb7, 0xea, 0x28, 0xc3, 0xfe, 0x68, 0xf5, 0x28, 0xcb, 0x7e, 0x31, 0xf7, 0xee, 0xe0, 0xda,
+	0x65, 0x5f, 0xb9, 0x6c, 0xe1, 0x66, 0xc6, 0xe2, 0x92, 0x45, 0xbe, 0x17, 0xe0, 0x8f, 0x50, 0x4e,
+	0x06, 0x0e, 0x2e, 0x4b, 0x7c, 0x6b, 0x2c, 0x99, 0xfb, 0x6b, 0x22, 0x5a, 0xfb, 0xb1, 0xd2, 0xde,
+	0xc1, 0xad, 0x8c, 0x76, 0xa8, 0x49, 0xdd, 0x7f, 0x09, 0xd4, 0xe4, 0x5d, 0xbf, 0x8b, 0x3b, 0x53,
+	0xa0, 0x03, 0x95, 0xb4, 0xa3, 0xf0, 0xfe, 0xd6, 0x37, 0xcd, 0x75, 0x21, 0xed, 0x78, 0xa8, 0x1c,
+	0x0d, 0xdc, 0x95, 0x8e, 0xba, 0xe7, 0x45, 0xbc, 0x58, 0xc8, 0x23, 0x2d, 0xa0, 0xb1, 0xda, 0xe7,
+	0x78, 0x78, 0x57, 0x2d, 0x3b, 0x95, 0xcc, 0xa3, 0x7b, 0xe3, 0xda, 0xf2, 0x89, 0xb2, 0x6c, 0x59,
+	0x8f, 0xd7, 0x5b, 0x3a, 0x92, 0x7c, 0x4a, 0x4e, 0x9c, 0xa2, 0xfa, 0x6f, 0xf8, 0xf2, 0xbf, 0x01,
+	0x00, 0x5d, 0x05, 0xce, 0x27, 0x6f, 0x08, 0x00, 0x00,
+}
+
 // Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
-var _ grpc.ClientConn
+var _ grpc.ClientConnInterface
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+const _ = grpc.SupportPackageIsVersion6
 
 // PoetCoreProverClient is the client API for PoetCoreProver service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type PoetCoreProverClient interface {
+	//*
+	//Compute starts computing a NIP in a new job, and returns the job ID as its handle.
+	//If wait is set, the call blocks until the job completes, and the NIP root (phi) is returned as well.
+	//If the job doesn't complete, e.g. if the call deadline is exceeded while it's still running, the error details
+	//include an ErrorInfo with the job ID in its jobId metadata entry, so that the job can still be polled or cancelled.
+	//The number of concurrently running jobs may be limited, in which case RESOURCE_EXHAUSTED is returned once it's reached.
 	Compute(ctx context.Context, in *ComputeRequest, opts ...grpc.CallOption) (*ComputeResponse, error)
+	//*
+	//GetJob returns the status and progress of a given job.
+	GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error)
+	//*
+	//CancelJob cancels a given running job.
+	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error)
+	//*
+	//GetNIP returns the NIP of a given completed job.
+	//If job ID isn't specified, the NIP of the most recently completed job is returned.
 	GetNIP(ctx context.Context, in *GetNIPRequest, opts ...grpc.CallOption) (*GetNIPResponse, error)
 	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
 }
 
 type poetCoreProverClient struct {
-	cc *grpc.ClientConn
+	cc grpc.ClientConnInterface
 }
 
-func NewPoetCoreProverClient(cc *grpc.ClientConn) PoetCoreProverClient {
+func NewPoetCoreProverClient(cc grpc.ClientConnInterface) PoetCoreProverClient {
 	return &poetCoreProverClient{cc}
 }
 
@@ -459,6 +1025,24 @@ func (c *poetCoreProverClient) Compute(ctx context.Context, in *ComputeRequest,
 	return out, nil
 }
 
+func (c *poetCoreProverClient) GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error) {
+	out := new(GetJobResponse)
+	err := c.cc.Invoke(ctx, "/apicore.PoetCoreProver/GetJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poetCoreProverClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error) {
+	out := new(CancelJobResponse)
+	err := c.cc.Invoke(ctx, "/apicore.PoetCoreProver/CancelJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *poetCoreProverClient) GetNIP(ctx context.Context, in *GetNIPRequest, opts ...grpc.CallOption) (*GetNIPResponse, error) {
 	out := new(GetNIPResponse)
 	err := c.cc.Invoke(ctx, "/apicore.PoetCoreProver/GetNIP", in, out, opts...)
@@ -479,11 +1063,46 @@ func (c *poetCoreProverClient) Shutdown(ctx context.Context, in *ShutdownRequest
 
 // PoetCoreProverServer is the server API for PoetCoreProver service.
 type PoetCoreProverServer interface {
+	//*
+	//Compute starts computing a NIP in a new job, and returns the job ID as its handle.
+	//If wait is set, the call blocks until the job completes, and the NIP root (phi) is returned as well.
+	//If the job doesn't complete, e.g. if the call deadline is exceeded while it's still running, the error details
+	//include an ErrorInfo with the job ID in its jobId metadata entry, so that the job can still be polled or cancelled.
+	//The number of concurrently running jobs may be limited, in which case RESOURCE_EXHAUSTED is returned once it's reached.
 	Compute(context.Context, *ComputeRequest) (*ComputeResponse, error)
+	//*
+	//GetJob returns the status and progress of a given job.
+	GetJob(context.Context, *GetJobRequest) (*GetJobResponse, error)
+	//*
+	//CancelJob cancels a given running job.
+	CancelJob(context.Context, *CancelJobRequest) (*CancelJobResponse, error)
+	//*
+	//GetNIP returns the NIP of a given completed job.
+	//If job ID isn't specified, the NIP of the most recently completed job is returned.
 	GetNIP(context.Context, *GetNIPRequest) (*GetNIPResponse, error)
 	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
 }
 
+// UnimplementedPoetCoreProverServer can be embedded to have forward compatible implementations.
+type UnimplementedPoetCoreProverServer struct {
+}
+
+func (*UnimplementedPoetCoreProverServer) Compute(ctx context.Context, req *ComputeRequest) (*ComputeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Compute not implemented")
+}
+func (*UnimplementedPoetCoreProverServer) GetJob(ctx context.Context, req *GetJobRequest) (*GetJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (*UnimplementedPoetCoreProverServer) CancelJob(ctx context.Context, req *CancelJobRequest) (*CancelJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (*UnimplementedPoetCoreProverServer) GetNIP(ctx context.Context, req *GetNIPRequest) (*GetNIPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNIP not implemented")
+}
+func (*UnimplementedPoetCoreProverServer) Shutdown(ctx context.Context, req *ShutdownRequest) (*ShutdownResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Shutdown not implemented")
+}
+
 func RegisterPoetCoreProverServer(s *grpc.Server, srv PoetCoreProverServer) {
 	s.RegisterService(&_PoetCoreProver_serviceDesc, srv)
 }
@@ -506,6 +1125,42 @@ func _PoetCoreProver_Compute_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PoetCoreProver_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoetCoreProverServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apicore.PoetCoreProver/GetJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoetCoreProverServer).GetJob(ctx, req.(*GetJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PoetCoreProver_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoetCoreProverServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apicore.PoetCoreProver/CancelJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoetCoreProverServer).CancelJob(ctx, req.(*CancelJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PoetCoreProver_GetNIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetNIPRequest)
 	if err := dec(in); err != nil {
@@ -550,6 +1205,14 @@ var _PoetCoreProver_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Compute",
 			Handler:    _PoetCoreProver_Compute_Handler,
 		},
+		{
+			MethodName: "GetJob",
+			Handler:    _PoetCoreProver_GetJob_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _PoetCoreProver_CancelJob_Handler,
+		},
 		{
 			MethodName: "GetNIP",
 			Handler:    _PoetCoreProver_GetNIP_Handler,
@@ -567,14 +1230,23 @@ var _PoetCoreProver_serviceDesc = grpc.ServiceDesc{
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type PoetVerifierClient interface {
+	//*
+	//VerifyNIP verifies a given NIP. If it fails the verification, it's reported as not verified, along with
+	//the failure reason (e.g. LABEL_MISMATCH). If the request is malformed, e.g. if the NIP doesn't match the DAG
+	//params, an InvalidArgument error is returned, whose details include an ErrorInfo with the failure reason
+	//(e.g. MALFORMED_MERKLE_PROOF) and its specifics in the metadata.
 	VerifyNIP(ctx context.Context, in *VerifyNIPRequest, opts ...grpc.CallOption) (*VerifyNIPResponse, error)
+	//*
+	//VerifyNIPBatch verifies a batch of NIPs concurrently, and returns the result of each, in the batch order.
+	//An invalid NIP doesn't stop the verification of the others. A batch may include up to 256 NIPs.
+	VerifyNIPBatch(ctx context.Context, in *VerifyNIPBatchRequest, opts ...grpc.CallOption) (*VerifyNIPBatchResponse, error)
 }
 
 type poetVerifierClient struct {
-	cc *grpc.ClientConn
+	cc grpc.ClientConnInterface
 }
 
-func NewPoetVerifierClient(cc *grpc.ClientConn) PoetVerifierClient {
+func NewPoetVerifierClient(cc grpc.ClientConnInterface) PoetVerifierClient {
 	return &poetVerifierClient{cc}
 }
 
@@ -587,9 +1259,38 @@ func (c *poetVerifierClient) VerifyNIP(ctx context.Context, in *VerifyNIPRequest
 	return out, nil
 }
 
+func (c *poetVerifierClient) VerifyNIPBatch(ctx context.Context, in *VerifyNIPBatchRequest, opts ...grpc.CallOption) (*VerifyNIPBatchResponse, error) {
+	out := new(VerifyNIPBatchResponse)
+	err := c.cc.Invoke(ctx, "/apicore.PoetVerifier/VerifyNIPBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PoetVerifierServer is the server API for PoetVerifier service.
 type PoetVerifierServer interface {
+	//*
+	//VerifyNIP verifies a given NIP. If it fails the verification, it's reported as not verified, along with
+	//the failure reason (e.g. LABEL_MISMATCH). If the request is malformed, e.g. if the NIP doesn't match the DAG
+	//params, an InvalidArgument error is returned, whose details include an ErrorInfo with the failure reason
+	//(e.g. MALFORMED_MERKLE_PROOF) and its specifics in the metadata.
 	VerifyNIP(context.Context, *VerifyNIPRequest) (*VerifyNIPResponse, error)
+	//*
+	//VerifyNIPBatch verifies a batch of NIPs concurrently, and returns the result of each, in the batch order.
+	//An invalid NIP doesn't stop the verification of the others. A batch may include up to 256 NIPs.
+	VerifyNIPBatch(context.Context, *VerifyNIPBatchRequest) (*VerifyNIPBatchResponse, error)
+}
+
+// UnimplementedPoetVerifierServer can be embedded to have forward compatible implementations.
+type UnimplementedPoetVerifierServer struct {
+}
+
+func (*UnimplementedPoetVerifierServer) VerifyNIP(ctx context.Context, req *VerifyNIPRequest) (*VerifyNIPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyNIP not implemented")
+}
+func (*UnimplementedPoetVerifierServer) VerifyNIPBatch(ctx context.Context, req *VerifyNIPBatchRequest) (*VerifyNIPBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyNIPBatch not implemented")
 }
 
 func RegisterPoetVerifierServer(s *grpc.Server, srv PoetVerifierServer) {
@@ -614,6 +1315,24 @@ func _PoetVerifier_VerifyNIP_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PoetVerifier_VerifyNIPBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyNIPBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoetVerifierServer).VerifyNIPBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apicore.PoetVerifier/VerifyNIPBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoetVerifierServer).VerifyNIPBatch(ctx, req.(*VerifyNIPBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _PoetVerifier_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "apicore.PoetVerifier",
 	HandlerType: (*PoetVerifierServer)(nil),
@@ -622,43 +1341,11 @@ var _PoetVerifier_serviceDesc = grpc.ServiceDesc{
 			MethodName: "VerifyNIP",
 			Handler:    _PoetVerifier_VerifyNIP_Handler,
 		},
+		{
+			MethodName: "VerifyNIPBatch",
+			Handler:    _PoetVerifier_VerifyNIPBatch_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "apicore.proto",
 }
-
-func init() { proto.RegisterFile("apicore.proto", fileDescriptor_apicore_a9b93ffafa709c25) }
-
-var fileDescriptor_apicore_a9b93ffafa709c25 = []byte{
-	// 472 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x93, 0xdf, 0x4a, 0x1b, 0x41,
-	0x14, 0xc6, 0x99, 0x04, 0x35, 0x1e, 0x37, 0xff, 0x8e, 0xad, 0xae, 0x5b, 0x69, 0xc3, 0x58, 0xa8,
-	0x57, 0x2e, 0x4d, 0xa1, 0x2f, 0x60, 0xa1, 0x14, 0x8a, 0x2c, 0xdb, 0x22, 0xbd, 0x28, 0x84, 0xd5,
-	0x1c, 0xe3, 0x82, 0xce, 0x99, 0xce, 0x4e, 0x52, 0xbd, 0xed, 0x2b, 0xf4, 0xbe, 0x2f, 0xd5, 0x57,
-	0xe8, 0x83, 0x94, 0x9d, 0x9d, 0x6c, 0xa2, 0x09, 0x78, 0xb7, 0xf3, 0x9d, 0x39, 0xbf, 0x6f, 0xe6,
-	0x7c, 0xb3, 0xd0, 0xce, 0x74, 0x7e, 0xc9, 0x86, 0x4e, 0xb4, 0x61, 0xcb, 0xb8, 0xe5, 0x97, 0xd1,
-	0xe1, 0x84, 0x79, 0x72, 0x43, 0x71, 0xa6, 0xf3, 0x38, 0x53, 0x8a, 0x6d, 0x66, 0x73, 0x56, 0x45,
-	0xb5, 0x4d, 0x0e, 0xa1, 0x73, 0xca, 0xb7, 0x7a, 0x6a, 0x29, 0xa5, 0x1f, 0x53, 0x2a, 0x2c, 0x0e,
-	0x40, 0x8c, 0x43, 0x31, 0x10, 0xc7, 0x3b, 0x43, 0x3c, 0x99, 0x33, 0x3f, 0x64, 0x93, 0x24, 0x33,
-	0xd9, 0x6d, 0x91, 0x8a, 0xb1, 0x3c, 0x82, 0x6e, 0xdd, 0x53, 0x68, 0x56, 0x05, 0x61, 0x0f, 0x9a,
-	0xfa, 0x3a, 0x77, 0x6d, 0x41, 0x5a, 0x7e, 0xca, 0x2e, 0xb4, 0x3f, 0x92, 0x3d, 0xfb, 0x94, 0x78,
-	0xae, 0x7c, 0x0f, 0x9d, 0xb9, 0xe0, 0x9b, 0x5e, 0xc3, 0x86, 0x36, 0xcc, 0x57, 0xde, 0xad, 0x53,
-	0xbb, 0x25, 0xa5, 0x9a, 0x56, 0x45, 0xd9, 0x87, 0xee, 0x97, 0xeb, 0xa9, 0x1d, 0xf3, 0x4f, 0x35,
-	0x47, 0x21, 0xf4, 0x16, 0x52, 0x05, 0x93, 0x29, 0xf4, 0xce, 0xc9, 0xe4, 0x57, 0xf7, 0x0b, 0xcb,
-	0xa7, 0xaf, 0x82, 0x87, 0x20, 0x74, 0xd8, 0x58, 0x6b, 0x2f, 0xb4, 0x8c, 0xa1, 0xbf, 0xc4, 0xf4,
-	0xa7, 0x8e, 0xa0, 0x35, 0x2b, 0xc5, 0x9c, 0x2a, 0x76, 0x2b, 0xad, 0xd7, 0xf2, 0x0d, 0x6c, 0xd7,
-	0x78, 0x0c, 0x40, 0xdc, 0xf9, 0x89, 0x88, 0xbb, 0x72, 0xa5, 0x9c, 0x53, 0x3b, 0x15, 0x4a, 0x8e,
-	0x60, 0xc3, 0xb9, 0xac, 0x0e, 0x0e, 0x8f, 0x20, 0xd0, 0x86, 0x67, 0xa4, 0x3e, 0x53, 0x36, 0xa3,
-	0x22, 0x6c, 0x0c, 0x9a, 0xc7, 0x41, 0xda, 0xae, 0xb4, 0xd1, 0x8d, 0x13, 0xf1, 0x15, 0x80, 0x9b,
-	0xce, 0x19, 0x8f, 0xa9, 0x08, 0x9b, 0x6e, 0xcb, 0x8e, 0x53, 0x46, 0xaa, 0x94, 0x86, 0x7f, 0x1a,
-	0xd0, 0x49, 0x98, 0xec, 0x29, 0x1b, 0x4a, 0xca, 0x56, 0x83, 0xdf, 0x60, 0xcb, 0xc7, 0x86, 0xfb,
-	0xf5, 0x5d, 0x1f, 0x86, 0x1f, 0x85, 0xab, 0x05, 0x3f, 0xdf, 0xe8, 0xd7, 0xdf, 0x7f, 0xbf, 0x1b,
-	0xcf, 0x10, 0xe3, 0xd9, 0xdb, 0xd8, 0x1d, 0xc6, 0xc4, 0x97, 0x1e, 0xf7, 0x15, 0x36, 0xab, 0x68,
-	0x71, 0xaf, 0xee, 0x7f, 0x10, 0x7e, 0xb4, 0xbf, 0xa2, 0x7b, 0xec, 0x81, 0xc3, 0xee, 0x62, 0x7f,
-	0x09, 0x3b, 0x21, 0xab, 0x72, 0x8d, 0xdf, 0xa1, 0x35, 0x4f, 0x19, 0x17, 0xe7, 0x7a, 0xf4, 0x16,
-	0xa2, 0x83, 0x35, 0x15, 0xcf, 0x7e, 0xe1, 0xd8, 0xcf, 0x71, 0x77, 0x89, 0x5d, 0xf8, 0x4d, 0x43,
-	0x03, 0x41, 0x39, 0x9f, 0xf3, 0x2a, 0x3a, 0x83, 0x17, 0xb0, 0x5d, 0x67, 0x8d, 0x0b, 0xe8, 0xe3,
-	0x37, 0x15, 0x45, 0xeb, 0x4a, 0xde, 0xf0, 0xa5, 0x33, 0x0c, 0x71, 0xaf, 0x34, 0xf4, 0x8f, 0xc2,
-	0x54, 0x1f, 0xf7, 0x2a, 0xd7, 0x17, 0x9b, 0xee, 0x9f, 0x7b, 0xf7, 0x3f, 0x00, 0x00, 0xff, 0xff,
-	0xde, 0xe8, 0xa8, 0x5a, 0xab, 0x03, 0x00, 0x00,
-}
@@ -1,6 +1,8 @@
 /*
 Package rpccore provides definition and implementation of the gRPC server of the
 poet core functionality for generating and validating proofs.
+Proofs are computed in jobs which run in the background, each in its own data directory,
+and are identified by their job ID for querying their status, cancelling them and fetching their proof.
 The main use-case of this server is integration tests.
 */
 package rpccore
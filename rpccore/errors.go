@@ -0,0 +1,90 @@
+package rpccore
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/spacemeshos/poet/verifier"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorInfoDomain is the domain of the ErrorInfo details of the verification and job errors.
+const errorInfoDomain = "poet"
+
+// verificationFailureReason returns the reason of a proof validation error, if the proof was checked and failed
+// the verification, as opposed to a malformed proof, which doesn't match the request params.
+func verificationFailureReason(err error) (string, bool) {
+	var label *verifier.LabelMismatchError
+	switch {
+	case errors.Is(err, verifier.ErrInvalidMerkleProof):
+		return "INVALID_MERKLE_PROOF", true
+	case errors.As(err, &label):
+		return "LABEL_MISMATCH", true
+	default:
+		return "", false
+	}
+}
+
+// verificationError maps a malformed proof error to an InvalidArgument gRPC status error. The status details include
+// an ErrorInfo, whose reason identifies the failure and whose metadata holds its specifics, so that callers can handle
+// it in code. Other errors are mapped to an Internal error.
+func verificationError(err error) error {
+	var (
+		mismatch  *verifier.SecurityParamMismatchError
+		malformed *verifier.MalformedMerkleProofError
+	)
+
+	var info *errdetails.ErrorInfo
+	var badRequest *errdetails.BadRequest
+	switch {
+	case errors.As(err, &mismatch):
+		info = &errdetails.ErrorInfo{
+			Reason: "SECURITY_PARAM_MISMATCH",
+			Metadata: map[string]string{
+				"numProvenLeaves": fmt.Sprint(mismatch.NumProvenLeaves),
+				"securityParam":   fmt.Sprint(mismatch.SecurityParam),
+			},
+		}
+		badRequest = &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "p.provenLeaves", Description: err.Error()},
+			},
+		}
+	case errors.As(err, &malformed):
+		info = &errdetails.ErrorInfo{
+			Reason:   "MALFORMED_MERKLE_PROOF",
+			Metadata: map[string]string{"cause": malformed.Err.Error()},
+		}
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	info.Domain = errorInfoDomain
+	details := []proto.Message{info}
+	if badRequest != nil {
+		details = append(details, badRequest)
+	}
+
+	st := status.New(codes.InvalidArgument, err.Error())
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// jobError returns a gRPC status error of a job which didn't complete. The status details include an ErrorInfo
+// with the job ID in its metadata, so that the job can still be polled or cancelled.
+func jobError(code codes.Code, reason string, jobID string, msg string) error {
+	st := status.New(code, msg)
+	info := &errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   errorInfoDomain,
+		Metadata: map[string]string{"jobId": jobID},
+	}
+	if withDetails, err := st.WithDetails(info); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
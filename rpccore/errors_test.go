@@ -0,0 +1,92 @@
+package rpccore
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spacemeshos/poet/verifier"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestVerificationError(t *testing.T) {
+	tests := []struct {
+		err        error
+		reason     string
+		metadata   map[string]string
+		badRequest bool
+	}{
+		{
+			err:        &verifier.SecurityParamMismatchError{NumProvenLeaves: 3, SecurityParam: 4},
+			reason:     "SECURITY_PARAM_MISMATCH",
+			metadata:   map[string]string{"numProvenLeaves": "3", "securityParam": "4"},
+			badRequest: true,
+		},
+		{
+			err:      &verifier.MalformedMerkleProofError{Err: errors.New("missing nodes")},
+			reason:   "MALFORMED_MERKLE_PROOF",
+			metadata: map[string]string{"cause": "missing nodes"},
+		},
+		{
+			// Wrapped errors are mapped by their type as well.
+			err:      fmt.Errorf("proof: %w", &verifier.MalformedMerkleProofError{Err: errors.New("missing leaves")}),
+			reason:   "MALFORMED_MERKLE_PROOF",
+			metadata: map[string]string{"cause": "missing leaves"},
+		},
+	}
+
+	for _, test := range tests {
+		req := require.New(t)
+		st := status.Convert(verificationError(test.err))
+		req.Equal(codes.InvalidArgument, st.Code(), test.reason)
+		req.Equal(test.err.Error(), st.Message(), test.reason)
+
+		details := st.Details()
+		info, ok := details[0].(*errdetails.ErrorInfo)
+		req.True(ok, test.reason)
+		req.Equal(test.reason, info.Reason)
+		req.Equal(errorInfoDomain, info.Domain)
+		req.Equal(len(test.metadata), len(info.Metadata), test.reason)
+		for k, v := range test.metadata {
+			req.Equal(v, info.Metadata[k], "%v: %v", test.reason, k)
+		}
+
+		if !test.badRequest {
+			req.Len(details, 1, test.reason)
+			continue
+		}
+		req.Len(details, 2, test.reason)
+		badRequest, ok := details[1].(*errdetails.BadRequest)
+		req.True(ok, test.reason)
+		req.Len(badRequest.FieldViolations, 1)
+		req.Equal("p.provenLeaves", badRequest.FieldViolations[0].Field)
+	}
+
+	// Other errors are internal errors, without details.
+	st := status.Convert(verificationError(errors.New("unexpected")))
+	require.Equal(t, codes.Internal, st.Code())
+	require.Empty(t, st.Details())
+}
+
+func TestVerificationFailureReason(t *testing.T) {
+	tests := []struct {
+		err    error
+		reason string
+	}{
+		{err: verifier.ErrInvalidMerkleProof, reason: "INVALID_MERKLE_PROOF"},
+		{err: fmt.Errorf("proof: %w", &verifier.LabelMismatchError{Index: 1, LeafID: 7}), reason: "LABEL_MISMATCH"},
+
+		// Malformed proofs, and the absence of an error, aren't verification failures.
+		{err: &verifier.SecurityParamMismatchError{NumProvenLeaves: 3, SecurityParam: 4}},
+		{err: &verifier.MalformedMerkleProofError{Err: errors.New("missing nodes")}},
+		{err: nil},
+	}
+
+	for _, test := range tests {
+		reason, ok := verificationFailureReason(test.err)
+		require.Equal(t, test.reason != "", ok, test.err)
+		require.Equal(t, test.reason, reason)
+	}
+}
@@ -0,0 +1,136 @@
+package rpccore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/spacemeshos/merkle-tree"
+	"github.com/spacemeshos/merkle-tree/cache"
+	"github.com/spacemeshos/poet/hash"
+	"github.com/spacemeshos/poet/prover"
+	"github.com/spacemeshos/poet/shared"
+	"github.com/spacemeshos/poet/signal"
+	"github.com/spacemeshos/smutil/log"
+	"os"
+	"sync"
+	"time"
+)
+
+type jobStatus int
+
+const (
+	jobRunning jobStatus = iota
+	jobCompleted
+	jobFailed
+	jobCancelled
+)
+
+// job is a NIP computation which runs in the background, in its own data directory.
+type job struct {
+	id      string
+	datadir string
+
+	// ctx is done once the job is cancelled, or once it ends.
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+
+	status   jobStatus
+	progress prover.Progress
+	proof    *shared.MerkleProof
+	err      error
+	ended    time.Time
+
+	// done is closed once the job ends.
+	done chan struct{}
+	sync.Mutex
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func newJob(id, datadir string) *job {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &job{
+		id:         id,
+		datadir:    datadir,
+		ctx:        ctx,
+		cancelFunc: cancel,
+		done:       make(chan struct{}),
+	}
+}
+
+// run computes the NIP, and removes the job data directory once it ends. The computation stops
+// if the job is cancelled, or if a shutdown is requested by sig.
+func (j *job) run(sig *signal.Signal, params *dagParams) {
+	j.Lock()
+	j.progress = prover.Progress{NumLeaves: params.numLeaves}
+	j.Unlock()
+
+	proof, err := prover.GenerateProofContext(
+		j.ctx,
+		sig,
+		j.datadir,
+		hash.GenLabelHashFunc(params.challenge),
+		hash.GenMerkleHashFunc(params.challenge),
+		params.numLeaves,
+		params.securityParam,
+		prover.MinMemoryLayer(params.numLeaves, params.memoryLayers),
+		func(tree *merkle.Tree, treeCache *cache.Writer, nextLeafID uint64) error { return nil },
+		j.reportProgress,
+	)
+
+	if err := os.RemoveAll(j.datadir); err != nil {
+		log.Error("Job %v: failed to remove data directory: %v", j.id, err)
+	}
+
+	j.Lock()
+	defer j.Unlock()
+
+	j.ended = time.Now()
+	switch {
+	case err == prover.ErrShutdownRequested || err == context.Canceled:
+		j.status = jobCancelled
+		log.Info("Job %v cancelled", j.id)
+	case err != nil:
+		j.status = jobFailed
+		j.err = err
+		log.Error("Job %v failed: %v", j.id, err)
+	default:
+		j.status = jobCompleted
+		j.proof = proof
+		log.Info("Job %v completed, phi=%x", j.id, proof.Root)
+	}
+
+	// Release the job context.
+	j.cancelFunc()
+}
+
+func (j *job) reportProgress(progress prover.Progress) {
+	j.Lock()
+	defer j.Unlock()
+	j.progress = progress
+}
+
+// cancel requests the job computation to stop. It returns false if the job isn't running.
+func (j *job) cancel() bool {
+	j.Lock()
+	defer j.Unlock()
+
+	if j.status != jobRunning {
+		return false
+	}
+	j.cancelFunc()
+	return true
+}
+
+// endTime returns the time at which the job ended, and false if it's still running.
+func (j *job) endTime() (time.Time, bool) {
+	j.Lock()
+	defer j.Unlock()
+	return j.ended, j.status != jobRunning
+}
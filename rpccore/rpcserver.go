@@ -1,26 +1,62 @@
 package rpccore
 
 import (
+	"fmt"
 	"github.com/spacemeshos/poet/hash"
-	"github.com/spacemeshos/poet/prover"
 	"github.com/spacemeshos/poet/rpccore/apicore"
 	"github.com/spacemeshos/poet/shared"
 	"github.com/spacemeshos/poet/signal"
 	"github.com/spacemeshos/poet/verifier"
+	"github.com/spacemeshos/smutil/log"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	jobsDirName = "jobs"
+
+	// finishedJobsRetention is the duration for which finished jobs are kept, so that their status and proof
+	// can be fetched.
+	finishedJobsRetention = time.Hour
+
+	// maxFinishedJobs is the number of finished jobs which are kept, regardless of their retention.
+	maxFinishedJobs = 100
+
+	// maxVerifyBatchSize is the maximum number of NIPs in a VerifyNIPBatch request.
+	maxVerifyBatchSize = 256
 )
 
 var (
-	ErrNoProofExists = status.Error(codes.FailedPrecondition, "no computed proof exists")
+	ErrNoProofExists  = status.Error(codes.FailedPrecondition, "no computed proof exists")
+	ErrJobNotFound    = status.Error(codes.NotFound, "job not found")
+	ErrJobNotRunning  = status.Error(codes.FailedPrecondition, "job is not running")
+	ErrJobNotComplete = status.Error(codes.FailedPrecondition, "job has not completed")
 )
 
+// Config is the core service configuration. Its params are the defaults of the computed proofs params,
+// as well as their upper limits when specified per request.
+type Config struct {
+	N             uint   `long:"n" description:"PoET time parameter"`
+	MemoryLayers  uint   `long:"memory" description:"Number of top Merkle tree layers to cache in-memory"`
+	SecurityParam uint32 `long:"security-param" description:"Number of proven leaves of each proof"`
+	MaxJobs       uint   `long:"max-jobs" description:"Maximum number of concurrently running jobs (0 for unlimited)"`
+}
+
 // RPCServer is a gRPC, RPC front end to poet core
 type RPCServer struct {
 	sig     *signal.Signal
+	cfg     *Config
 	datadir string
-	proof   *shared.MerkleProof
+
+	jobs      map[string]*job
+	lastJobID string // lastJobID is the ID of the most recently completed job.
+	sync.Mutex
 }
 
 // A compile time check to ensure that RPCServer fully implements the
@@ -28,44 +64,282 @@ type RPCServer struct {
 var _ apicore.PoetCoreProverServer = (*RPCServer)(nil)
 var _ apicore.PoetVerifierServer = (*RPCServer)(nil)
 
-// NewRPCServer creates and returns a new instance of the RPCServer.
-func NewRPCServer(sig *signal.Signal, datadir string) *RPCServer {
+// NewRPCServer creates and returns a new instance of the RPCServer. The data directories of the jobs
+// of a previous run are removed, since the jobs aren't resumed.
+func NewRPCServer(sig *signal.Signal, cfg *Config, datadir string) *RPCServer {
+	if err := os.RemoveAll(filepath.Join(datadir, jobsDirName)); err != nil {
+		log.Error("Failed to remove the jobs data directories: %v", err)
+	}
+
 	return &RPCServer{
 		sig:     sig,
+		cfg:     cfg,
 		datadir: datadir,
+		jobs:    make(map[string]*job),
 	}
 }
 
 func (r *RPCServer) Compute(ctx context.Context, in *apicore.ComputeRequest) (*apicore.ComputeResponse, error) {
-	challenge := in.D.X
-	numLeaves := uint64(1) << in.D.N
-	securityParam := shared.T
-	proof, err := prover.GenerateProofWithoutPersistency(r.datadir, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam, prover.LowestMerkleMinMemoryLayer)
+	params, err := r.dagParams(in.D)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkLimits(params); err != nil {
+		return nil, err
+	}
+
+	id, err := newJobID()
 	if err != nil {
-		return nil, status.Error(codes.Unknown, err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	j := newJob(id, filepath.Join(r.datadir, jobsDirName, id))
+
+	r.Lock()
+	r.pruneJobs(time.Now())
+	if max := r.cfg.MaxJobs; max > 0 && r.numRunningJobs() >= max {
+		r.Unlock()
+		return nil, status.Error(codes.ResourceExhausted, fmt.Sprintf("number of running jobs reached the limit (%d)", max))
+	}
+	r.jobs[id] = j
+	r.Unlock()
+
+	if err := os.MkdirAll(j.datadir, 0700); err != nil {
+		r.Lock()
+		delete(r.jobs, id)
+		r.Unlock()
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	go func() {
+		j.run(r.sig, params)
+
+		j.Lock()
+		completed := j.status == jobCompleted
+		j.Unlock()
+		if completed {
+			r.Lock()
+			r.lastJobID = j.id
+			r.Unlock()
+		}
+		close(j.done)
+	}()
+
+	log.Info("Job %v started, num leaves: %d, security param: %d", id, params.numLeaves, params.securityParam)
+
+	out := &apicore.ComputeResponse{JobId: id}
+	if !in.Wait {
+		return out, nil
+	}
+
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		return nil, jobError(codes.DeadlineExceeded, "JOB_RUNNING", id, fmt.Sprintf("job %v is still running", id))
 	}
-	r.proof = proof
 
-	return &apicore.ComputeResponse{}, nil
+	j.Lock()
+	defer j.Unlock()
+
+	switch j.status {
+	case jobCancelled:
+		return nil, jobError(codes.Canceled, "JOB_CANCELLED", id, fmt.Sprintf("job %v cancelled", id))
+	case jobFailed:
+		return nil, jobError(codes.Internal, "JOB_FAILED", id, fmt.Sprintf("job %v failed: %v", id, j.err))
+	}
+	out.Phi = j.proof.Root
+
+	return out, nil
+}
+
+func (r *RPCServer) GetJob(ctx context.Context, in *apicore.GetJobRequest) (*apicore.GetJobResponse, error) {
+	j, err := r.job(in.JobId)
+	if err != nil {
+		return nil, err
+	}
+
+	j.Lock()
+	defer j.Unlock()
+
+	out := &apicore.Job{
+		Id:         j.id,
+		Status:     apicore.JobStatus(j.status),
+		LeavesDone: j.progress.LeavesDone,
+		NumLeaves:  j.progress.NumLeaves,
+		Rate:       j.progress.Rate,
+	}
+	if j.status == jobRunning && !j.progress.EstimatedCompletion.IsZero() {
+		out.EstimatedCompletion = j.progress.EstimatedCompletion.Unix()
+	}
+	if j.err != nil {
+		out.Error = j.err.Error()
+	}
+
+	return &apicore.GetJobResponse{Job: out}, nil
+}
+
+func (r *RPCServer) CancelJob(ctx context.Context, in *apicore.CancelJobRequest) (*apicore.CancelJobResponse, error) {
+	j, err := r.job(in.JobId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !j.cancel() {
+		return nil, ErrJobNotRunning
+	}
+
+	return &apicore.CancelJobResponse{}, nil
 }
 
 func (r *RPCServer) GetNIP(ctx context.Context, in *apicore.GetNIPRequest) (*apicore.GetNIPResponse, error) {
-	if r.proof == nil {
-		return nil, ErrNoProofExists
+	jobID := in.JobId
+	if jobID == "" {
+		r.Lock()
+		jobID = r.lastJobID
+		r.Unlock()
+
+		if jobID == "" {
+			return nil, ErrNoProofExists
+		}
+	}
+
+	j, err := r.job(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	j.Lock()
+	proof := j.proof
+	j.Unlock()
+	if proof == nil {
+		return nil, ErrJobNotComplete
 	}
 
 	return &apicore.GetNIPResponse{Proof: &apicore.Proof{
-		Phi:          r.proof.Root,
-		ProvenLeaves: r.proof.ProvenLeaves,
-		ProofNodes:   r.proof.ProofNodes,
+		Phi:          proof.Root,
+		ProvenLeaves: proof.ProvenLeaves,
+		ProofNodes:   proof.ProofNodes,
 	}}, nil
 }
 
+func (r *RPCServer) job(id string) (*job, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	j, ok := r.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return j, nil
+}
+
+// numRunningJobs returns the number of jobs which are still running. It must be called while holding the lock.
+func (r *RPCServer) numRunningJobs() uint {
+	var num uint
+	for _, j := range r.jobs {
+		if _, ended := j.endTime(); !ended {
+			num++
+		}
+	}
+	return num
+}
+
+// pruneJobs evicts the finished jobs which ended before their retention, and the oldest ones beyond
+// maxFinishedJobs, and removes their data directories, in case they were left behind. The most recently
+// completed job is kept, for GetNIP requests without a job ID. It must be called while holding the lock.
+func (r *RPCServer) pruneJobs(now time.Time) {
+	type finishedJob struct {
+		id    string
+		ended time.Time
+	}
+	var finished []finishedJob
+	for id, j := range r.jobs {
+		ended, ok := j.endTime()
+		if !ok || id == r.lastJobID {
+			continue
+		}
+		if now.Sub(ended) > finishedJobsRetention {
+			r.evictJob(id)
+			continue
+		}
+		finished = append(finished, finishedJob{id, ended})
+	}
+
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+	sort.Slice(finished, func(i, k int) bool { return finished[i].ended.Before(finished[k].ended) })
+	for _, f := range finished[:len(finished)-maxFinishedJobs] {
+		r.evictJob(f.id)
+	}
+}
+
+// evictJob removes a finished job and its data directory. It must be called while holding the lock.
+func (r *RPCServer) evictJob(id string) {
+	if err := os.RemoveAll(r.jobs[id].datadir); err != nil {
+		log.Error("Job %v: failed to remove data directory: %v", id, err)
+	}
+	delete(r.jobs, id)
+}
+
 func (r *RPCServer) Shutdown(context.Context, *apicore.ShutdownRequest) (*apicore.ShutdownResponse, error) {
 	r.sig.RequestShutdown()
 	return &apicore.ShutdownResponse{}, nil
 }
 
+// dagParams are the params of a DAG computation, after applying the configured defaults.
+type dagParams struct {
+	challenge     []byte
+	numLeaves     uint64
+	memoryLayers  uint
+	securityParam uint32
+}
+
+// dagParams applies the configured defaults to the params which aren't specified in the request.
+func (r *RPCServer) dagParams(d *apicore.DagParams) (*dagParams, error) {
+	if d == nil {
+		return nil, status.Error(codes.InvalidArgument, "dag params are missing")
+	}
+	if d.N >= 64 {
+		return nil, status.Error(codes.InvalidArgument, "n must be lower than 64")
+	}
+
+	p := &dagParams{
+		challenge:     d.X,
+		numLeaves:     d.NumLeaves,
+		memoryLayers:  uint(d.MemoryLayers),
+		securityParam: r.cfg.SecurityParam,
+	}
+	if p.numLeaves == 0 {
+		p.numLeaves = uint64(1) << d.N
+	}
+	if p.memoryLayers == 0 {
+		p.memoryLayers = r.cfg.MemoryLayers
+	}
+	if d.SecurityParam != 0 {
+		p.securityParam = d.SecurityParam
+	}
+	if uint64(p.securityParam) > p.numLeaves {
+		return nil, status.Error(codes.InvalidArgument, "security param must not exceed the number of leaves")
+	}
+
+	return p, nil
+}
+
+// checkLimits validates that the params of a DAG computation don't exceed the configured limits.
+func (r *RPCServer) checkLimits(p *dagParams) error {
+	if maxLeaves := uint64(1) << r.cfg.N; p.numLeaves > maxLeaves {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("number of leaves exceeds the limit (%d)", maxLeaves))
+	}
+	if p.memoryLayers > r.cfg.MemoryLayers {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("memory layers exceed the limit (%d)", r.cfg.MemoryLayers))
+	}
+	if p.securityParam > r.cfg.SecurityParam {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("security param exceeds the limit (%d)", r.cfg.SecurityParam))
+	}
+	return nil
+}
+
 func nativeProofFromWire(wireProof *apicore.Proof) shared.MerkleProof {
 	return shared.MerkleProof{
 		Root:         wireProof.Phi,
@@ -75,13 +349,71 @@ func nativeProofFromWire(wireProof *apicore.Proof) shared.MerkleProof {
 }
 
 func (r *RPCServer) VerifyNIP(ctx context.Context, in *apicore.VerifyNIPRequest) (*apicore.VerifyNIPResponse, error) {
-	proof := nativeProofFromWire(in.P)
-	challenge := in.D.X
-	numLeaves := uint64(1) << in.D.N
-	securityParam := shared.T
-	err := verifier.Validate(proof, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam)
+	params, err := r.dagParams(in.D)
 	if err != nil {
 		return nil, err
 	}
+	if in.P == nil {
+		return nil, status.Error(codes.InvalidArgument, "proof is missing")
+	}
+
+	proof := nativeProofFromWire(in.P)
+	challenge := params.challenge
+	err = verifier.Validate(proof, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), params.numLeaves, params.securityParam)
+	if reason, ok := verificationFailureReason(err); ok {
+		return &apicore.VerifyNIPResponse{Verified: false, Error: err.Error(), Reason: reason}, nil
+	} else if err != nil {
+		return nil, verificationError(err)
+	}
 	return &apicore.VerifyNIPResponse{Verified: true}, nil
 }
+
+func (r *RPCServer) VerifyNIPBatch(ctx context.Context, in *apicore.VerifyNIPBatchRequest) (*apicore.VerifyNIPBatchResponse, error) {
+	if len(in.Requests) > maxVerifyBatchSize {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("batch size exceeds the limit (%d)", maxVerifyBatchSize))
+	}
+
+	results := make([]*apicore.VerifyNIPResult, len(in.Requests))
+
+	// Requests with invalid params are rejected without validating their proof.
+	var items []*verifier.BatchItem
+	var indices []int
+	for i, req := range in.Requests {
+		params, err := r.dagParams(req.D)
+		if err == nil && req.P == nil {
+			err = status.Error(codes.InvalidArgument, "proof is missing")
+		}
+		if err != nil {
+			results[i] = &apicore.VerifyNIPResult{Error: status.Convert(err).Message()}
+			continue
+		}
+
+		challenge := params.challenge
+		items = append(items, &verifier.BatchItem{
+			Proof:          nativeProofFromWire(req.P),
+			LabelHashFunc:  hash.GenLabelHashFunc(challenge),
+			MerkleHashFunc: hash.GenMerkleHashFunc(challenge),
+			NumLeaves:      params.numLeaves,
+			SecurityParam:  params.securityParam,
+		})
+		indices = append(indices, i)
+	}
+
+	batchResults := verifier.ValidateBatch(ctx, items, 0)
+	if err := ctx.Err(); err != nil {
+		if err == context.DeadlineExceeded {
+			return nil, status.Error(codes.DeadlineExceeded, err.Error())
+		}
+		return nil, status.Error(codes.Canceled, err.Error())
+	}
+
+	for j, err := range batchResults {
+		result := &apicore.VerifyNIPResult{Verified: err == nil}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results[indices[j]] = result
+	}
+
+	return &apicore.VerifyNIPBatchResponse{Results: results}, nil
+}
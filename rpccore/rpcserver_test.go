@@ -0,0 +1,290 @@
+package rpccore
+
+import (
+	"fmt"
+	"github.com/spacemeshos/poet/rpccore/apicore"
+	"github.com/spacemeshos/poet/shared"
+	"github.com/spacemeshos/poet/signal"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T) (*RPCServer, func()) {
+	tempdir, err := ioutil.TempDir("", "poet-test")
+	require.NoError(t, err)
+
+	sig := signal.NewSignal()
+	cfg := &Config{N: 20, MemoryLayers: 4, SecurityParam: shared.T}
+	return NewRPCServer(sig, cfg, tempdir), func() {
+		sig.RequestShutdown()
+		os.RemoveAll(tempdir)
+	}
+}
+
+// waitForJob polls the status of a given job until it's no longer running.
+func waitForJob(t *testing.T, r *RPCServer, id string) *apicore.Job {
+	for i := 0; i < 100; i++ {
+		res, err := r.GetJob(context.Background(), &apicore.GetJobRequest{JobId: id})
+		require.NoError(t, err)
+		if res.Job.Status != apicore.JobStatus_RUNNING {
+			return res.Job
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	require.Fail(t, "job didn't end")
+	return nil
+}
+
+func TestRPCServer_Compute(t *testing.T) {
+	req := require.New(t)
+	r, cleanup := newTestServer(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := r.GetNIP(ctx, &apicore.GetNIPRequest{})
+	req.Equal(ErrNoProofExists, err)
+
+	d := &apicore.DagParams{X: []byte("challenge"), N: 10}
+	res, err := r.Compute(ctx, &apicore.ComputeRequest{D: d, Wait: true})
+	req.NoError(err)
+	req.NotEmpty(res.JobId)
+	req.NotEmpty(res.Phi)
+
+	job := waitForJob(t, r, res.JobId)
+	req.Equal(apicore.JobStatus_COMPLETED, job.Status)
+	req.Equal(uint64(1)<<d.N, job.NumLeaves)
+	req.Equal(job.NumLeaves, job.LeavesDone)
+
+	// The proof of the most recently completed job is returned if no job ID is specified.
+	for _, id := range []string{"", res.JobId} {
+		nip, err := r.GetNIP(ctx, &apicore.GetNIPRequest{JobId: id})
+		req.NoError(err)
+		req.Equal(res.Phi, nip.Proof.Phi)
+
+		verified, err := r.VerifyNIP(ctx, &apicore.VerifyNIPRequest{D: d, P: nip.Proof})
+		req.NoError(err)
+		req.True(verified.Verified)
+	}
+
+	// A proof which fails the verification is reported as not verified, while a malformed one is rejected.
+	nip, err := r.GetNIP(ctx, &apicore.GetNIPRequest{JobId: res.JobId})
+	req.NoError(err)
+	other := &apicore.DagParams{X: []byte("other challenge"), N: d.N}
+	verified, err := r.VerifyNIP(ctx, &apicore.VerifyNIPRequest{D: other, P: nip.Proof})
+	req.NoError(err)
+	req.False(verified.Verified)
+	req.NotEmpty(verified.Error)
+	req.NotEmpty(verified.Reason)
+
+	malformed := &apicore.Proof{Phi: nip.Proof.Phi, ProvenLeaves: nip.Proof.ProvenLeaves[1:], ProofNodes: nip.Proof.ProofNodes}
+	_, err = r.VerifyNIP(ctx, &apicore.VerifyNIPRequest{D: d, P: malformed})
+	req.Equal(codes.InvalidArgument, status.Code(err))
+
+	_, err = r.CancelJob(ctx, &apicore.CancelJobRequest{JobId: res.JobId})
+	req.Equal(ErrJobNotRunning, err)
+	_, err = r.GetJob(ctx, &apicore.GetJobRequest{JobId: "unknown"})
+	req.Equal(ErrJobNotFound, err)
+
+	// The job data directory is removed once it ends.
+	_, err = os.Stat(r.jobs[res.JobId].datadir)
+	req.True(os.IsNotExist(err))
+}
+
+func TestRPCServer_ComputeParams(t *testing.T) {
+	req := require.New(t)
+	r, cleanup := newTestServer(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// The security param can't exceed the number of leaves.
+	d := &apicore.DagParams{X: []byte("challenge"), N: 7, SecurityParam: 129}
+	_, err := r.Compute(ctx, &apicore.ComputeRequest{D: d})
+	req.Equal(codes.InvalidArgument, status.Code(err))
+
+	// The configured limits can't be exceeded.
+	d = &apicore.DagParams{X: []byte("challenge"), N: 21}
+	_, err = r.Compute(ctx, &apicore.ComputeRequest{D: d})
+	req.Equal(codes.InvalidArgument, status.Code(err))
+	req.Empty(r.jobs)
+}
+
+func TestRPCServer_CancelJob(t *testing.T) {
+	req := require.New(t)
+	r, cleanup := newTestServer(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	d := &apicore.DagParams{X: []byte("challenge"), N: 20}
+	res, err := r.Compute(ctx, &apicore.ComputeRequest{D: d})
+	req.NoError(err)
+	req.Empty(res.Phi)
+
+	_, err = r.CancelJob(ctx, &apicore.CancelJobRequest{JobId: res.JobId})
+	req.NoError(err)
+
+	job := waitForJob(t, r, res.JobId)
+	req.Equal(apicore.JobStatus_CANCELLED, job.Status)
+	req.Empty(job.Error)
+
+	_, err = r.GetNIP(ctx, &apicore.GetNIPRequest{JobId: res.JobId})
+	req.Equal(ErrJobNotComplete, err)
+	_, err = r.GetNIP(ctx, &apicore.GetNIPRequest{})
+	req.Equal(ErrNoProofExists, err)
+	_, err = r.CancelJob(ctx, &apicore.CancelJobRequest{JobId: res.JobId})
+	req.Equal(ErrJobNotRunning, err)
+}
+
+func TestRPCServer_ComputeDeadline(t *testing.T) {
+	req := require.New(t)
+	r, cleanup := newTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	d := &apicore.DagParams{X: []byte("challenge"), N: 20}
+	_, err := r.Compute(ctx, &apicore.ComputeRequest{D: d, Wait: true})
+
+	// The job ID is reported in the error details, so that the running job can still be cancelled.
+	st := status.Convert(err)
+	req.Equal(codes.DeadlineExceeded, st.Code())
+	req.Len(st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	req.True(ok)
+	req.Equal("JOB_RUNNING", info.Reason)
+	jobID := info.Metadata["jobId"]
+	req.Contains(r.jobs, jobID)
+
+	_, err = r.CancelJob(context.Background(), &apicore.CancelJobRequest{JobId: jobID})
+	req.NoError(err)
+	req.Equal(apicore.JobStatus_CANCELLED, waitForJob(t, r, jobID).Status)
+}
+
+func TestRPCServer_VerifyNIPBatch(t *testing.T) {
+	req := require.New(t)
+	r, cleanup := newTestServer(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	d := &apicore.DagParams{X: []byte("challenge"), N: 10}
+	res, err := r.Compute(ctx, &apicore.ComputeRequest{D: d, Wait: true})
+	req.NoError(err)
+	nip, err := r.GetNIP(ctx, &apicore.GetNIPRequest{JobId: res.JobId})
+	req.NoError(err)
+
+	otherD := &apicore.DagParams{X: []byte("other challenge"), N: 10}
+	batch := &apicore.VerifyNIPBatchRequest{Requests: []*apicore.VerifyNIPRequest{
+		{D: d, P: nip.Proof},
+		{D: otherD, P: nip.Proof},
+		{D: d},
+	}}
+	out, err := r.VerifyNIPBatch(ctx, batch)
+	req.NoError(err)
+	req.Len(out.Results, 3)
+	req.True(out.Results[0].Verified)
+	req.False(out.Results[1].Verified)
+	req.NotEmpty(out.Results[1].Error)
+	req.False(out.Results[2].Verified)
+	req.Equal("proof is missing", out.Results[2].Error)
+
+	// The batch isn't verified once the call context is done.
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = r.VerifyNIPBatch(cancelledCtx, batch)
+	req.Equal(codes.Canceled, status.Code(err))
+
+	// The batch size is limited.
+	for len(batch.Requests) <= maxVerifyBatchSize {
+		batch.Requests = append(batch.Requests, batch.Requests[0])
+	}
+	_, err = r.VerifyNIPBatch(ctx, batch)
+	req.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestRPCServer_PruneJobs(t *testing.T) {
+	req := require.New(t)
+	r, cleanup := newTestServer(t)
+	defer cleanup()
+
+	now := time.Now()
+	addJob := func(id string, s jobStatus, ended time.Time) {
+		j := newJob(id, "")
+		j.status = s
+		j.ended = ended
+		r.jobs[id] = j
+	}
+
+	addJob("running", jobRunning, time.Time{})
+	addJob("expired", jobCompleted, now.Add(-finishedJobsRetention-time.Second))
+	expiredDir := filepath.Join(r.datadir, jobsDirName, "expired")
+	req.NoError(os.MkdirAll(expiredDir, 0700))
+	r.jobs["expired"].datadir = expiredDir
+	addJob("last", jobCompleted, now.Add(-finishedJobsRetention-time.Second))
+	r.lastJobID = "last"
+	for i := 0; i < maxFinishedJobs+1; i++ {
+		addJob(fmt.Sprint(i), jobFailed, now.Add(time.Duration(i-maxFinishedJobs)*time.Second))
+	}
+
+	r.pruneJobs(now)
+
+	// The expired job and the oldest job beyond the limit are evicted, while the running job
+	// and the most recently completed one are kept.
+	req.Len(r.jobs, maxFinishedJobs+2)
+	req.Contains(r.jobs, "running")
+	req.Contains(r.jobs, "last")
+	req.NotContains(r.jobs, "expired")
+	req.NotContains(r.jobs, "0")
+	req.Contains(r.jobs, "1")
+
+	// The data directories of the evicted jobs are removed, in case they were left behind.
+	_, err := os.Stat(expiredDir)
+	req.True(os.IsNotExist(err))
+}
+
+func TestRPCServer_MaxJobs(t *testing.T) {
+	req := require.New(t)
+	r, cleanup := newTestServer(t)
+	defer cleanup()
+	r.cfg.MaxJobs = 1
+	ctx := context.Background()
+
+	d := &apicore.DagParams{X: []byte("challenge"), N: 20}
+	res, err := r.Compute(ctx, &apicore.ComputeRequest{D: d})
+	req.NoError(err)
+
+	_, err = r.Compute(ctx, &apicore.ComputeRequest{D: d})
+	req.Equal(codes.ResourceExhausted, status.Code(err))
+	req.Len(r.jobs, 1)
+
+	// A new job may start once the running one ends.
+	_, err = r.CancelJob(ctx, &apicore.CancelJobRequest{JobId: res.JobId})
+	req.NoError(err)
+	waitForJob(t, r, res.JobId)
+
+	res, err = r.Compute(ctx, &apicore.ComputeRequest{D: d})
+	req.NoError(err)
+	_, err = r.CancelJob(ctx, &apicore.CancelJobRequest{JobId: res.JobId})
+	req.NoError(err)
+	waitForJob(t, r, res.JobId)
+}
+
+func TestNewRPCServer_StaleJobs(t *testing.T) {
+	req := require.New(t)
+	tempdir, err := ioutil.TempDir("", "poet-test")
+	req.NoError(err)
+	defer os.RemoveAll(tempdir)
+
+	// The data directories of the jobs of a previous run are removed.
+	staleDir := filepath.Join(tempdir, jobsDirName, "stale")
+	req.NoError(os.MkdirAll(staleDir, 0700))
+	NewRPCServer(signal.NewSignal(), &Config{N: 20}, tempdir)
+	_, err = os.Stat(staleDir)
+	req.True(os.IsNotExist(err))
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
 	"fmt"
 	proxy "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/spacemeshos/poet/auth"
 	"github.com/spacemeshos/poet/rpc"
 	"github.com/spacemeshos/poet/rpc/api"
 	"github.com/spacemeshos/poet/rpccore"
@@ -12,7 +16,9 @@ import (
 	"github.com/spacemeshos/smutil/log"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"net"
 	"net/http"
@@ -30,8 +36,9 @@ func startServer() error {
 	// Initialize and register the implementation of gRPC interface
 	var grpcServer *grpc.Server
 	var proxyRegstr []func(context.Context, *proxy.ServeMux, string, []grpc.DialOption) error
+	interceptors := []grpc.UnaryServerInterceptor{loggerInterceptor()}
+	var streamInterceptors []grpc.StreamServerInterceptor
 	options := []grpc.ServerOption{
-		grpc.UnaryInterceptor(loggerInterceptor()),
 		// XXX: this is done to prevent routers from cleaning up our connections (e.g aws load balances..)
 		// TODO: these parameters work for now but we might need to revisit or add them as configuration
 		// TODO: Configure maxconns, maxconcurrentcons ..
@@ -44,14 +51,61 @@ func startServer() error {
 		}),
 	}
 
+	// Secure the listeners with TLS, if it's configured. The REST proxy dials the RPC listener with the same certificate.
+	proxyDialOpts := []grpc.DialOption{grpc.WithInsecure()}
+	var restTLSConfig *tls.Config
+	if cfg.TLSCertFile != "" {
+		certs, err := newCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile)
+		if err != nil {
+			return err
+		}
+		if cfg.TLSReloadInterval > 0 {
+			go certs.watch(cfg.TLSReloadInterval, ctx.Done())
+		}
+
+		options = append(options, grpc.Creds(credentials.NewTLS(certs.serverConfig())))
+		proxyDialOpts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(certs.clientConfig()))}
+		restTLSConfig = certs.serverConfig()
+	}
+
 	if _, err := os.Stat(cfg.DataDir); os.IsNotExist(err) {
 		if err := os.Mkdir(cfg.DataDir, 0700); err != nil {
 			return err
 		}
 	}
 
+	// Authorize the calls by their tokens, which are generated into the data directory on first start.
+	var restHandler func(http.Handler) http.Handler
+	if cfg.NoAuth {
+		log.Warning("RPC authentication is disabled")
+	} else {
+		// Only the admin calls require a token by default, so that the existing clients of the
+		// submit and read calls keep working. The submit scope is never enforced unless --authall is set.
+		minScope := auth.ScopeAdmin
+		if cfg.AuthAll {
+			minScope = auth.ScopeRead
+		}
+		authorizer, err := auth.NewAuthorizer(cfg.DataDir, methodScopes, minScope)
+		if err != nil {
+			return err
+		}
+		log.Info("RPC authentication is enabled for the %v scope and above, tokens are in %v", minScope, cfg.DataDir)
+
+		interceptors = append(interceptors, authorizer.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, authorizer.StreamServerInterceptor())
+		restHandler = authorizer.HTTPHandler
+	}
+	options = append(options, grpc.ChainUnaryInterceptor(interceptors...), grpc.ChainStreamInterceptor(streamInterceptors...))
+
+	// The secret by which the REST proxy identifies its calls, which is generated on each start.
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate proxy secret: %v", err)
+	}
+	proxySecret := hex.EncodeToString(secret)
+
 	if cfg.CoreServiceMode {
-		rpcServer := rpccore.NewRPCServer(sig, cfg.DataDir)
+		rpcServer := rpccore.NewRPCServer(sig, cfg.CoreService, cfg.DataDir)
 		grpcServer = grpc.NewServer(options...)
 
 		apicore.RegisterPoetCoreProverServer(grpcServer, rpcServer)
@@ -64,7 +118,7 @@ func startServer() error {
 			return err
 		}
 
-		rpcServer := rpc.NewRPCServer(svc)
+		rpcServer := rpc.NewRPCServer(svc, proxySecret)
 		grpcServer = grpc.NewServer(options...)
 
 		api.RegisterPoetServer(grpcServer, rpcServer)
@@ -83,10 +137,13 @@ func startServer() error {
 		grpcServer.Serve(lis)
 	}()
 
-	// Start the REST proxy for the gRPC server above.
-	mux := proxy.NewServeMux()
+	// Start the REST proxy for the gRPC server above. Its calls carry the proxy secret,
+	// so that the x-forwarded-for metadata which it sets is trusted.
+	mux := proxy.NewServeMux(proxy.WithMetadata(func(context.Context, *http.Request) metadata.MD {
+		return metadata.Pairs(rpc.ProxyMetadataKey, proxySecret)
+	}))
 	for _, r := range proxyRegstr {
-		err := r(ctx, mux, cfg.RPCListener.String(), []grpc.DialOption{grpc.WithInsecure()})
+		err := r(ctx, mux, cfg.RPCListener.String(), proxyDialOpts)
 		if err != nil {
 			return err
 		}
@@ -94,7 +151,17 @@ func startServer() error {
 
 	go func() {
 		log.Info("REST proxy start listening on %s", cfg.RESTListener.String())
-		err := http.ListenAndServe(cfg.RESTListener.String(), mux)
+		var handler http.Handler = mux
+		if restHandler != nil {
+			handler = restHandler(handler)
+		}
+		server := &http.Server{Addr: cfg.RESTListener.String(), Handler: handler, TLSConfig: restTLSConfig}
+		var err error
+		if restTLSConfig != nil {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
 		log.Error("REST proxy failed listening: %s\n", err)
 	}()
 
@@ -104,6 +171,27 @@ func startServer() error {
 	return nil
 }
 
+// methodScopes are the permission scopes required by the RPC methods.
+// Methods which aren't listed, such as Start and Shutdown, require the admin scope. The submit and read scopes
+// are enforced only if --authall is set, hence the core jobs, which are expensive to compute and may be
+// cancelled by any caller, require the admin scope.
+var methodScopes = map[string]auth.Scope{
+	"/api.Poet/Submit":                     auth.ScopeSubmit,
+	"/api.Poet/GetInfo":                    auth.ScopeRead,
+	"/api.Poet/GetMembershipProof":         auth.ScopeRead,
+	"/api.Poet/GetProof":                   auth.ScopeRead,
+	"/api.Poet/ListRounds":                 auth.ScopeRead,
+	"/api.Poet/GetRound":                   auth.ScopeRead,
+	"/api.Poet/GetGateways":                auth.ScopeRead,
+	"/api.Poet/SubscribeEvents":            auth.ScopeRead,
+	"/apicore.PoetCoreProver/Compute":      auth.ScopeAdmin,
+	"/apicore.PoetCoreProver/CancelJob":    auth.ScopeAdmin,
+	"/apicore.PoetCoreProver/GetJob":       auth.ScopeRead,
+	"/apicore.PoetCoreProver/GetNIP":       auth.ScopeRead,
+	"/apicore.PoetVerifier/VerifyNIP":      auth.ScopeRead,
+	"/apicore.PoetVerifier/VerifyNIPBatch": auth.ScopeRead,
+}
+
 // loggerInterceptor returns UnaryServerInterceptor handler to log all RPC server incoming requests.
 func loggerInterceptor() func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
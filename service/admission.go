@@ -0,0 +1,166 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// The number of peers whose rate limiters are kept before the idle ones are pruned.
+const maxTrackedPeers = 10000
+
+// SubmitLimit identifies the admission limit which rejected a submission.
+type SubmitLimit string
+
+const (
+	LimitChallengeSize SubmitLimit = "CHALLENGE_TOO_LARGE"
+	LimitPeerRate      SubmitLimit = "PEER_RATE_LIMITED"
+	LimitRate          SubmitLimit = "RATE_LIMITED"
+	LimitRoundMembers  SubmitLimit = "ROUND_FULL"
+)
+
+// SubmitLimitError is returned when a submission is rejected by an admission limit.
+type SubmitLimitError struct {
+	Limit SubmitLimit
+	Msg   string
+
+	// RetryNextRound indicates that the submission may succeed in the next round, since the current one is full.
+	RetryNextRound bool
+
+	// RetryAfter is the duration after which the submission may succeed, if it was rate limited.
+	RetryAfter time.Duration
+}
+
+func (e *SubmitLimitError) Error() string {
+	return e.Msg
+}
+
+// rateLimiter is a token bucket, which is refilled at a given rate per second, up to its burst size.
+type rateLimiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst uint, now time.Time) *rateLimiter {
+	b := float64(burst)
+	if b == 0 {
+		b = math.Max(1, math.Ceil(rate))
+	}
+	return &rateLimiter{rate: rate, burst: b, tokens: b, last: now}
+}
+
+// refill adds the tokens which accumulated since the last refill.
+func (l *rateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+elapsed*l.rate)
+	}
+	l.last = now
+}
+
+// wait returns the duration until a token is available, following a refill.
+func (l *rateLimiter) wait() time.Duration {
+	if l.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// admission applies the configured rate limits to the submissions, globally and per peer.
+type admission struct {
+	cfg *Config
+
+	sync.Mutex
+	global *rateLimiter // global is nil if the submissions rate isn't limited.
+	peers  map[string]*rateLimiter
+}
+
+func newAdmission(cfg *Config) *admission {
+	a := &admission{
+		cfg:   cfg,
+		peers: make(map[string]*rateLimiter),
+	}
+	if cfg.SubmitRate > 0 {
+		a.global = newRateLimiter(cfg.SubmitRate, cfg.SubmitBurst, time.Now())
+	}
+	return a
+}
+
+// admit checks the admission limits of a submission from a given peer, and takes its rate limits tokens
+// if it's admitted.
+func (a *admission) admit(challenge []byte, peer string, now time.Time) error {
+	if max := a.cfg.MaxChallengeSize; max > 0 && uint(len(challenge)) > max {
+		return &SubmitLimitError{
+			Limit: LimitChallengeSize,
+			Msg:   fmt.Sprintf("challenge size (%d) exceeds the limit (%d)", len(challenge), max),
+		}
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	var peerLimiter *rateLimiter
+	if a.cfg.PeerSubmitRate > 0 {
+		peerLimiter = a.peers[peer]
+		if peerLimiter == nil {
+			a.prunePeers(now)
+			peerLimiter = newRateLimiter(a.cfg.PeerSubmitRate, a.cfg.PeerSubmitBurst, now)
+			a.peers[peer] = peerLimiter
+		}
+		peerLimiter.refill(now)
+		if wait := peerLimiter.wait(); wait > 0 {
+			return &SubmitLimitError{
+				Limit:      LimitPeerRate,
+				Msg:        fmt.Sprintf("submissions rate limit of peer exceeded, retry after %v", wait),
+				RetryAfter: wait,
+			}
+		}
+	}
+
+	if a.global != nil {
+		a.global.refill(now)
+		if wait := a.global.wait(); wait > 0 {
+			return &SubmitLimitError{
+				Limit:      LimitRate,
+				Msg:        fmt.Sprintf("submissions rate limit exceeded, retry after %v", wait),
+				RetryAfter: wait,
+			}
+		}
+		a.global.tokens--
+	}
+	if peerLimiter != nil {
+		peerLimiter.tokens--
+	}
+
+	return nil
+}
+
+// refund returns the rate limits tokens which were taken by the admission of a submission from a given peer,
+// once the submission is rejected.
+func (a *admission) refund(peer string) {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.global != nil {
+		a.global.tokens = math.Min(a.global.burst, a.global.tokens+1)
+	}
+	if l := a.peers[peer]; l != nil && a.cfg.PeerSubmitRate > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+1)
+	}
+}
+
+// prunePeers removes the rate limiters of the peers which are idle, i.e. their bucket is full,
+// once the number of tracked peers reaches maxTrackedPeers.
+func (a *admission) prunePeers(now time.Time) {
+	if len(a.peers) < maxTrackedPeers {
+		return
+	}
+	for peer, l := range a.peers {
+		l.refill(now)
+		if l.tokens >= l.burst {
+			delete(a.peers, peer)
+		}
+	}
+}
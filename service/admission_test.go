@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAdmission(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Now()
+	a := newAdmission(&Config{
+		SubmitRate:      10,
+		SubmitBurst:     3,
+		PeerSubmitRate:  1,
+		PeerSubmitBurst: 2,
+	})
+
+	// Each peer may submit up to its burst, and then at its rate.
+	req.NoError(a.admit(nil, "a", now))
+	req.NoError(a.admit(nil, "a", now))
+	err := a.admit(nil, "a", now)
+	var limitErr *SubmitLimitError
+	req.True(errors.As(err, &limitErr))
+	req.Equal(LimitPeerRate, limitErr.Limit)
+	req.Equal(time.Second, limitErr.RetryAfter)
+	req.False(limitErr.RetryNextRound)
+
+	// The peer rate limit is independent of the other peers, but the global one isn't.
+	req.NoError(a.admit(nil, "b", now))
+	err = a.admit(nil, "c", now)
+	req.True(errors.As(err, &limitErr))
+	req.Equal(LimitRate, limitErr.Limit)
+	req.Equal(100*time.Millisecond, limitErr.RetryAfter)
+
+	// A rejected submission doesn't take the peer token.
+	now = now.Add(100 * time.Millisecond)
+	req.NoError(a.admit(nil, "c", now))
+	now = now.Add(100 * time.Millisecond)
+	req.NoError(a.admit(nil, "c", now))
+
+	now = now.Add(800 * time.Millisecond)
+	req.NoError(a.admit(nil, "a", now))
+
+	// The tokens of a submission which was rejected after its admission are refunded.
+	err = a.admit(nil, "a", now)
+	req.True(errors.As(err, &limitErr))
+	now = now.Add(time.Second)
+	req.NoError(a.admit(nil, "a", now))
+	a.refund("a")
+	req.NoError(a.admit(nil, "a", now))
+	req.Error(a.admit(nil, "a", now))
+}
+
+func TestAdmission_Unlimited(t *testing.T) {
+	req := require.New(t)
+
+	a := newAdmission(&Config{})
+	for i := 0; i < 1000; i++ {
+		req.NoError(a.admit(make([]byte, 1000), "a", time.Now()))
+	}
+}
+
+func TestRateLimiter_DefaultBurst(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Now()
+	req.Equal(float64(1), newRateLimiter(0.5, 0, now).burst)
+	req.Equal(float64(3), newRateLimiter(2.5, 0, now).burst)
+	req.Equal(float64(5), newRateLimiter(2.5, 5, now).burst)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"github.com/spacemeshos/poet/shared"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	archiveDirName       = "archive"
+	archiveFileExtension = ".bin"
+)
+
+// ArchivedRound is the compact record of a round which its proof was broadcast.
+// It is kept in the archive after the round data directory, including its layers cache files, is removed.
+type ArchivedRound struct {
+	ID                   string
+	Opened               time.Time
+	ExecutionStarted     time.Time
+	Archived             time.Time
+	LastBroadcastAttempt time.Time
+	NumLeaves            uint64
+	SecurityParam        uint32
+	Members              [][]byte
+	Statement            []byte
+	NIP                  *shared.MerkleProof
+}
+
+// archive stores the records of finished rounds, one file per round, and prunes them according to
+// the configured retention policy.
+type archive struct {
+	dir          string
+	maxRounds    uint
+	maxRoundsAge time.Duration
+	sync.Mutex
+}
+
+// newArchive creates a new archive in a given directory. maxRounds set the number of records to keep,
+// and maxRoundsAge set the duration to keep each record for. Zero values disable the respective policy.
+func newArchive(dir string, maxRounds uint, maxRoundsAge time.Duration) (*archive, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %v", err)
+	}
+
+	return &archive{
+		dir:          dir,
+		maxRounds:    maxRounds,
+		maxRoundsAge: maxRoundsAge,
+	}, nil
+}
+
+func (a *archive) put(r *ArchivedRound) error {
+	a.Lock()
+	defer a.Unlock()
+
+	if err := persist(a.filename(r.ID), r); err != nil {
+		return err
+	}
+
+	return a.prune()
+}
+
+func (a *archive) get(roundID string) (*ArchivedRound, error) {
+	if roundID == "" || filepath.Base(roundID) != roundID {
+		return nil, ErrRoundNotFound
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	filename := a.filename(roundID)
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return nil, ErrRoundNotFound
+	}
+
+	r := &ArchivedRound{}
+	if err := load(filename, r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// ids returns the IDs of the archived rounds, ordered from the least recently archived.
+func (a *archive) ids() ([]string, error) {
+	a.Lock()
+	defer a.Unlock()
+
+	entries, err := a.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = strings.TrimSuffix(entry.Name(), archiveFileExtension)
+	}
+
+	return ids, nil
+}
+
+// prune removes the records which exceed the retention policy. It must be called while holding the lock.
+func (a *archive) prune() error {
+	entries, err := a.entries()
+	if err != nil {
+		return err
+	}
+
+	for i, entry := range entries {
+		expired := a.maxRoundsAge > 0 && time.Since(entry.ModTime()) > a.maxRoundsAge
+		exceeding := a.maxRounds > 0 && len(entries)-i > int(a.maxRounds)
+		if !expired && !exceeding {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(a.dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove archived round: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// entries returns the archive files, ordered from the least recently modified.
+func (a *archive) entries() ([]os.FileInfo, error) {
+	infos, err := ioutil.ReadDir(a.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]os.FileInfo, 0, len(infos))
+	for _, info := range infos {
+		if !info.IsDir() && strings.HasSuffix(info.Name(), archiveFileExtension) {
+			entries = append(entries, info)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].ModTime().Before(entries[j].ModTime()) })
+
+	return entries, nil
+}
+
+func (a *archive) filename(roundID string) string {
+	return filepath.Join(a.dir, roundID+archiveFileExtension)
+}
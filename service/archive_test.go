@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchive(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	a, err := newArchive(filepath.Join(tempdir, archiveDirName), 0, 0)
+	req.NoError(err)
+
+	_, err = a.get("1")
+	req.Equal(ErrRoundNotFound, err)
+	_, err = a.get("../1")
+	req.Equal(ErrRoundNotFound, err)
+
+	members, err := genChallenges(4)
+	req.NoError(err)
+
+	r := &ArchivedRound{
+		ID:        "1",
+		Opened:    time.Now().UTC().Truncate(time.Second),
+		Archived:  time.Now().UTC().Truncate(time.Second),
+		NumLeaves: 16,
+		Members:   members,
+		Statement: []byte("statement"),
+	}
+	req.NoError(a.put(r))
+
+	archived, err := a.get("1")
+	req.NoError(err)
+	req.Equal(r.ID, archived.ID)
+	req.True(r.Opened.Equal(archived.Opened))
+	req.Equal(r.NumLeaves, archived.NumLeaves)
+	req.Equal(r.Members, archived.Members)
+	req.Equal(r.Statement, archived.Statement)
+
+	ids, err := a.ids()
+	req.NoError(err)
+	req.Equal([]string{"1"}, ids)
+}
+
+func TestArchive_Retention(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	// Retention by count.
+	a, err := newArchive(filepath.Join(tempdir, "count"), 3, 0)
+	req.NoError(err)
+
+	for i := 1; i <= 5; i++ {
+		req.NoError(a.put(&ArchivedRound{ID: fmt.Sprintf("%d", i)}))
+
+		// Distinguish the files modification time.
+		modTime := time.Now().Add(time.Duration(i-5) * time.Minute)
+		req.NoError(os.Chtimes(a.filename(fmt.Sprintf("%d", i)), modTime, modTime))
+	}
+
+	ids, err := a.ids()
+	req.NoError(err)
+	req.Equal([]string{"3", "4", "5"}, ids)
+
+	// Retention by age.
+	a, err = newArchive(filepath.Join(tempdir, "age"), 0, time.Hour)
+	req.NoError(err)
+
+	req.NoError(a.put(&ArchivedRound{ID: "1"}))
+	modTime := time.Now().Add(-2 * time.Hour)
+	req.NoError(os.Chtimes(a.filename("1"), modTime, modTime))
+	req.NoError(a.put(&ArchivedRound{ID: "2"}))
+
+	ids, err = a.ids()
+	req.NoError(err)
+	req.Equal([]string{"2"}, ids)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spacemeshos/poet/prover"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const calibrationFileBaseName = "calibration.bin"
+
+var ErrNoCalibration = errors.New("calibration result not found")
+
+// Calibration is the result of benchmarking the host, which sets the number of leaves of the rounds proofs
+// so that their execution completes within a target duration.
+type Calibration struct {
+	LabelHashRate  float64
+	LeavesRate     float64
+	SampleLeaves   uint64
+	TargetDuration time.Duration
+	SafetyMargin   float64
+	NumLeaves      uint64
+	Calibrated     time.Time
+}
+
+// Calibrate benchmarks the proof generation with a given number of sample leaves, and derives the number of leaves
+// whose execution is expected to complete within the target duration, leaving the safety margin (a fraction of
+// the target duration) spare. The result is stored in datadir.
+func Calibrate(cfg *Config, datadir string, target time.Duration, margin float64, sampleLeaves uint64) (*Calibration, error) {
+	if target <= 0 {
+		return nil, errors.New("calibration target duration must be positive")
+	}
+	if margin < 0 || margin >= 1 {
+		return nil, errors.New("calibration safety margin must be within [0, 1)")
+	}
+
+	// The benchmark files are created outside of datadir, since a leftover directory in it, e.g. if the calibration
+	// is killed, would be recovered as a round.
+	tempdir, err := ioutil.TempDir("", "poet-calibration")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calibration directory: %v", err)
+	}
+	defer os.RemoveAll(tempdir)
+
+	b, err := prover.RunBenchmark(tempdir, sampleLeaves, prover.MinMemoryLayer(sampleLeaves, cfg.MemoryLayers))
+	if err != nil {
+		return nil, fmt.Errorf("benchmark failure: %v", err)
+	}
+
+	c := &Calibration{
+		LabelHashRate:  b.LabelHashRate,
+		LeavesRate:     b.LeavesRate,
+		SampleLeaves:   sampleLeaves,
+		TargetDuration: target,
+		SafetyMargin:   margin,
+		NumLeaves:      uint64(b.LeavesRate * target.Seconds() * (1 - margin)),
+		Calibrated:     time.Now(),
+	}
+	if c.NumLeaves == 0 {
+		return nil, errors.New("calibration target duration is too short")
+	}
+
+	if err := persist(filepath.Join(datadir, calibrationFileBaseName), c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// LoadCalibration returns the calibration result which is stored in datadir.
+func LoadCalibration(datadir string) (*Calibration, error) {
+	filename := filepath.Join(datadir, calibrationFileBaseName)
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return nil, ErrNoCalibration
+	}
+
+	c := &Calibration{}
+	if err := load(filename, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestCalibrate(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	_, err := LoadCalibration(tempdir)
+	req.Equal(ErrNoCalibration, err)
+
+	cfg := &Config{MemoryLayers: 4}
+	_, err = Calibrate(cfg, tempdir, 0, 0.1, 1<<12)
+	req.Error(err)
+	_, err = Calibrate(cfg, tempdir, time.Minute, 1, 1<<12)
+	req.Error(err)
+
+	c, err := Calibrate(cfg, tempdir, time.Minute, 0.2, 1<<12)
+	req.NoError(err)
+	req.True(c.LabelHashRate > 0)
+	req.True(c.LeavesRate > 0)
+	req.Equal(uint64(c.LeavesRate*time.Minute.Seconds()*0.8), c.NumLeaves)
+
+	loaded, err := LoadCalibration(tempdir)
+	req.NoError(err)
+	req.Equal(c.NumLeaves, loaded.NumLeaves)
+	req.Equal(c.LeavesRate, loaded.LeavesRate)
+	req.Equal(c.TargetDuration, loaded.TargetDuration)
+	req.True(c.Calibrated.Equal(loaded.Calibrated))
+
+	// Verify that the benchmark files were removed.
+	entries, err := ioutil.ReadDir(tempdir)
+	req.NoError(err)
+	req.Len(entries, 1)
+}
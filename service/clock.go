@@ -0,0 +1,20 @@
+package service
+
+import "time"
+
+// clock is the time source of the rounds closure, which tests replace to control the rounds timing.
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the wall clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
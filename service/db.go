@@ -5,24 +5,43 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"sync"
 )
 
 type LevelDB struct {
 	*leveldb.DB
 	wo *opt.WriteOptions
 	ro *opt.ReadOptions
+
+	// closeMtx guards the creation of iterators against the closing of the database, e.g. on shutdown,
+	// since creating an iterator of a closed database panics.
+	closeMtx sync.RWMutex
+	closed   bool
 }
 
 func NewLevelDbStore(path string, wo *opt.WriteOptions, ro *opt.ReadOptions) *LevelDB {
+	db, err := openLevelDbStore(path, wo, ro)
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
+
+func openLevelDbStore(path string, wo *opt.WriteOptions, ro *opt.ReadOptions) (*LevelDB, error) {
 	blocks, err := leveldb.OpenFile(path, nil)
 	if err != nil {
-		panic(fmt.Errorf("failed to open db file (%v): %v", path, err))
+		return nil, fmt.Errorf("failed to open db file (%v): %v", path, err)
 	}
 
-	return &LevelDB{blocks, wo, ro}
+	return &LevelDB{DB: blocks, wo: wo, ro: ro}, nil
 }
 
 func (db *LevelDB) Close() error {
+	db.closeMtx.Lock()
+	defer db.closeMtx.Unlock()
+
+	db.closed = true
 	return db.DB.Close()
 }
 
@@ -39,5 +58,15 @@ func (db *LevelDB) Delete(key []byte) error {
 }
 
 func (db *LevelDB) Iterator() iterator.Iterator {
+	db.closeMtx.RLock()
+	defer db.closeMtx.RUnlock()
+
+	if db.closed {
+		return iterator.NewEmptyIterator(leveldb.ErrClosed)
+	}
 	return db.DB.NewIterator(nil, db.ro)
 }
+
+func (db *LevelDB) Has(key []byte) (bool, error) {
+	return db.DB.Has(key, db.ro)
+}
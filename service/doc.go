@@ -5,8 +5,12 @@ they can be shared among arbitrary number of miners, and thus amortize the CPU c
 Each round starts by being open for receiving challenges (byte arrays) from miners.
 Once finalized (according to the configured duration), a hash digest is created from the received challenges,
 and is used as the input for the proof generation.
-Once completed, the proof is broadcast to the Spacemesh network via the configured list of gateway nodes.
+If a genesis time is configured, rounds are aligned to the network epochs: each round is open
+during a single epoch (optionally shifted by the configured phase shift), and is identified by its epoch number.
+Once its generation is completed, the proof is broadcast to the Spacemesh network via the configured list of gateway nodes.
 In addition to the PoET, the proof also contains the list of received challenges, so that the membership
 of each challenge can be publicly verified.
+Once broadcast, the round data directory is removed, and its proof and list of challenges are kept in
+an archive, subject to the configured retention policy.
 */
 package service
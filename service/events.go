@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// The number of most recent events which are kept for resuming subscriptions.
+	eventsHistorySize = 1000
+
+	// The number of events which can be pending for a subscriber before it is dropped.
+	eventsSubscriberBufferSize = 256
+)
+
+var (
+	ErrEventsUnavailable    = errors.New("events since the requested sequence number are no longer available")
+	ErrSubscriptionOverflow = errors.New("subscriber fell behind; resubscribe from the last received sequence number")
+)
+
+// EventType is the type of a round lifecycle event.
+type EventType int
+
+const (
+	EventRoundOpened EventType = iota
+	EventRoundExecutionStarted
+	EventRoundExecutionEnded
+	EventRoundBroadcasted
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventRoundOpened:
+		return "round opened"
+	case EventRoundExecutionStarted:
+		return "round execution started"
+	case EventRoundExecutionEnded:
+		return "round execution ended"
+	case EventRoundBroadcasted:
+		return "round broadcasted"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(t))
+	}
+}
+
+// Event is a round lifecycle event. Seq is a sequence number, incremented by one per event. Its high 32 bits
+// are the service start time in unix seconds and its low 32 bits start from 1, so that the sequence numbers
+// aren't reused across restarts, and the events of a previous run are reported as unavailable.
+// Payload is the proof root (phi) for EventRoundExecutionEnded events, and is empty otherwise.
+type Event struct {
+	Seq     uint64
+	Type    EventType
+	RoundID string
+	Time    time.Time
+	Payload []byte
+}
+
+// Subscription delivers the events published since its requested sequence number.
+type Subscription struct {
+	missed []*Event
+	live   chan *Event
+	events *events
+}
+
+// Next returns the next event, blocking until it's published or until ctx is done.
+func (sub *Subscription) Next(ctx context.Context) (*Event, error) {
+	if len(sub.missed) > 0 {
+		e := sub.missed[0]
+		sub.missed = sub.missed[1:]
+		return e, nil
+	}
+
+	select {
+	case e, ok := <-sub.live:
+		if !ok {
+			return nil, ErrSubscriptionOverflow
+		}
+		return e, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close unregisters the subscription.
+func (sub *Subscription) Close() {
+	sub.events.unsubscribe(sub.live)
+}
+
+// events publishes events to subscribers, and keeps a bounded history of them for resuming subscriptions.
+type events struct {
+	history     []*Event
+	nextSeq     uint64
+	subscribers map[chan *Event]bool
+	sync.Mutex
+}
+
+func newEvents() *events {
+	return &events{
+		nextSeq:     uint64(time.Now().Unix())<<32 | 1,
+		subscribers: make(map[chan *Event]bool),
+	}
+}
+
+func (e *events) publish(typ EventType, roundID string, payload []byte) {
+	e.Lock()
+	defer e.Unlock()
+
+	event := &Event{
+		Seq:     e.nextSeq,
+		Type:    typ,
+		RoundID: roundID,
+		Time:    time.Now(),
+		Payload: payload,
+	}
+	e.nextSeq++
+
+	e.history = append(e.history, event)
+	if len(e.history) > eventsHistorySize {
+		e.history = e.history[len(e.history)-eventsHistorySize:]
+	}
+
+	for c := range e.subscribers {
+		select {
+		case c <- event:
+		default:
+			// Drop the subscriber rather than blocking the publisher.
+			delete(e.subscribers, c)
+			close(c)
+		}
+	}
+}
+
+// subscribe creates a subscription to the events published from a given sequence number.
+// If fromSeq is 0, only the events published after the subscription are delivered. ErrEventsUnavailable
+// is returned if the events since fromSeq are no longer kept, or if fromSeq wasn't issued by this run
+// of the service, i.e. it's beyond the next sequence number.
+func (e *events) subscribe(fromSeq uint64) (*Subscription, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	sub := &Subscription{
+		live:   make(chan *Event, eventsSubscriberBufferSize),
+		events: e,
+	}
+
+	if fromSeq > e.nextSeq {
+		return nil, ErrEventsUnavailable
+	}
+	if fromSeq > 0 && fromSeq < e.nextSeq {
+		if len(e.history) == 0 || fromSeq < e.history[0].Seq {
+			return nil, ErrEventsUnavailable
+		}
+		missed := e.history[fromSeq-e.history[0].Seq:]
+		sub.missed = make([]*Event, len(missed))
+		copy(sub.missed, missed)
+	}
+
+	e.subscribers[sub.live] = true
+	return sub, nil
+}
+
+func (e *events) unsubscribe(c chan *Event) {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.subscribers[c] {
+		delete(e.subscribers, c)
+		close(c)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestEvents(t *testing.T) {
+	req := require.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	e := newEvents()
+	first := e.nextSeq
+
+	// Subscribe to new events only.
+	sub, err := e.subscribe(0)
+	req.NoError(err)
+	defer sub.Close()
+
+	e.publish(EventRoundOpened, "0", nil)
+	e.publish(EventRoundExecutionStarted, "0", nil)
+	e.publish(EventRoundExecutionEnded, "0", []byte("phi"))
+
+	for i, typ := range []EventType{EventRoundOpened, EventRoundExecutionStarted, EventRoundExecutionEnded} {
+		event, err := sub.Next(ctx)
+		req.NoError(err)
+		req.Equal(first+uint64(i), event.Seq)
+		req.Equal(typ, event.Type)
+		req.Equal("0", event.RoundID)
+	}
+
+	// Resume from a previous sequence number, followed by new events.
+	resumed, err := e.subscribe(first + 2)
+	req.NoError(err)
+	defer resumed.Close()
+
+	e.publish(EventRoundBroadcasted, "0", nil)
+
+	event, err := resumed.Next(ctx)
+	req.NoError(err)
+	req.Equal(first+2, event.Seq)
+	req.Equal([]byte("phi"), event.Payload)
+
+	event, err = resumed.Next(ctx)
+	req.NoError(err)
+	req.Equal(first+3, event.Seq)
+	req.Equal(EventRoundBroadcasted, event.Type)
+
+	// Wait for the next event until ctx is done.
+	shortCtx, shortCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shortCancel()
+	_, err = resumed.Next(shortCtx)
+	req.Equal(context.DeadlineExceeded, err)
+}
+
+func TestEvents_History(t *testing.T) {
+	req := require.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	e := newEvents()
+	first := e.nextSeq
+	sub, err := e.subscribe(0)
+	req.NoError(err)
+
+	for i := 0; i < eventsHistorySize+10; i++ {
+		e.publish(EventRoundOpened, "0", nil)
+	}
+
+	// Events which were evicted from the history can't be resumed from.
+	_, err = e.subscribe(first + 9)
+	req.Equal(ErrEventsUnavailable, err)
+
+	oldest, err := e.subscribe(first + 10)
+	req.NoError(err)
+	defer oldest.Close()
+	event, err := oldest.Next(ctx)
+	req.NoError(err)
+	req.Equal(first+10, event.Seq)
+
+	// A subscriber which fell behind is dropped.
+	for i := 0; i < eventsSubscriberBufferSize; i++ {
+		_, err := sub.Next(ctx)
+		req.NoError(err)
+	}
+	_, err = sub.Next(ctx)
+	req.Equal(ErrSubscriptionOverflow, err)
+	sub.Close()
+}
+
+func TestEvents_Restart(t *testing.T) {
+	req := require.New(t)
+
+	prev := newEvents()
+	prev.publish(EventRoundOpened, "0", nil)
+	prev.publish(EventRoundExecutionStarted, "0", nil)
+	lastSeq := prev.nextSeq - 1
+
+	// The sequence numbers of a previous run aren't reused, and its events are reported as unavailable.
+	e := newEvents()
+	e.nextSeq = prev.nextSeq + 1<<32
+	_, err := e.subscribe(lastSeq)
+	req.Equal(ErrEventsUnavailable, err)
+	e.publish(EventRoundOpened, "1", nil)
+	_, err = e.subscribe(lastSeq + 1)
+	req.Equal(ErrEventsUnavailable, err)
+
+	// Sequence numbers which weren't issued yet are unavailable, except for the next one.
+	_, err = e.subscribe(e.nextSeq + 1)
+	req.Equal(ErrEventsUnavailable, err)
+	sub, err := e.subscribe(e.nextSeq)
+	req.NoError(err)
+	sub.Close()
+}
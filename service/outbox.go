@@ -0,0 +1,150 @@
+package service
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const (
+	outboxFileBaseName = "outbox.bin"
+
+	// The number of most recent broadcast attempts which are kept in an outbox.
+	outboxMaxAttempts = 100
+
+	// The upper bound of the interval between broadcast retries, as it grows exponentially.
+	maxBroadcastRetriesInterval = 1 * time.Hour
+
+	// The fraction of the interval between broadcast retries which is randomized, in each direction.
+	broadcastRetriesJitter = 0.2
+)
+
+// GatewaysBroadcaster is a Broadcaster which also reports the broadcast result of each gateway node, by its address.
+// A nil result indicates a successful broadcast. The gateway nodes in acked are skipped, since they already
+// acknowledged the proof. If the service broadcaster implements it, the outbox records each gateway attempt
+// separately, and the retries target only the gateway nodes which didn't acknowledge the proof yet.
+type GatewaysBroadcaster interface {
+	BroadcastProofToGateways(msg []byte, roundID string, members [][]byte, acked map[string]bool) (map[string]error, error)
+}
+
+// BroadcastAttempt is the result of a proof broadcast attempt via a gateway node.
+// Gateway is empty if the broadcaster doesn't report the results of the gateway nodes separately,
+// and Error is empty if the attempt succeeded.
+type BroadcastAttempt struct {
+	Time    time.Time
+	Gateway string
+	Error   string
+}
+
+// Outbox is the durable record of a round proof broadcast, which is kept in the round data directory
+// so that the broadcast retries resume after a restart.
+type Outbox struct {
+	// Attempts are the most recent broadcast attempts, ordered by their time.
+	Attempts []*BroadcastAttempt
+
+	// NumAttempts is the number of broadcast attempts since the broadcast started, or was last forced.
+	NumAttempts uint
+
+	// NextRetry is the time of the next broadcast attempt.
+	NextRetry time.Time
+
+	// Exhausted indicates that the broadcast retries ran out, and the broadcast is pending for being forced.
+	Exhausted bool
+
+	// Acked are the gateway nodes which acknowledged the proof, and which aren't sent it again on retries.
+	Acked []string
+
+	// Broadcasted indicates that the proof was broadcast, so that it isn't sent again if the round
+	// data directory is kept, e.g. since archiving the round failed.
+	Broadcasted bool
+}
+
+// lastAttempt returns the time of the most recent broadcast attempt, or the zero time if there was none.
+func (o *Outbox) lastAttempt() time.Time {
+	if len(o.Attempts) == 0 {
+		return time.Time{}
+	}
+	return o.Attempts[len(o.Attempts)-1].Time
+}
+
+// acked returns the set of gateway nodes which acknowledged the proof.
+func (o *Outbox) acked() map[string]bool {
+	acked := make(map[string]bool, len(o.Acked))
+	for _, gateway := range o.Acked {
+		acked[gateway] = true
+	}
+	return acked
+}
+
+// record adds the results of a broadcast attempt.
+func (o *Outbox) record(t time.Time, results map[string]error, err error) {
+	o.NumAttempts++
+
+	if len(results) == 0 {
+		o.addAttempt(t, "", err)
+	}
+	gateways := make([]string, 0, len(results))
+	for gateway := range results {
+		gateways = append(gateways, gateway)
+	}
+	sort.Strings(gateways)
+	acked := o.acked()
+	for _, gateway := range gateways {
+		o.addAttempt(t, gateway, results[gateway])
+		if results[gateway] == nil && !acked[gateway] {
+			o.Acked = append(o.Acked, gateway)
+		}
+	}
+
+	if len(o.Attempts) > outboxMaxAttempts {
+		o.Attempts = o.Attempts[len(o.Attempts)-outboxMaxAttempts:]
+	}
+}
+
+func (o *Outbox) addAttempt(t time.Time, gateway string, err error) {
+	attempt := &BroadcastAttempt{Time: t, Gateway: gateway}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	o.Attempts = append(o.Attempts, attempt)
+}
+
+// scheduleRetry sets the time of the next broadcast attempt, following a failed one, or marks the outbox
+// as exhausted if numRetries were already made.
+func (o *Outbox) scheduleRetry(t time.Time, numRetries uint, interval time.Duration) {
+	if o.NumAttempts > numRetries {
+		o.Exhausted = true
+		o.NextRetry = time.Time{}
+		return
+	}
+	o.NextRetry = t.Add(retryInterval(interval, o.NumAttempts))
+}
+
+// reset restarts the broadcast retries, so that the next attempt is immediate. The gateway nodes which
+// acknowledged the proof are kept, so that they aren't sent it again.
+func (o *Outbox) reset() {
+	o.NumAttempts = 0
+	o.NextRetry = time.Time{}
+	o.Exhausted = false
+}
+
+// retryInterval returns the interval before the retry which follows a given number of attempts. It grows exponentially
+// from a base interval, up to maxBroadcastRetriesInterval (unless the base interval is longer),
+// and is randomized by broadcastRetriesJitter.
+func retryInterval(base time.Duration, numAttempts uint) time.Duration {
+	max := maxBroadcastRetriesInterval
+	if base > max {
+		max = base
+	}
+
+	interval := base
+	for i := uint(1); i < numAttempts && interval < max; i++ {
+		interval *= 2
+	}
+	if interval > max {
+		interval = max
+	}
+
+	jitter := (2*rand.Float64() - 1) * broadcastRetriesJitter
+	return interval + time.Duration(float64(interval)*jitter)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestOutbox(t *testing.T) {
+	req := require.New(t)
+
+	o := new(Outbox)
+	now := time.Now()
+	interval := 10 * time.Second
+
+	// The retry interval grows exponentially with each failed attempt.
+	for i := uint(1); i <= 3; i++ {
+		o.record(now, nil, errors.New("failure"))
+		req.Equal(i, o.NumAttempts)
+
+		o.scheduleRetry(now, 3, interval)
+		req.False(o.Exhausted)
+		expected := interval << (i - 1)
+		req.InDelta(float64(expected), float64(o.NextRetry.Sub(now)), float64(expected)*broadcastRetriesJitter)
+	}
+	req.Len(o.Attempts, 3)
+	req.Equal("", o.Attempts[0].Gateway)
+	req.Equal("failure", o.Attempts[0].Error)
+
+	// Retries run out once they were all made.
+	o.record(now, nil, errors.New("failure"))
+	o.scheduleRetry(now, 3, interval)
+	req.True(o.Exhausted)
+	req.True(o.NextRetry.IsZero())
+
+	o.reset()
+	req.False(o.Exhausted)
+	req.Zero(o.NumAttempts)
+	req.Len(o.Attempts, 4)
+
+	// The gateway nodes which acknowledged the proof are kept across attempts, and across a reset.
+	o.record(now, map[string]error{"a": nil, "b": errors.New("failure")}, errors.New("failure"))
+	req.Equal(map[string]bool{"a": true}, o.acked())
+	o.record(now, map[string]error{"b": nil}, nil)
+	o.reset()
+	req.Equal(map[string]bool{"a": true, "b": true}, o.acked())
+
+	// The attempts history is bounded.
+	for i := 0; i < outboxMaxAttempts; i++ {
+		o.record(now, map[string]error{"a": nil, "b": nil}, nil)
+	}
+	req.Len(o.Attempts, outboxMaxAttempts)
+	req.Equal([]string{"a", "b"}, o.Acked)
+}
+
+func TestRetryInterval(t *testing.T) {
+	req := require.New(t)
+
+	for i := 0; i < 100; i++ {
+		interval := retryInterval(time.Minute, 100)
+		req.InDelta(float64(maxBroadcastRetriesInterval), float64(interval), float64(maxBroadcastRetriesInterval)*broadcastRetriesJitter)
+	}
+
+	// A base interval which is longer than the upper bound isn't shortened.
+	interval := retryInterval(2*maxBroadcastRetriesInterval, 3)
+	req.InDelta(float64(2*maxBroadcastRetriesInterval), float64(interval), float64(2*maxBroadcastRetriesInterval)*broadcastRetriesJitter)
+
+	req.Zero(retryInterval(0, 3))
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"github.com/spacemeshos/merkle-tree"
@@ -10,16 +11,18 @@ import (
 	"github.com/spacemeshos/poet/shared"
 	"github.com/spacemeshos/poet/signal"
 	"github.com/spacemeshos/smutil/log"
+	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
 
 type executionState struct {
 	NumLeaves     uint64
-	SecurityParam uint8
+	SecurityParam uint32
 	Members       [][]byte
 	Statement     []byte
 	ParkedNodes   [][]byte
@@ -27,14 +30,28 @@ type executionState struct {
 	NIP           *shared.MerkleProof
 }
 
-const roundStateFileBaseName = "state.bin"
+const (
+	roundStateFileBaseName = "state.bin"
+	roundEpochFileBaseName = "epoch.bin"
 
+	// The minimal interval between logs of the round execution progress.
+	progressLogInterval = 1 * time.Minute
+)
+
+// roundState is the persisted state of a round. Its format must be kept, so that the state files
+// of the rounds which are in-flight during an upgrade can be decoded.
 type roundState struct {
 	Opened           time.Time
 	ExecutionStarted time.Time
 	Execution        *executionState
 }
 
+// roundEpoch records the epoch of a round which was opened epoch-aligned. It's kept apart from the round state,
+// whose format must be kept, and it's missing for the rounds which were opened before the rounds were epoch-aligned.
+type roundEpoch struct {
+	Epoch uint64
+}
+
 func (r *roundState) isOpen() bool {
 	return !r.Opened.IsZero() && r.ExecutionStarted.IsZero()
 }
@@ -58,11 +75,34 @@ type round struct {
 	executionStartedChan chan struct{}
 	executionEndedChan   chan struct{}
 	broadcastedChan      chan struct{}
+	keepData             bool // keepData is set before broadcastedChan is closed, if the data directory is to be kept.
+	discardedChan        chan struct{}
+	failedChan           chan struct{}
+
+	// tornDownChan is closed once the round is torn down, and its database is closed.
+	tornDownChan chan struct{}
+
+	// rebroadcastChan signals a forced re-broadcast of the round proof.
+	rebroadcastChan chan struct{}
 
 	stateCache *roundState
 
+	// executionProgress is the latest reported progress of the round execution.
+	executionProgress *prover.Progress
+	progressLogged    time.Time
+	progressMtx       sync.Mutex
+
 	sig       *signal.Signal
 	submitMtx sync.Mutex
+
+	// numMembers is the number of submitted challenges, which is counted on the first submission
+	// if the members are limited. It's guarded by submitMtx.
+	numMembers      int
+	numMembersKnown bool
+
+	// identitiesDb maps the identities of the signed submissions to their challenges. It's opened
+	// on the first signed submission, and is guarded by submitMtx.
+	identitiesDb *LevelDB
 }
 
 func newRound(sig *signal.Signal, cfg *Config, datadir string, id string) *round {
@@ -74,6 +114,10 @@ func newRound(sig *signal.Signal, cfg *Config, datadir string, id string) *round
 	r.executionStartedChan = make(chan struct{})
 	r.executionEndedChan = make(chan struct{})
 	r.broadcastedChan = make(chan struct{})
+	r.discardedChan = make(chan struct{})
+	r.failedChan = make(chan struct{})
+	r.tornDownChan = make(chan struct{})
+	r.rebroadcastChan = make(chan struct{}, 1)
 	r.sig = sig
 
 	dbPath := filepath.Join(datadir, "challengesDb")
@@ -81,14 +125,24 @@ func newRound(sig *signal.Signal, cfg *Config, datadir string, id string) *round
 	r.challengesDb = NewLevelDbStore(dbPath, wo, nil) // This creates the datadir if it doesn't exist already.
 
 	r.execution = new(executionState)
-	r.execution.NumLeaves = uint64(1) << r.cfg.N // TODO(noamnelke): configure tick count instead of height
-	r.execution.SecurityParam = shared.T
+	r.execution.NumLeaves = r.cfg.NumLeaves
+	if r.execution.NumLeaves == 0 {
+		r.execution.NumLeaves = uint64(1) << r.cfg.N
+	}
+	r.execution.SecurityParam = r.cfg.SecurityParam
+	if r.execution.SecurityParam == 0 {
+		r.execution.SecurityParam = shared.T
+	}
 
 	go func() {
+		defer close(r.tornDownChan)
+
 		var cleanup bool
 		select {
 		case <-sig.ShutdownRequestedChan:
 		case <-r.broadcastedChan:
+			cleanup = !r.keepData
+		case <-r.discardedChan:
 			cleanup = true
 		}
 
@@ -122,16 +176,93 @@ func (r *round) isOpen() bool {
 	return !r.opened.IsZero() && r.executionStarted.IsZero()
 }
 
-func (r *round) submit(challenge []byte) error {
+// submit adds a challenge to the round members. identity is the public key which signed the submission,
+// or nil if it's unsigned. Each identity may submit a single challenge per round.
+func (r *round) submit(challenge []byte, identity []byte) error {
 	if !r.isOpen() {
 		return errors.New("round is not open")
 	}
 
 	r.submitMtx.Lock()
-	err := r.challengesDb.Put(challenge, nil)
-	r.submitMtx.Unlock()
+	defer r.submitMtx.Unlock()
+
+	if identity == nil {
+		return r.addMember(challenge)
+	}
+
+	if err := r.openIdentitiesDb(); err != nil {
+		return err
+	}
+	submitted, err := r.identitiesDb.Get(identity)
+	if err == nil {
+		if !bytes.Equal(submitted, challenge) {
+			return ErrIdentityAlreadySubmitted
+		}
+		// The challenge is added again, in case the identity was recorded but adding its challenge
+		// was interrupted. It doesn't count towards the members limit if it's already a member.
+		return r.addMember(challenge)
+	}
+	if err != leveldb.ErrNotFound {
+		return err
+	}
+
+	// The identity is recorded before its challenge is added, and the record is removed if adding the challenge
+	// fails, so that the identity is marked as used only along with its challenge.
+	if err := r.identitiesDb.Put(identity, challenge); err != nil {
+		return err
+	}
+	if err := r.addMember(challenge); err != nil {
+		if delErr := r.identitiesDb.Delete(identity); delErr != nil {
+			return fmt.Errorf("%w (failed to remove the identity record: %v)", err, delErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// addMember adds a challenge to the round members, unless the members limit was reached.
+// It must be called with submitMtx held.
+func (r *round) addMember(challenge []byte) error {
+	max := r.cfg.MaxRoundMembers
+	if max == 0 {
+		return r.challengesDb.Put(challenge, nil)
+	}
+
+	// A challenge which was already submitted doesn't count towards the limit.
+	if exists, err := r.challengesDb.Has(challenge); err != nil || exists {
+		return err
+	}
+	if !r.numMembersKnown {
+		r.numMembers = r.numChallenges()
+		r.numMembersKnown = true
+	}
+	if uint(r.numMembers) >= max {
+		return &SubmitLimitError{
+			Limit:          LimitRoundMembers,
+			Msg:            fmt.Sprintf("round %v is full (%d members), retry in the next round", r.ID, max),
+			RetryNextRound: true,
+		}
+	}
 
-	return err
+	if err := r.challengesDb.Put(challenge, nil); err != nil {
+		return err
+	}
+	r.numMembers++
+	return nil
+}
+
+// openIdentitiesDb opens the identities db, if it isn't open yet. It must be called with submitMtx held.
+func (r *round) openIdentitiesDb() error {
+	if r.identitiesDb != nil {
+		return nil
+	}
+
+	db, err := openLevelDbStore(filepath.Join(r.datadir, "identitiesDb"), &opt.WriteOptions{Sync: true}, nil)
+	if err != nil {
+		return err
+	}
+	r.identitiesDb = db
+	return nil
 }
 
 func (r *round) numChallenges() int {
@@ -163,20 +294,15 @@ func (r *round) execute() error {
 	r.submitMtx.Lock()
 	var err error
 	r.execution.Members, r.execution.Statement, err = r.calcMembersAndStatement()
+	r.submitMtx.Unlock()
 	if err != nil {
 		return err
 	}
-	r.submitMtx.Unlock()
 
 	if err := r.saveState(); err != nil {
 		return err
 	}
 
-	minMemoryLayer := int(r.cfg.N - r.cfg.MemoryLayers)
-	if minMemoryLayer < prover.LowestMerkleMinMemoryLayer {
-		minMemoryLayer = prover.LowestMerkleMinMemoryLayer
-	}
-
 	r.execution.NIP, err = prover.GenerateProof(
 		r.sig,
 		r.datadir,
@@ -184,8 +310,9 @@ func (r *round) execute() error {
 		hash.GenMerkleHashFunc(r.execution.Statement),
 		r.execution.NumLeaves,
 		r.execution.SecurityParam,
-		uint(minMemoryLayer),
+		prover.MinMemoryLayer(r.execution.NumLeaves, r.cfg.MemoryLayers),
 		r.persistExecution,
+		r.reportProgress,
 	)
 	if err != nil {
 		return err
@@ -216,7 +343,35 @@ func (r *round) persistExecution(tree *merkle.Tree, treeCache *cache.Writer, nex
 	return nil
 }
 
+func (r *round) reportProgress(progress prover.Progress) {
+	r.progressMtx.Lock()
+	defer r.progressMtx.Unlock()
+
+	r.executionProgress = &progress
+
+	if time.Since(r.progressLogged) < progressLogInterval {
+		return
+	}
+	r.progressLogged = time.Now()
+
+	log.Info("Round %v: execution progress (done: %d, total: %d, rate: %.0f leaves/sec, estimated completion: %v)",
+		r.ID, progress.LeavesDone, progress.NumLeaves, progress.Rate, progress.EstimatedCompletion.Format(time.RFC3339))
+}
+
+// progress returns the latest reported progress of the round execution, or nil if none was reported yet.
+func (r *round) progress() *prover.Progress {
+	r.progressMtx.Lock()
+	defer r.progressMtx.Unlock()
+
+	if r.executionProgress == nil {
+		return nil
+	}
+	progress := *r.executionProgress
+	return &progress
+}
+
 func (r *round) recoverExecution(state *executionState) error {
+	r.opened = r.stateCache.Opened
 	r.executionStarted = r.stateCache.ExecutionStarted
 	close(r.executionStartedChan)
 
@@ -245,6 +400,7 @@ func (r *round) recoverExecution(state *executionState) error {
 		state.NextLeafID,
 		state.ParkedNodes,
 		r.persistExecution,
+		r.reportProgress,
 	)
 	if err != nil {
 		return err
@@ -258,6 +414,13 @@ func (r *round) recoverExecution(state *executionState) error {
 	return nil
 }
 
+// recoverExecuted restores the state of a round whose execution ended, and which is pending for its proof broadcast.
+func (r *round) recoverExecuted() {
+	r.opened = r.stateCache.Opened
+	r.executionStarted = r.stateCache.ExecutionStarted
+	r.execution = r.stateCache.Execution
+}
+
 func (r *round) proof(wait bool) (*PoetProof, error) {
 	if wait {
 		<-r.executionEndedChan
@@ -267,29 +430,74 @@ func (r *round) proof(wait bool) (*PoetProof, error) {
 		default:
 			select {
 			case <-r.executionStartedChan:
-				return nil, errors.New("round is executing")
+				return nil, ErrRoundExecuting
 			default:
 				select {
 				case <-r.openedChan:
-					return nil, errors.New("round is open")
+					return nil, ErrRoundOpen
 				default:
-					return nil, errors.New("round wasn't open")
+					return nil, ErrRoundNotOpened
 				}
 			}
 		}
 	}
 
 	return &PoetProof{
-		N:         r.cfg.N,
-		Statement: r.execution.Statement,
-		Proof:     r.execution.NIP,
+		N:             numLeavesN(r.execution.NumLeaves),
+		NumLeaves:     r.execution.NumLeaves,
+		SecurityParam: r.execution.SecurityParam,
+		Statement:     r.execution.Statement,
+		Proof:         r.execution.NIP,
+		Members:       r.execution.Members,
 	}, nil
 }
 
+// loadOutbox returns the round proof broadcast outbox, or a new one if the broadcast didn't start yet.
+func (r *round) loadOutbox() (*Outbox, error) {
+	o := new(Outbox)
+	if err := load(filepath.Join(r.datadir, outboxFileBaseName), o); err != nil {
+		if strings.Contains(err.Error(), "file is missing") {
+			return new(Outbox), nil
+		}
+		return nil, err
+	}
+	return o, nil
+}
+
+func (r *round) saveOutbox(o *Outbox) error {
+	return persist(filepath.Join(r.datadir, outboxFileBaseName), o)
+}
+
+// rebroadcast forces an immediate re-broadcast of the round proof, if its broadcast is pending.
+func (r *round) rebroadcast() {
+	select {
+	case r.rebroadcastChan <- struct{}{}:
+	default:
+		// A re-broadcast is already pending.
+	}
+}
+
 func (r *round) broadcasted() {
 	close(r.broadcastedChan)
 }
 
+// broadcastedKeepingData tears down a broadcast round without removing its data directory,
+// e.g. since its proof couldn't be archived.
+func (r *round) broadcastedKeepingData() {
+	r.keepData = true
+	close(r.broadcastedChan)
+}
+
+// failed notifies that the round won't proceed to its next phases, since its execution or broadcast failed.
+func (r *round) failed() {
+	close(r.failedChan)
+}
+
+// discard tears down an open round which won't be executed, and removes its data directory.
+func (r *round) discard() {
+	close(r.discardedChan)
+}
+
 func (r *round) state() (*roundState, error) {
 	filename := filepath.Join(r.datadir, roundStateFileBaseName)
 	s := &roundState{}
@@ -321,6 +529,23 @@ func (r *round) saveState() error {
 	return persist(filename, v)
 }
 
+// saveEpoch records the epoch of a round which is opened epoch-aligned.
+func (r *round) saveEpoch(epoch uint64) error {
+	return persist(filepath.Join(r.datadir, roundEpochFileBaseName), &roundEpoch{Epoch: epoch})
+}
+
+// epoch returns the epoch of the round, and false if the round wasn't opened epoch-aligned.
+func (r *round) epoch() (uint64, bool, error) {
+	v := new(roundEpoch)
+	if err := load(filepath.Join(r.datadir, roundEpochFileBaseName), v); err != nil {
+		if strings.Contains(err.Error(), "file is missing") {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return v.Epoch, true, nil
+}
+
 func (r *round) calcMembersAndStatement() ([][]byte, []byte, error) {
 	mtree, err := merkle.NewTree()
 	if err != nil {
@@ -344,11 +569,53 @@ func (r *round) calcMembersAndStatement() ([][]byte, []byte, error) {
 	return members, mtree.Root(), nil
 }
 
+// calcMembershipProof generates a Merkle proof of the membership of a given challenge in a members list,
+// using the same Merkle tree construction which calcMembersAndStatement uses to derive the statement.
+func calcMembershipProof(members [][]byte, challenge []byte) (*MembershipProof, error) {
+	index := -1
+	for i, member := range members {
+		if bytes.Equal(member, challenge) {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, ErrNotMember
+	}
+
+	mtree, err := merkle.NewProvingTree(map[uint64]bool{uint64(index): true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize merkle tree: %v", err)
+	}
+
+	for _, member := range members {
+		if err := mtree.AddLeaf(member); err != nil {
+			return nil, err
+		}
+	}
+
+	root, proof := mtree.RootAndProof()
+	return &MembershipProof{
+		Index: index,
+		Root:  root,
+		Proof: proof,
+	}, nil
+}
+
 func (r *round) teardown(cleanup bool) error {
 	if err := r.challengesDb.Close(); err != nil {
 		return err
 	}
 
+	r.submitMtx.Lock()
+	if r.identitiesDb != nil {
+		if err := r.identitiesDb.Close(); err != nil {
+			r.submitMtx.Unlock()
+			return err
+		}
+	}
+	r.submitMtx.Unlock()
+
 	if cleanup {
 		if err := os.RemoveAll(r.datadir); err != nil {
 			return err
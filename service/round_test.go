@@ -2,10 +2,13 @@ package service
 
 import (
 	"fmt"
+	"github.com/spacemeshos/poet/hash"
 	"github.com/spacemeshos/poet/prover"
+	"github.com/spacemeshos/poet/shared"
 	"github.com/spacemeshos/poet/signal"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -39,7 +42,7 @@ func TestRound_Recovery(t *testing.T) {
 	req.True(r1.isEmpty())
 
 	for _, ch := range challenges {
-		req.NoError(r1.submit(ch))
+		req.NoError(r1.submit(ch, nil))
 	}
 	req.Equal(len(challenges), r1.numChallenges())
 	req.False(r1.isEmpty())
@@ -56,7 +59,7 @@ func TestRound_Recovery(t *testing.T) {
 	req.True(r2.isEmpty())
 
 	for _, ch := range challenges {
-		req.NoError(r2.submit(ch))
+		req.NoError(r2.submit(ch, nil))
 	}
 	req.Equal(len(challenges), r2.numChallenges())
 	req.False(r2.isEmpty())
@@ -69,6 +72,7 @@ func TestRound_Recovery(t *testing.T) {
 	start = time.Now()
 	req.EqualError(r2.execute(), prover.ErrShutdownRequested.Error())
 	r2exec1 := time.Since(start)
+	<-r2.tornDownChan
 
 	// Recover r2 execution, and request shutdown before completion.
 	sig = signal.NewSignal()
@@ -87,6 +91,7 @@ func TestRound_Recovery(t *testing.T) {
 	start = time.Now()
 	req.EqualError(r2recovery1.recoverExecution(state.Execution), prover.ErrShutdownRequested.Error())
 	r2exec2 := time.Since(start)
+	<-r2recovery1.tornDownChan
 
 	// Recover r2 execution again, and let it complete.
 	sig = signal.NewSignal()
@@ -132,7 +137,7 @@ func TestRound_State(t *testing.T) {
 	challenges, err := genChallenges(32)
 	req.NoError(err)
 
-	req.EqualError(r.submit(challenges[0]), "round is not open")
+	req.EqualError(r.submit(challenges[0], nil), "round is not open")
 
 	// Open the round.
 	req.NoError(r.open())
@@ -143,7 +148,7 @@ func TestRound_State(t *testing.T) {
 	req.True(r.isEmpty())
 
 	for _, ch := range challenges {
-		req.NoError(r.submit(ch))
+		req.NoError(r.submit(ch, nil))
 	}
 	req.Equal(len(challenges), r.numChallenges())
 	req.False(r.isEmpty())
@@ -190,7 +195,8 @@ func TestRound_State(t *testing.T) {
 	req.True(state.Execution.ParkedNodes != nil)
 	req.True(state.Execution.NIP == nil)
 
-	// Create a new round instance of the same round.
+	// Create a new round instance of the same round, once the previous one released the database.
+	<-r.tornDownChan
 	r = newRound(signal.NewSignal(), cfg, tempdir, "test-round")
 	req.True(!r.isOpen())
 	req.True(r.opened.IsZero())
@@ -215,6 +221,12 @@ func TestRound_State(t *testing.T) {
 	req.Equal(r.execution.NIP, proof.Proof)
 	req.Equal(r.execution.Statement, proof.Statement)
 
+	// Verify the reported execution progress.
+	progress := r.progress()
+	req.NotNil(progress)
+	req.Equal(state.Execution.NumLeaves, progress.NumLeaves)
+	req.Equal(progress.NumLeaves, progress.LeavesDone)
+
 	// Verify round execution state.
 	state, err = r.state()
 	req.True(!state.isOpen())
@@ -223,10 +235,105 @@ func TestRound_State(t *testing.T) {
 
 	// Trigger cleanup.
 	r.broadcasted()
-	time.Sleep(1 * time.Second)
+	<-r.tornDownChan
 
 	// Verify cleanup.
 	state, err = r.state()
 	req.EqualError(err, fmt.Sprintf("file is missing: %v", filepath.Join(tempdir, roundStateFileBaseName)))
 	req.Nil(state)
 }
+
+func TestRound_NumLeaves(t *testing.T) {
+	req := require.New(t)
+
+	sig := signal.NewSignal()
+	cfg := &Config{N: 18, NumLeaves: 200000, MemoryLayers: 4}
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	// Verify that the configured number of leaves overrides 2^n.
+	r := newRound(sig, cfg, tempdir, "test-round")
+	req.Equal(cfg.NumLeaves, r.execution.NumLeaves)
+
+	challenges, err := genChallenges(32)
+	req.NoError(err)
+	req.NoError(r.open())
+	for _, ch := range challenges {
+		req.NoError(r.submit(ch, nil))
+	}
+
+	// Execute the round, and request shutdown before completion.
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		sig.RequestShutdown()
+	}()
+	req.EqualError(r.execute(), prover.ErrShutdownRequested.Error())
+	<-r.tornDownChan
+
+	// Recover execution.
+	r = newRound(signal.NewSignal(), cfg, tempdir, "test-round")
+	state, err := r.state()
+	req.NoError(err)
+	req.True(state.Execution.NextLeafID > 0)
+	req.NoError(r.recoverExecution(state.Execution))
+
+	// Verify that the recovered proof matches a proof which was generated without interruption.
+	statement := r.execution.Statement
+	refdir, _ := ioutil.TempDir("", "poet-test")
+	expected, err := prover.GenerateProofWithoutPersistency(refdir, hash.GenLabelHashFunc(statement), hash.GenMerkleHashFunc(statement), cfg.NumLeaves, r.execution.SecurityParam, prover.LowestMerkleMinMemoryLayer)
+	req.NoError(err)
+	req.Equal(expected, r.execution.NIP)
+
+	proof, err := r.proof(false)
+	req.NoError(err)
+	req.Equal(cfg.NumLeaves, proof.NumLeaves)
+
+	r.broadcasted()
+}
+
+// baselineRoundState is the format of the round state files which were written before the upgrade,
+// whose in-flight rounds must still be recovered.
+type baselineRoundState struct {
+	Opened           time.Time
+	ExecutionStarted time.Time
+	Execution        *baselineExecutionState
+}
+
+type baselineExecutionState struct {
+	NumLeaves     uint64
+	SecurityParam uint8
+	Members       [][]byte
+	Statement     []byte
+	ParkedNodes   [][]byte
+	NextLeafID    uint64
+	NIP           *shared.MerkleProof
+}
+
+func TestRound_BaselineStateFormat(t *testing.T) {
+	req := require.New(t)
+
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+	defer os.RemoveAll(tempdir)
+
+	opened := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	prev := &baselineRoundState{Opened: opened, ExecutionStarted: opened.Add(time.Minute)}
+	prev.Execution = &baselineExecutionState{
+		NumLeaves:     1 << 10,
+		SecurityParam: 150,
+		Members:       [][]byte{[]byte("member")},
+		Statement:     []byte("statement"),
+		NextLeafID:    1 << 10,
+		NIP:           &shared.MerkleProof{Root: []byte("root")},
+	}
+	filename := filepath.Join(tempdir, roundStateFileBaseName)
+	req.NoError(persist(filename, prev))
+
+	state := new(roundState)
+	req.NoError(load(filename, state))
+	req.True(state.Opened.Equal(opened))
+	req.True(state.ExecutionStarted.Equal(prev.ExecutionStarted))
+	req.True(state.isExecuted())
+	req.Equal(prev.Execution.NumLeaves, state.Execution.NumLeaves)
+	req.Equal(uint32(150), state.Execution.SecurityParam)
+	req.Equal(prev.Execution.Members, state.Execution.Members)
+	req.Equal(prev.Execution.NIP, state.Execution.NIP)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// epochSchedule aligns the rounds to the network epochs. Round k is open from genesis + phaseShift + k*epochDuration
+// until the opening of round k+1, hence its ID is the number of the epoch during which it is open.
+// Rounds which are opened before the first epoch starts are assigned to epoch 0.
+type epochSchedule struct {
+	genesis       time.Time
+	epochDuration time.Duration
+	phaseShift    time.Duration
+}
+
+// newEpochSchedule creates the rounds schedule per the config. It returns nil if genesis time isn't specified.
+func newEpochSchedule(cfg *Config) (*epochSchedule, error) {
+	if cfg.GenesisTime == "" {
+		return nil, nil
+	}
+
+	genesis, err := time.Parse(time.RFC3339, cfg.GenesisTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis time: %v", err)
+	}
+	if cfg.EpochDuration <= 0 {
+		return nil, errors.New("epoch duration must be positive if genesis time is specified")
+	}
+	if cfg.PhaseShift < 0 || cfg.PhaseShift >= cfg.EpochDuration {
+		return nil, errors.New("phase shift must be non-negative and shorter than the epoch duration")
+	}
+
+	return &epochSchedule{
+		genesis:       genesis,
+		epochDuration: cfg.EpochDuration,
+		phaseShift:    cfg.PhaseShift,
+	}, nil
+}
+
+// epoch returns the number of the epoch which a given time is within.
+func (e *epochSchedule) epoch(t time.Time) uint64 {
+	elapsed := t.Sub(e.genesis.Add(e.phaseShift))
+	if elapsed < 0 {
+		return 0
+	}
+	return uint64(elapsed / e.epochDuration)
+}
+
+// epochStart returns the opening time of the round of a given epoch.
+func (e *epochSchedule) epochStart(epoch uint64) time.Time {
+	return e.genesis.Add(e.phaseShift).Add(time.Duration(epoch) * e.epochDuration)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestEpochSchedule(t *testing.T) {
+	req := require.New(t)
+
+	schedule, err := newEpochSchedule(&Config{})
+	req.NoError(err)
+	req.Nil(schedule)
+
+	_, err = newEpochSchedule(&Config{GenesisTime: "yesterday", EpochDuration: time.Hour})
+	req.Error(err)
+	_, err = newEpochSchedule(&Config{GenesisTime: "2020-01-01T00:00:00Z"})
+	req.Error(err)
+	_, err = newEpochSchedule(&Config{GenesisTime: "2020-01-01T00:00:00Z", EpochDuration: time.Hour, PhaseShift: time.Hour})
+	req.Error(err)
+
+	schedule, err = newEpochSchedule(&Config{
+		GenesisTime:   "2020-01-01T00:00:00Z",
+		EpochDuration: time.Hour,
+		PhaseShift:    10 * time.Minute,
+	})
+	req.NoError(err)
+
+	genesis := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	req.Equal(uint64(0), schedule.epoch(genesis.Add(-time.Hour)))
+	req.Equal(uint64(0), schedule.epoch(genesis.Add(5*time.Minute)))
+	req.Equal(uint64(0), schedule.epoch(genesis.Add(10*time.Minute)))
+	req.Equal(uint64(0), schedule.epoch(genesis.Add(69*time.Minute)))
+	req.Equal(uint64(1), schedule.epoch(genesis.Add(70*time.Minute)))
+	req.Equal(uint64(24), schedule.epoch(genesis.Add(24*time.Hour+10*time.Minute)))
+
+	req.True(genesis.Add(10 * time.Minute).Equal(schedule.epochStart(0)))
+	req.True(genesis.Add(3*time.Hour + 10*time.Minute).Equal(schedule.epochStart(3)))
+	req.Equal(uint64(3), schedule.epoch(schedule.epochStart(3)))
+}
@@ -2,17 +2,23 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/nullstyle/go-xdr/xdr3"
+	"github.com/spacemeshos/poet/auth"
 	"github.com/spacemeshos/poet/broadcaster"
+	"github.com/spacemeshos/poet/prover"
 	"github.com/spacemeshos/poet/shared"
 	"github.com/spacemeshos/poet/signal"
 	"github.com/spacemeshos/smutil/log"
 	"golang.org/x/crypto/ed25519"
+	"io"
 	"io/ioutil"
+	"math/bits"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -21,6 +27,8 @@ import (
 
 type Config struct {
 	N                        uint          `long:"n" description:"PoET time parameter"`
+	NumLeaves                uint64        `long:"num-leaves" description:"number of leaves (ticks) of each round proof. if not specified, 2^n leaves are used"`
+	SecurityParam            uint32        `long:"security-param" description:"number of proven leaves of each round proof. if not specified, the default security param is used"`
 	MemoryLayers             uint          `long:"memory" description:"Number of top Merkle tree layers to cache in-memory"`
 	RoundsDuration           time.Duration `long:"duration" description:"duration of the opening time for each round. If not specified, rounds duration will be determined by its previous round end of PoET execution"`
 	InitialRoundDuration     time.Duration `long:"initialduration" description:"duration of the opening time for the initial round. if rounds duration isn't specified, this param is necessary"`
@@ -28,15 +36,35 @@ type Config struct {
 	NoRecovery               bool          `long:"norecovery" description:"whether to disable a potential recovery procedure"`
 	Reset                    bool          `long:"reset" description:"whether to reset the service state by deleting the datadir"`
 	GatewayAddresses         []string      `long:"gateway" description:"list of Spacemesh gateway nodes RPC listeners (host:port) for broadcasting of proofs"`
+	GatewayTLS               []string      `long:"gateway-tls" description:"list of TLS configs of Spacemesh gateway nodes connections (address[,ca=file][,cert=file,key=file][,server-name=name]). gateway nodes and gateway sinks without one are connected insecurely"`
 	DisableBroadcast         bool          `long:"disablebroadcast" description:"whether to disable broadcasting of proofs"`
+	Sinks                    []string      `long:"sink" description:"list of additional destinations for broadcasting of proofs (kind:target), e.g. webhook:https://host/path, dir:/path or gateway:host:port"`
+	SinkAcksThreshold        uint          `long:"sink-acks" description:"number of required successful broadcasts via the sinks, counting the gateway nodes as a single sink (0 for all)"`
 	ConnAcksThreshold        uint          `long:"conn-acks" description:"number of required successful connections to Spacemesh gateway nodes"`
 	BroadcastAcksThreshold   uint          `long:"broadcast-acks" description:"number of required successful broadcasts via Spacemesh gateway nodes"`
-	BroadcastNumRetries      uint          `long:"broadcast-num-retries" description:"number of broadcast retries"`
-	BroadcastRetriesInterval time.Duration `long:"broadcast-retries-interval" description:"duration interval between broadcast retries"`
+	BroadcastNumRetries      uint          `long:"broadcast-num-retries" description:"number of broadcast retries, after which the broadcast waits for being forced"`
+	BroadcastRetriesInterval time.Duration `long:"broadcast-retries-interval" description:"duration interval before the first broadcast retry. it doubles with each retry, up to an hour"`
+	ArchiveRetentionCount    uint          `long:"archive-retention-count" description:"number of broadcast rounds to keep in the archive (0 for unlimited)"`
+	ArchiveRetentionAge      time.Duration `long:"archive-retention-age" description:"duration to keep each broadcast round in the archive (0 for unlimited)"`
+	GenesisTime              string        `long:"genesis-time" description:"genesis time of the network (RFC3339). If specified, rounds open and close at the network epochs boundaries, and are identified by their epoch number"`
+	EpochDuration            time.Duration `long:"epoch-duration" description:"duration of a network epoch. required if genesis time is specified"`
+	PhaseShift               time.Duration `long:"phase-shift" description:"duration to shift the rounds opening time from the network epochs start"`
+	MaxChallengeSize         uint          `long:"max-challenge-size" description:"maximum size of a submitted challenge, in bytes (0 for unlimited)"`
+	MaxRoundMembers          uint          `long:"max-round-members" description:"maximum number of challenges per round (0 for unlimited)"`
+	SubmitRate               float64       `long:"submit-rate" description:"maximum rate of submissions per second, from all peers (0 for unlimited)"`
+	SubmitBurst              uint          `long:"submit-burst" description:"number of submissions which may exceed the submit rate in a burst (0 for the rate rounded up)"`
+	PeerSubmitRate           float64       `long:"peer-submit-rate" description:"maximum rate of submissions per second, from each peer (0 for unlimited)"`
+	PeerSubmitBurst          uint          `long:"peer-submit-burst" description:"number of submissions from each peer which may exceed the peer submit rate in a burst (0 for the rate rounded up)"`
+	RequireSignedSubmissions bool          `long:"require-signed-submissions" description:"whether to reject submissions which aren't signed by a node identity"`
+	SubmitAllowlist          []string      `long:"submit-allow" description:"list of node identities (hex-encoded public keys) which are allowed to submit. if specified, submissions must be signed"`
+	SubmitDenylist           []string      `long:"submit-deny" description:"list of node identities (hex-encoded public keys) which aren't allowed to submit. if specified, submissions must be signed. can't be specified along with an allowlist"`
 }
 
 const serviceStateFileBaseName = "state.bin"
 
+// gatewaysSinkName is the name of the gateway nodes broadcaster, as a sink among the configured sinks.
+const gatewaysSinkName = "gateways"
+
 type serviceState struct {
 	NextRoundID int
 	PrivKey     []byte
@@ -59,13 +87,39 @@ type Service struct {
 	// and the proof generation duration (cfg.N + runtime variance + caching policies).
 	executingRounds map[string]*round
 
+	// broadcastingRounds are the rounds which their execution ended, and their proof broadcast is pending.
+	broadcastingRounds map[string]*round
+
 	prevRound   *round
 	nextRoundID int
 
+	// schedule aligns the rounds to the network epochs. It is nil if rounds aren't epoch-aligned.
+	schedule *epochSchedule
+
+	// clock is the time source of the rounds schedule and closure.
+	clock clock
+
 	PubKey      ed25519.PublicKey
 	privKey     ed25519.PrivateKey
 	broadcaster Broadcaster
 
+	// gateways is the broadcaster of the gateway nodes, which is replaced on gateways update.
+	// sinks are the configured additional broadcast destinations, which are kept across gateways updates.
+	gateways Broadcaster
+	sinks    map[string]broadcaster.Sink
+
+	// archive keeps the proofs of the rounds which were broadcast, after their data directory is removed.
+	archive *archive
+
+	// admission applies the submissions rate limits.
+	admission *admission
+
+	// identities admits the node identities of the signed submissions.
+	identities *identityFilter
+
+	// events publishes the rounds lifecycle events to subscribers.
+	events *events
+
 	errChan chan error
 	sig     *signal.Signal
 	sync.Mutex
@@ -74,49 +128,127 @@ type Service struct {
 type InfoResponse struct {
 	OpenRoundID        string
 	ExecutingRoundsIds []string
+
+	// ExecutingRoundsProgress is the latest reported execution progress of the executing rounds, by their ID.
+	ExecutingRoundsProgress map[string]*prover.Progress
+}
+
+// RoundPhase is the lifecycle phase of a round.
+type RoundPhase int
+
+const (
+	RoundPhaseOpen RoundPhase = iota
+	RoundPhaseExecuting
+	RoundPhaseExecuted
+	RoundPhaseBroadcasted
+)
+
+func (p RoundPhase) String() string {
+	switch p {
+	case RoundPhaseOpen:
+		return "open"
+	case RoundPhaseExecuting:
+		return "executing"
+	case RoundPhaseExecuted:
+		return "executed"
+	case RoundPhaseBroadcasted:
+		return "broadcasted"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(p))
+	}
+}
+
+// RoundInfo describes the status of a round known to the service, either on disk or in the archive.
+type RoundInfo struct {
+	ID                   string
+	Phase                RoundPhase
+	Opened               time.Time
+	ExecutionStarted     time.Time
+	NumMembers           int
+	NextLeafID           uint64
+	NumLeaves            uint64
+	LastBroadcastAttempt time.Time
+
+	// Outbox is the proof broadcast record of an executed round, or nil if its broadcast didn't start yet
+	// or if the round was archived.
+	Outbox *Outbox
+}
+
+// MembershipProof is a Merkle proof of the membership of a challenge in a round members list,
+// whose root is the round statement.
+type MembershipProof struct {
+	Index int
+	Root  []byte
+	Proof [][]byte
 }
 
 type PoetProof struct {
-	N         uint
-	Statement []byte
-	Proof     *shared.MerkleProof
+	N             uint
+	NumLeaves     uint64
+	SecurityParam uint32
+	Statement     []byte
+	Proof         *shared.MerkleProof
+	Members       [][]byte
+}
+
+// numLeavesN returns the N which a round was executed with, i.e. the log2 of its number of leaves,
+// rather than the configured one, which may have changed since.
+func numLeavesN(numLeaves uint64) uint {
+	if numLeaves == 0 {
+		return 0
+	}
+	return uint(bits.Len64(numLeaves) - 1)
 }
 
 var (
 	ErrNotStarted     = errors.New("service not started")
 	ErrAlreadyStarted = errors.New("already started")
+
+	ErrRoundNotFound           = errors.New("round not found")
+	ErrRoundMembersUnavailable = errors.New("round members are not available before execution")
+	ErrNotMember               = errors.New("challenge is not a member of the round")
+	ErrRoundNotBroadcasting    = errors.New("round proof broadcast is not pending")
+	ErrRoundNotOpened          = errors.New("round wasn't open")
+	ErrRoundOpen               = errors.New("round is open")
+	ErrRoundExecuting          = errors.New("round is executing")
+	ErrRoundNotExecuted        = errors.New("round execution has not ended")
 )
 
 type Broadcaster interface {
 	BroadcastProof(msg []byte, roundID string, members [][]byte) error
 }
 
-type GossipPoetProof struct {
-	// The actual proof.
-	shared.MerkleProof
-
-	// Members is the ordered list of miners challenges which are included
-	// in the proof (by using the list hash digest as the proof generation input (the statement)).
-	Members [][]byte
-
-	// NumLeaves is the width of the proof-generation tree.
-	NumLeaves uint64
-}
-
-type PoetProofMessage struct {
-	GossipPoetProof
-	ServicePubKey []byte
-	RoundID       string
-	Signature     []byte
-}
+// GossipPoetProof and PoetProofMessage are defined in the shared package, so that the proof messages
+// can be verified without depending on the service.
+type (
+	GossipPoetProof  = shared.GossipPoetProof
+	PoetProofMessage = shared.PoetProofMessage
+)
 
 func NewService(sig *signal.Signal, cfg *Config, datadir string) (*Service, error) {
+	// The proofs must have at least as many leaves as the number of proven leaves, otherwise they fail verification.
+	numLeaves := cfg.NumLeaves
+	if numLeaves == 0 {
+		numLeaves = uint64(1) << cfg.N
+	}
+	securityParam := cfg.SecurityParam
+	if securityParam == 0 {
+		securityParam = shared.T
+	}
+	if uint64(securityParam) > numLeaves {
+		return nil, fmt.Errorf("security param (%d) must not exceed the number of leaves (%d)", securityParam, numLeaves)
+	}
+
 	s := new(Service)
 	s.cfg = cfg
 	s.datadir = datadir
 	s.executingRounds = make(map[string]*round)
+	s.broadcastingRounds = make(map[string]*round)
+	s.events = newEvents()
+	s.admission = newAdmission(cfg)
 	s.errChan = make(chan error, 10)
 	s.sig = sig
+	s.clock = realClock{}
 
 	if cfg.Reset {
 		entries, err := ioutil.ReadDir(datadir)
@@ -124,12 +256,35 @@ func NewService(sig *signal.Signal, cfg *Config, datadir string) (*Service, erro
 			return nil, err
 		}
 		for _, entry := range entries {
+			// The calibration result is a property of the host rather than of the service state,
+			// and the RPC authentication tokens are credentials which the clients hold.
+			if entry.Name() == calibrationFileBaseName || strings.HasSuffix(entry.Name(), auth.TokenFileExt) {
+				continue
+			}
 			if err := os.RemoveAll(filepath.Join(s.datadir, entry.Name())); err != nil {
 				return nil, err
 			}
 		}
 	}
 
+	archive, err := newArchive(filepath.Join(datadir, archiveDirName), cfg.ArchiveRetentionCount, cfg.ArchiveRetentionAge)
+	if err != nil {
+		return nil, err
+	}
+	s.archive = archive
+
+	schedule, err := newEpochSchedule(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.schedule = schedule
+
+	identities, err := newIdentityFilter(cfg.SubmitAllowlist, cfg.SubmitDenylist)
+	if err != nil {
+		return nil, err
+	}
+	s.identities = identities
+
 	state, err := s.state()
 	if err != nil {
 		if !strings.Contains(err.Error(), "file is missing") {
@@ -144,20 +299,31 @@ func NewService(sig *signal.Signal, cfg *Config, datadir string) (*Service, erro
 
 	log.Info("Service public key: %x", s.PubKey)
 
-	if len(cfg.GatewayAddresses) > 0 || cfg.DisableBroadcast {
-		b, err := broadcaster.New(
-			cfg.GatewayAddresses,
-			cfg.DisableBroadcast,
-			broadcaster.DefaultConnTimeout,
-			cfg.ConnAcksThreshold,
-			broadcaster.DefaultBroadcastTimeout,
-			cfg.BroadcastAcksThreshold,
-		)
+	if len(cfg.GatewayAddresses) > 0 || cfg.DisableBroadcast || len(cfg.Sinks) > 0 {
+		gatewaysTLS, sinksTLS, err := parseTLSConfigs(cfg)
 		if err != nil {
 			return nil, err
 		}
 
+		if !cfg.DisableBroadcast {
+			if s.sinks, err = newSinks(cfg.Sinks, sinksTLS); err != nil {
+				return nil, err
+			}
+		}
+
+		var b Broadcaster
+		if len(cfg.GatewayAddresses) > 0 || cfg.DisableBroadcast {
+			if b, err = newGatewaysBroadcaster(cfg, gatewaysTLS); err != nil {
+				closeSinks(s.sinks)
+				return nil, err
+			}
+		}
+
 		if err := s.Start(b); err != nil {
+			closeSinks(s.sinks)
+			if c, ok := b.(io.Closer); ok {
+				c.Close()
+			}
 			return nil, fmt.Errorf("failed to start service: %v", err)
 		}
 	} else {
@@ -167,6 +333,82 @@ func NewService(sig *signal.Signal, cfg *Config, datadir string) (*Service, erro
 	return s, nil
 }
 
+// parseTLSConfigs parses the configured gateway nodes TLS configs, and returns the ones of the gateway nodes
+// and the ones of the gateway sinks, by their addresses.
+func parseTLSConfigs(cfg *Config) (gateways map[string]*broadcaster.TLSConfig, sinks map[string]*broadcaster.TLSConfig, err error) {
+	tlsConfigs, err := broadcaster.ParseTLSConfigs(cfg.GatewayTLS)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sinkAddresses := make(map[string]bool)
+	for _, spec := range cfg.Sinks {
+		if kind, target, err := broadcaster.ParseSinkSpec(spec); err == nil && kind == "gateway" {
+			sinkAddresses[target] = true
+		}
+	}
+
+	gateways = make(map[string]*broadcaster.TLSConfig)
+	sinks = make(map[string]*broadcaster.TLSConfig)
+	for address, tlsConfig := range tlsConfigs {
+		known := false
+		for _, gatewayAddress := range cfg.GatewayAddresses {
+			if gatewayAddress == address {
+				gateways[address] = tlsConfig
+				known = true
+			}
+		}
+		if sinkAddresses[address] {
+			sinks[address] = tlsConfig
+			known = true
+		}
+		if !known {
+			return nil, nil, fmt.Errorf("TLS config of unknown gateway address %q", address)
+		}
+	}
+
+	return gateways, sinks, nil
+}
+
+// newGatewaysBroadcaster creates the broadcaster of the configured gateway nodes, with their TLS configs.
+func newGatewaysBroadcaster(cfg *Config, tlsConfigs map[string]*broadcaster.TLSConfig) (Broadcaster, error) {
+	return broadcaster.New(
+		cfg.GatewayAddresses,
+		tlsConfigs,
+		cfg.DisableBroadcast,
+		broadcaster.DefaultConnTimeout,
+		cfg.ConnAcksThreshold,
+		broadcaster.DefaultBroadcastTimeout,
+		cfg.BroadcastAcksThreshold,
+	)
+}
+
+// newSinks creates the sinks of the given specs, by their specs, where the gateway sinks are connected
+// with their TLS config, if any. The sinks which were already created are closed if a later one fails.
+func newSinks(specs []string, tlsConfigs map[string]*broadcaster.TLSConfig) (map[string]broadcaster.Sink, error) {
+	sinks := make(map[string]broadcaster.Sink, len(specs))
+	for _, spec := range specs {
+		sink, err := broadcaster.NewSinkWithTLS(spec, tlsConfigs)
+		if err != nil {
+			closeSinks(sinks)
+			return nil, err
+		}
+		sinks[spec] = sink
+	}
+	return sinks, nil
+}
+
+// closeSinks closes the sinks which hold resources, such as gateway nodes connections.
+func closeSinks(sinks map[string]broadcaster.Sink) {
+	for name, sink := range sinks {
+		if c, ok := sink.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				log.Error("Failed to close sink %q: %v", name, err)
+			}
+		}
+	}
+}
+
 func (s *Service) initialState() *serviceState {
 	_, priv, err := ed25519.GenerateKey(nil)
 	if err != nil {
@@ -205,7 +447,10 @@ func (s *Service) Start(b Broadcaster) error {
 		return ErrAlreadyStarted
 	}
 
-	s.SetBroadcaster(b)
+	if err := s.SetBroadcaster(b); err != nil {
+		atomic.StoreInt32(&s.started, 0)
+		return err
+	}
 
 	if s.cfg.NoRecovery {
 		log.Info("Recovery is disabled")
@@ -222,13 +467,29 @@ func (s *Service) Start(b Broadcaster) error {
 		for {
 			select {
 			case <-s.openRoundClosure():
+			case <-s.sig.ShutdownRequestedChan:
+			}
+
+			// Shutdown takes precedence over a closure which is due at the same time, since the open round
+			// is torn down on shutdown.
+			select {
 			case <-s.sig.ShutdownRequestedChan:
 				log.Info("Shutdown requested, service shutting down")
 				s.openRound = nil
 				return
+			default:
 			}
 
 			if s.openRound.isEmpty() && !s.cfg.ExecuteEmpty {
+				if s.schedule == nil {
+					continue
+				}
+
+				// An epoch-aligned round can't be extended beyond its epoch, hence it's replaced.
+				log.Info("Round %v discarded, no challenges were submitted", s.openRound.ID)
+				s.openRound.discard()
+				s.openRound = s.newRound()
+				log.Info("Round %v opened", s.openRound.ID)
 				continue
 			}
 
@@ -240,11 +501,12 @@ func (s *Service) Start(b Broadcaster) error {
 			go func() {
 				r := s.prevRound
 				if err := s.executeRound(r); err != nil {
+					r.failed()
 					s.asyncError(fmt.Errorf("round %v execution error: %v", r.ID, err))
 					return
 				}
 
-				broadcastProof(s, r, r.execution, s.broadcaster)
+				broadcastProof(s, r, r.execution)
 			}()
 		}
 	}()
@@ -263,7 +525,7 @@ func (s *Service) Recover() error {
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.IsDir() || entry.Name() == archiveDirName {
 			continue
 		}
 
@@ -284,12 +546,20 @@ func (s *Service) Recover() error {
 			// Keep the last open round as openRound (multiple open rounds state is possible
 			// only if recovery was previously disabled).
 			s.openRound = r
+			s.watchRound(r, EventRoundOpened)
 			continue
 		}
 
 		if state.isExecuted() {
 			log.Info("Recovery: found round %v in executed state. broadcasting...", r.ID)
-			go broadcastProof(s, r, state.Execution, s.broadcaster)
+			r.recoverExecuted()
+			s.watchRound(r, EventRoundBroadcasted)
+
+			// Register the round before its broadcast resumes, so that it can be forced right away.
+			s.Lock()
+			s.broadcastingRounds[r.ID] = r
+			s.Unlock()
+			go broadcastProof(s, r, r.execution)
 			continue
 		}
 
@@ -298,6 +568,7 @@ func (s *Service) Recover() error {
 		// Keep the last executing round as prevRound for potentially affecting
 		// the closure of the current open round (see openRoundClosure()).
 		s.prevRound = r
+		s.watchRound(r, EventRoundExecutionStarted)
 
 		go func() {
 			s.Lock()
@@ -310,26 +581,79 @@ func (s *Service) Recover() error {
 			}()
 
 			if err = r.recoverExecution(state.Execution); err != nil {
+				r.failed()
 				s.asyncError(fmt.Errorf("recovery: round %v execution failure: %v", r.ID, err))
 				return
 			}
 
 			log.Info("Recovery: round %v execution ended, phi=%x", r.ID, r.execution.NIP.Root)
-			broadcastProof(s, r, r.execution, s.broadcaster)
+			broadcastProof(s, r, r.execution)
 		}()
 	}
 
 	return nil
 }
 
-func (s *Service) SetBroadcaster(b Broadcaster) {
-	initial := s.broadcaster == nil
-	s.broadcaster = b
+// SetBroadcaster sets the broadcaster of the gateway nodes. If sinks are configured, the proofs are broadcast
+// to all of them, and to the gateway nodes as an additional sink. The previous gateway nodes broadcaster, if any,
+// is closed if it holds resources, while the sinks are kept. Pending broadcasts use the new broadcaster from
+// their next attempt.
+func (s *Service) SetBroadcaster(b Broadcaster) error {
+	effective := b
+	if len(s.sinks) > 0 {
+		sinks := make(map[string]broadcaster.Sink, len(s.sinks)+1)
+		for name, sink := range s.sinks {
+			sinks[name] = sink
+		}
+		if b != nil {
+			sinks[gatewaysSinkName] = b
+		}
+
+		f, err := broadcaster.NewFanout(sinks, s.cfg.SinkAcksThreshold)
+		if err != nil {
+			return err
+		}
+		effective = f
+	} else if b == nil {
+		return errors.New("broadcaster must not be nil")
+	}
+
+	s.Lock()
+	prev := s.gateways
+	s.gateways = b
+	s.broadcaster = effective
+	s.Unlock()
 
-	if !initial {
+	if prev != nil {
+		if c, ok := prev.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				log.Error("Failed to close the previous broadcaster: %v", err)
+			}
+		}
 		log.Info("Service broadcaster updated")
 	}
 
+	return nil
+}
+
+func (s *Service) currentBroadcaster() Broadcaster {
+	s.Lock()
+	defer s.Unlock()
+	return s.broadcaster
+}
+
+// Gateways returns the connection status of the broadcaster gateway nodes,
+// or nil if the broadcaster doesn't report it.
+func (s *Service) Gateways() ([]broadcaster.GatewayStatus, error) {
+	if !s.Started() {
+		return nil, ErrNotStarted
+	}
+
+	r, ok := s.currentBroadcaster().(broadcaster.GatewaysReporter)
+	if !ok {
+		return nil, nil
+	}
+	return r.Gateways(), nil
 }
 
 func (s *Service) executeRound(r *round) error {
@@ -354,14 +678,35 @@ func (s *Service) executeRound(r *round) error {
 	return nil
 }
 
-func (s *Service) Submit(data []byte) (*round, error) {
+// Submit submits an unsigned challenge from a given peer to the open round. If the submission is rejected by
+// an admission limit, a *SubmitLimitError is returned.
+func (s *Service) Submit(data []byte, peer string) (*round, error) {
+	return s.SubmitSigned(data, peer, nil)
+}
+
+// SubmitSigned submits a challenge from a given peer to the open round, which is signed by the submitting node
+// identity, or is unsigned if sig is nil. Each identity may submit a single challenge per round.
+func (s *Service) SubmitSigned(data []byte, peer string, sig *SubmissionSignature) (*round, error) {
 	if !s.Started() {
 		return nil, ErrNotStarted
 	}
 
+	if err := s.admission.admit(data, peer, time.Now()); err != nil {
+		return nil, err
+	}
+
 	r := s.openRound
-	err := r.submit(data)
+	var identity []byte
+	if sig != nil {
+		identity = sig.PubKey
+	}
+	err := s.verifySubmission(r, data, sig)
+	if err == nil {
+		err = r.submit(data, identity)
+	}
 	if err != nil {
+		// Rejected submissions don't count towards the rate limits.
+		s.admission.refund(peer)
 		return nil, err
 	}
 
@@ -378,16 +723,284 @@ func (s *Service) Info() (*InfoResponse, error) {
 
 	s.Lock()
 	ids := make([]string, 0, len(s.executingRounds))
-	for id := range s.executingRounds {
+	progress := make(map[string]*prover.Progress)
+	for id, r := range s.executingRounds {
 		ids = append(ids, id)
+		if p := r.progress(); p != nil {
+			progress[id] = p
+		}
 	}
 	s.Unlock()
 	res.ExecutingRoundsIds = ids
+	res.ExecutingRoundsProgress = progress
+
+	return res, nil
+}
+
+// MembershipProof returns a Merkle proof of the membership of a given challenge in the members list of a given round.
+// The proof is available once the round has started executing, and as long as its state is kept on disk.
+func (s *Service) MembershipProof(roundID string, challenge []byte) (*MembershipProof, error) {
+	if !s.Started() {
+		return nil, ErrNotStarted
+	}
+
+	state, err := s.roundState(roundID)
+	if err == ErrRoundNotFound {
+		archived, err := s.archive.get(roundID)
+		if err != nil {
+			return nil, err
+		}
+		return calcMembershipProof(archived.Members, challenge)
+	} else if err != nil {
+		return nil, err
+	}
+	if state.isOpen() || state.Execution.Statement == nil {
+		return nil, ErrRoundMembersUnavailable
+	}
+
+	return calcMembershipProof(state.Execution.Members, challenge)
+}
+
+// Proof returns the proof of a given round. If wait is true and the round is still open or executing,
+// it blocks until the round execution ends, or until ctx is done.
+func (s *Service) Proof(ctx context.Context, roundID string, wait bool) (*PoetProof, error) {
+	if !s.Started() {
+		return nil, ErrNotStarted
+	}
+
+	if r := s.activeRound(roundID); r != nil {
+		if wait {
+			select {
+			case <-r.executionEndedChan:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		return r.proof(false)
+	}
+
+	state, err := s.roundState(roundID)
+	if err == ErrRoundNotFound {
+		archived, err := s.archive.get(roundID)
+		if err != nil {
+			return nil, err
+		}
+		return &PoetProof{
+			N:             numLeavesN(archived.NumLeaves),
+			NumLeaves:     archived.NumLeaves,
+			SecurityParam: archived.SecurityParam,
+			Statement:     archived.Statement,
+			Proof:         archived.NIP,
+			Members:       archived.Members,
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if !state.isExecuted() {
+		return nil, ErrRoundNotExecuted
+	}
+
+	return &PoetProof{
+		N:             numLeavesN(state.Execution.NumLeaves),
+		NumLeaves:     state.Execution.NumLeaves,
+		SecurityParam: state.Execution.SecurityParam,
+		Statement:     state.Execution.Statement,
+		Proof:         state.Execution.NIP,
+		Members:       state.Execution.Members,
+	}, nil
+}
+
+// Rounds returns the status of all the rounds known to the service, ordered by their opening time.
+func (s *Service) Rounds() ([]*RoundInfo, error) {
+	if !s.Started() {
+		return nil, ErrNotStarted
+	}
+
+	entries, err := ioutil.ReadDir(s.datadir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make(map[string]*RoundInfo)
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == archiveDirName {
+			continue
+		}
+
+		info, err := s.roundInfo(entry.Name())
+		if err == ErrRoundNotFound {
+			// Round was torn down or was never persisted.
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("round %v: %v", entry.Name(), err)
+		}
+		infos[info.ID] = info
+	}
+
+	archivedIds, err := s.archive.ids()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range archivedIds {
+		archived, err := s.archive.get(id)
+		if err == ErrRoundNotFound {
+			// Round was pruned.
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("archived round %v: %v", id, err)
+		}
+		infos[id] = archivedRoundInfo(archived)
+	}
+
+	res := make([]*RoundInfo, 0, len(infos))
+	for _, info := range infos {
+		res = append(res, info)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Opened.Equal(res[j].Opened) {
+			return res[i].ID < res[j].ID
+		}
+		return res[i].Opened.Before(res[j].Opened)
+	})
 
 	return res, nil
 }
 
+// Round returns the status of a given round, either on disk or in the archive.
+func (s *Service) Round(roundID string) (*RoundInfo, error) {
+	if !s.Started() {
+		return nil, ErrNotStarted
+	}
+
+	if archived, err := s.archive.get(roundID); err == nil {
+		return archivedRoundInfo(archived), nil
+	} else if err != ErrRoundNotFound {
+		return nil, err
+	}
+
+	return s.roundInfo(roundID)
+}
+
+func (s *Service) roundInfo(roundID string) (*RoundInfo, error) {
+	state, err := s.roundState(roundID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &RoundInfo{
+		ID:               roundID,
+		Opened:           state.Opened,
+		ExecutionStarted: state.ExecutionStarted,
+		NumMembers:       len(state.Execution.Members),
+		NextLeafID:       state.Execution.NextLeafID,
+		NumLeaves:        state.Execution.NumLeaves,
+	}
+
+	switch {
+	case state.isOpen():
+		info.Phase = RoundPhaseOpen
+		if r := s.activeRound(roundID); r != nil {
+			info.NumMembers = r.numChallenges()
+		}
+	case state.isExecuted():
+		info.Phase = RoundPhaseExecuted
+		info.NextLeafID = state.Execution.NumLeaves
+		if info.Outbox, err = s.roundOutbox(roundID); err != nil {
+			return nil, err
+		}
+		if info.Outbox != nil {
+			info.LastBroadcastAttempt = info.Outbox.lastAttempt()
+			if info.Outbox.Broadcasted {
+				// The round data directory was kept since archiving it failed.
+				info.Phase = RoundPhaseBroadcasted
+			}
+		}
+	default:
+		info.Phase = RoundPhaseExecuting
+	}
+
+	return info, nil
+}
+
+// roundOutbox returns the proof broadcast outbox of a given round on disk, or nil if its broadcast didn't start yet.
+func (s *Service) roundOutbox(roundID string) (*Outbox, error) {
+	outbox := new(Outbox)
+	if err := load(filepath.Join(s.datadir, roundID, outboxFileBaseName), outbox); err != nil {
+		if strings.Contains(err.Error(), "file is missing") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return outbox, nil
+}
+
+func archivedRoundInfo(archived *ArchivedRound) *RoundInfo {
+	return &RoundInfo{
+		ID:                   archived.ID,
+		Phase:                RoundPhaseBroadcasted,
+		Opened:               archived.Opened,
+		ExecutionStarted:     archived.ExecutionStarted,
+		NumMembers:           len(archived.Members),
+		NextLeafID:           archived.NumLeaves,
+		NumLeaves:            archived.NumLeaves,
+		LastBroadcastAttempt: archived.LastBroadcastAttempt,
+	}
+}
+
+// ArchivedRound returns the archived record of a given round which its proof was broadcast.
+func (s *Service) ArchivedRound(roundID string) (*ArchivedRound, error) {
+	return s.archive.get(roundID)
+}
+
+// ArchivedRoundsIds returns the IDs of the rounds kept in the archive, ordered from the least recently archived.
+func (s *Service) ArchivedRoundsIds() ([]string, error) {
+	return s.archive.ids()
+}
+
+// activeRound returns the in-memory instance of a given round, if it's currently open or executing.
+func (s *Service) activeRound(roundID string) *round {
+	s.Lock()
+	defer s.Unlock()
+
+	if r, ok := s.executingRounds[roundID]; ok {
+		return r
+	}
+	if s.openRound != nil && s.openRound.ID == roundID {
+		return s.openRound
+	}
+
+	return nil
+}
+
+// roundState loads the persisted state of a given round from its data directory.
+func (s *Service) roundState(roundID string) (*roundState, error) {
+	if roundID == "" || roundID == "." || roundID == ".." || filepath.Base(roundID) != roundID {
+		return nil, ErrRoundNotFound
+	}
+
+	filename := filepath.Join(s.datadir, roundID, roundStateFileBaseName)
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return nil, ErrRoundNotFound
+	}
+
+	state := &roundState{}
+	if err := load(filename, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
 func (s *Service) newRound() *round {
+	if s.schedule != nil {
+		// A recovered round might already have the current epoch ID, in which case the new round
+		// is assigned to the next epoch.
+		s.nextRoundID = int(s.schedule.epoch(s.clock.Now()))
+		for s.roundIDInUse(fmt.Sprintf("%d", s.nextRoundID)) {
+			s.nextRoundID++
+		}
+	}
+	epoch := uint64(s.nextRoundID)
 	roundID := fmt.Sprintf("%d", s.nextRoundID)
 	s.nextRoundID++
 	if err := s.saveState(); err != nil {
@@ -397,15 +1010,76 @@ func (s *Service) newRound() *round {
 	datadir := filepath.Join(s.datadir, roundID)
 
 	r := newRound(s.sig, s.cfg, datadir, roundID)
+	if s.schedule != nil {
+		if err := r.saveEpoch(epoch); err != nil {
+			panic(fmt.Errorf("failed to save round epoch: %v", err))
+		}
+	}
 	if err := r.open(); err != nil {
 		panic(fmt.Errorf("failed to open round: %v", err))
 	}
+	s.watchRound(r, EventRoundOpened)
 
 	return r
 }
 
+// roundIDInUse returns whether a given round ID was already assigned to a round, which either has a data directory
+// or was archived.
+func (s *Service) roundIDInUse(roundID string) bool {
+	if _, err := os.Stat(filepath.Join(s.datadir, roundID)); err == nil {
+		return true
+	}
+	_, err := s.archive.get(roundID)
+	return err != ErrRoundNotFound
+}
+
+// watchRound publishes the lifecycle events of a round, starting from a given event type.
+// Recovered rounds don't go through the phases preceding their recovered state, hence they are skipped.
+func (s *Service) watchRound(r *round, from EventType) {
+	phases := []struct {
+		typ EventType
+		c   <-chan struct{}
+	}{
+		{EventRoundOpened, r.openedChan},
+		{EventRoundExecutionStarted, r.executionStartedChan},
+		{EventRoundExecutionEnded, r.executionEndedChan},
+		{EventRoundBroadcasted, r.broadcastedChan},
+	}
+
+	go func() {
+		for _, phase := range phases[from:] {
+			select {
+			case <-phase.c:
+			case <-r.discardedChan:
+				return
+			case <-r.failedChan:
+				return
+			case <-s.sig.ShutdownRequestedChan:
+				return
+			}
+
+			var payload []byte
+			if phase.typ == EventRoundExecutionEnded {
+				payload = r.execution.NIP.Root
+			}
+			s.events.publish(phase.typ, r.ID, payload)
+		}
+	}()
+}
+
+// SubscribeEvents subscribes to the rounds lifecycle events, starting from a given sequence number.
+// If fromSeq is 0, only the events published after the subscription are delivered.
+func (s *Service) SubscribeEvents(fromSeq uint64) (*Subscription, error) {
+	return s.events.subscribe(fromSeq)
+}
+
 // openRoundClosure returns a channel used to notify the closure of the current open round.
 func (s *Service) openRoundClosure() <-chan struct{} {
+	// If rounds are epoch-aligned, notify the closure at the end of the open round epoch.
+	if s.schedule != nil {
+		return s.openRoundClosurePerEpoch()
+	}
+
 	// If it's the initial round, use the initial duration config to notify the closure.
 	if s.prevRound == nil {
 		return s.openRoundClosurePerDuration(s.cfg.InitialRoundDuration)
@@ -424,12 +1098,39 @@ func (s *Service) openRoundClosurePerDuration(d time.Duration) <-chan struct{} {
 	// If the open round was recovered, include the time period from when it was originally opened.
 	var offset time.Duration
 	if s.openRound.stateCache != nil {
-		offset = time.Since(s.openRound.stateCache.Opened)
+		offset = s.clock.Now().Sub(s.openRound.stateCache.Opened)
 	}
 
 	c := make(chan struct{})
+	after := s.clock.After(d - offset)
 	go func() {
-		<-time.After(d - offset)
+		<-after
+		close(c)
+	}()
+	return c
+}
+
+func (s *Service) openRoundClosurePerEpoch() <-chan struct{} {
+	// A recovered round might have been opened before the rounds were epoch-aligned, e.g. with a legacy
+	// counter ID, in which case it is closed at the end of the current epoch.
+	epoch, aligned, err := s.openRound.epoch()
+	if err != nil {
+		log.Error("Round %v: failed to load its epoch: %v", s.openRound.ID, err)
+	}
+	if !aligned {
+		epoch = s.schedule.epoch(s.clock.Now())
+	}
+	end := s.schedule.epochStart(epoch + 1)
+
+	c := make(chan struct{})
+	after := s.clock.After(end.Sub(s.clock.Now()))
+	go func() {
+		// Wait until the epoch end per the clock, rather than per the timer, which may fire early,
+		// so that the next round is assigned to the following epoch.
+		<-after
+		for d := end.Sub(s.clock.Now()); d > 0; d = end.Sub(s.clock.Now()) {
+			<-s.clock.After(d)
+		}
 		close(c)
 	}()
 	return c
@@ -442,34 +1143,162 @@ func (s *Service) asyncError(err error) {
 	s.errChan <- err
 }
 
-func broadcastProof(s *Service, r *round, execution *executionState, broadcaster Broadcaster) {
-	msg, err := serializeProofMsg(s.PubKey, r.ID, execution)
+func broadcastProof(s *Service, r *round, execution *executionState) {
+	outbox, err := r.loadOutbox()
 	if err != nil {
-		log.Error(err.Error())
+		log.Error("Round %v: failed to load broadcast outbox: %v", r.ID, err)
+		r.failed()
 		return
 	}
 
-	bindFunc := func() error { return broadcaster.BroadcastProof(msg, r.ID, r.execution.Members) }
-	logger := func(msg string) { log.Error("Round %v: %v", r.ID, msg) }
+	s.Lock()
+	s.broadcastingRounds[r.ID] = r
+	s.Unlock()
+	defer func() {
+		s.Lock()
+		delete(s.broadcastingRounds, r.ID)
+		s.Unlock()
+	}()
+
+	if outbox.Broadcasted {
+		log.Info("Round %v: proof was already broadcast, archiving", r.ID)
+	} else if !sendProof(s, r, execution, outbox) {
+		return
+	}
 
-	if err := shared.Retry(bindFunc, int(s.cfg.BroadcastNumRetries), s.cfg.BroadcastRetriesInterval, logger); err != nil {
-		log.Error("Round %v proof broadcast failure: %v", r.ID, err)
+	// Archive the round before it is torn down and its data directory is removed. If archiving fails,
+	// the data directory is kept, since it holds the only copy of the proof.
+	if err := s.archiveRound(r.ID); err != nil {
+		r.broadcastedKeepingData()
+		s.asyncError(fmt.Errorf("round %v archiving failure, its data directory is kept: %v", r.ID, err))
 		return
 	}
 
 	r.broadcasted()
 }
 
-func serializeProofMsg(servicePubKey []byte, roundID string, execution *executionState) ([]byte, error) {
+// sendProof broadcasts the proof of a given round, retrying according to its outbox, until it succeeds.
+// It returns false if the broadcast was given up, either since the service is shutting down
+// or since the proof message couldn't be created.
+func sendProof(s *Service, r *round, execution *executionState, outbox *Outbox) bool {
+	msg, err := serializeProofMsg(s.privKey, r.ID, execution)
+	if err != nil {
+		log.Error(err.Error())
+		r.failed()
+		return false
+	}
+
+	for {
+		// Wait for the next attempt, unless a re-broadcast is forced.
+		if outbox.Exhausted {
+			log.Error("Round %v: proof broadcast retries ran out, waiting for a forced re-broadcast", r.ID)
+			select {
+			case <-r.rebroadcastChan:
+				outbox.reset()
+			case <-s.sig.ShutdownRequestedChan:
+				return false
+			}
+		} else if wait := time.Until(outbox.NextRetry); wait > 0 {
+			log.Info("Round %v: next proof broadcast attempt in %v", r.ID, wait)
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-r.rebroadcastChan:
+				outbox.reset()
+			case <-s.sig.ShutdownRequestedChan:
+				timer.Stop()
+				return false
+			}
+			timer.Stop()
+		}
+
+		var results map[string]error
+		b := s.currentBroadcaster()
+		if gb, ok := b.(GatewaysBroadcaster); ok {
+			results, err = gb.BroadcastProofToGateways(msg, r.ID, r.execution.Members, outbox.acked())
+		} else {
+			err = b.BroadcastProof(msg, r.ID, r.execution.Members)
+		}
+		outbox.record(time.Now(), results, err)
+		if err != nil {
+			log.Error("Round %v proof broadcast failure: %v", r.ID, err)
+			outbox.scheduleRetry(time.Now(), s.cfg.BroadcastNumRetries, s.cfg.BroadcastRetriesInterval)
+		} else {
+			outbox.Broadcasted = true
+		}
+
+		// The outbox is saved following successful attempts as well, since it records the attempts time,
+		// and since it marks the round as broadcast before it's archived.
+		if err := r.saveOutbox(outbox); err != nil {
+			log.Error("Round %v: failed to save broadcast outbox: %v", r.ID, err)
+		}
+		if err == nil {
+			return true
+		}
+	}
+}
+
+// Rebroadcast forces an immediate re-broadcast of the proof of a given round, which its broadcast is pending,
+// either since it waits for a retry or since its retries ran out. The round broadcast retries are restarted.
+func (s *Service) Rebroadcast(roundID string) error {
+	if !s.Started() {
+		return ErrNotStarted
+	}
+
+	s.Lock()
+	r, ok := s.broadcastingRounds[roundID]
+	s.Unlock()
+	if !ok {
+		return ErrRoundNotBroadcasting
+	}
+
+	log.Info("Round %v: proof re-broadcast was forced", roundID)
+	r.rebroadcast()
+	return nil
+}
+
+func (s *Service) archiveRound(roundID string) error {
+	state, err := s.roundState(roundID)
+	if err != nil {
+		return err
+	}
+	outbox, err := s.roundOutbox(roundID)
+	if err != nil {
+		return err
+	}
+	var lastBroadcastAttempt time.Time
+	if outbox != nil {
+		lastBroadcastAttempt = outbox.lastAttempt()
+	}
+
+	return s.archive.put(&ArchivedRound{
+		ID:                   roundID,
+		Opened:               state.Opened,
+		ExecutionStarted:     state.ExecutionStarted,
+		Archived:             time.Now(),
+		LastBroadcastAttempt: lastBroadcastAttempt,
+		NumLeaves:            state.Execution.NumLeaves,
+		SecurityParam:        state.Execution.SecurityParam,
+		Members:              state.Execution.Members,
+		Statement:            state.Execution.Statement,
+		NIP:                  state.Execution.NIP,
+	})
+}
+
+func serializeProofMsg(privKey ed25519.PrivateKey, roundID string, execution *executionState) ([]byte, error) {
 	proofMessage := PoetProofMessage{
 		GossipPoetProof: GossipPoetProof{
-			MerkleProof: *execution.NIP,
-			Members:     execution.Members,
-			NumLeaves:   execution.NumLeaves,
+			MerkleProof:   *execution.NIP,
+			Members:       execution.Members,
+			NumLeaves:     execution.NumLeaves,
+			SecurityParam: execution.SecurityParam,
 		},
-		ServicePubKey: servicePubKey,
+		ServicePubKey: privKey.Public().(ed25519.PublicKey),
 		RoundID:       roundID,
-		Signature:     nil,
+	}
+
+	if err := shared.SignProofMessage(privKey, &proofMessage); err != nil {
+		return nil, fmt.Errorf("failed to sign proof message for round %v: %v", roundID, err)
 	}
 
 	var dataBuf bytes.Buffer
@@ -2,15 +2,24 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/nullstyle/go-xdr/xdr3"
 	"github.com/spacemeshos/merkle-tree"
 	"github.com/spacemeshos/poet/prover"
+	"github.com/spacemeshos/poet/shared"
 	"github.com/spacemeshos/poet/signal"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -24,6 +33,90 @@ func (b *MockBroadcaster) BroadcastProof(msg []byte, roundID string, members [][
 	return nil
 }
 
+// mockClock is a clock which advances only when told so, for controlling the rounds timing.
+type mockClock struct {
+	sync.Mutex
+	now    time.Time
+	timers []*mockTimer
+
+	// added is notified when a timer is set.
+	added chan struct{}
+}
+
+type mockTimer struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+func newMockClock(now time.Time) *mockClock {
+	return &mockClock{now: now, added: make(chan struct{}, 1)}
+}
+
+func (c *mockClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *mockClock) After(d time.Duration) <-chan time.Time {
+	c.Lock()
+	defer c.Unlock()
+
+	timer := &mockTimer{deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		timer.c <- c.now
+		return timer.c
+	}
+	c.timers = append(c.timers, timer)
+	select {
+	case c.added <- struct{}{}:
+	default:
+	}
+	return timer.c
+}
+
+// advance moves the clock forward, and fires the timers which are due.
+func (c *mockClock) advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.deadline.After(c.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.c <- c.now
+	}
+	c.timers = pending
+}
+
+// waitTimer waits for a timer to be set, and returns the earliest deadline of the pending timers.
+func (c *mockClock) waitTimer(t *testing.T) time.Time {
+	timeout := time.After(10 * time.Second)
+	for {
+		c.Lock()
+		if len(c.timers) > 0 {
+			deadline := c.timers[0].deadline
+			for _, timer := range c.timers[1:] {
+				if timer.deadline.Before(deadline) {
+					deadline = timer.deadline
+				}
+			}
+			c.Unlock()
+			return deadline
+		}
+		c.Unlock()
+
+		select {
+		case <-c.added:
+		case <-timeout:
+			require.Fail(t, "no timer was set")
+		}
+	}
+}
+
 type challenge struct {
 	data  []byte
 	round *round
@@ -76,7 +169,7 @@ func TestService_Recovery(t *testing.T) {
 	submitChallenges := func(roundIndex int, groupIndex int) {
 		challengesGroup := challengeGroups[groupIndex]
 		for i := 0; i < len(challengeGroups[groupIndex]); i++ {
-			round, err := s.Submit(challengesGroup[i].data)
+			round, err := s.Submit(challengesGroup[i].data, "")
 			req.NoError(err)
 			req.Equal(s.openRound.ID, round.ID)
 
@@ -211,56 +304,97 @@ func contains(list [][]byte, item []byte) bool {
 	return false
 }
 
-func TestNewService(t *testing.T) {
+// startService starts a service whose rounds closure is controlled by a mock clock, and whose proofs
+// are broadcast to a mock broadcaster.
+func startService(t *testing.T, sig *signal.Signal, cfg *Config) (*Service, *mockClock, *MockBroadcaster) {
 	req := require.New(t)
 	tempdir, _ := ioutil.TempDir("", "poet-test")
 
-	cfg := new(Config)
-	cfg.N = 17
-	cfg.InitialRoundDuration = 1 * time.Second
-
-	s, err := NewService(signal.NewSignal(), cfg, tempdir)
+	s, err := NewService(sig, cfg, tempdir)
 	req.NoError(err)
+	clock := newMockClock(time.Now())
+	s.clock = clock
 
-	proofBroadcaster := &MockBroadcaster{receivedMessages: make(chan []byte)}
-	err = s.Start(proofBroadcaster)
-	req.NoError(err)
+	broadcaster := &MockBroadcaster{receivedMessages: make(chan []byte, 1)}
+	req.NoError(s.Start(broadcaster))
+	return s, clock, broadcaster
+}
 
-	challengesCount := 8
-	challenges := make([]challenge, challengesCount)
-	info, err := s.Info()
-	req.NoError(err)
+// executeRound submits challenges to the open round, closes it by advancing the clock, and waits
+// for its execution to end.
+func executeRound(t *testing.T, s *Service, clock *mockClock, challenges [][]byte) *round {
+	req := require.New(t)
 
-	// Generate random challenges.
-	for i := 0; i < len(challenges); i++ {
-		challenges[i] = challenge{data: make([]byte, 32)}
-		_, err := rand.Read(challenges[i].data)
+	var r *round
+	for _, ch := range challenges {
+		var err error
+		r, err = s.Submit(ch, "")
 		req.NoError(err)
 	}
 
-	// Submit challenges.
-	for i := 0; i < len(challenges); i++ {
-		round, err := s.Submit(challenges[i].data)
+	clock.advance(clock.waitTimer(t).Sub(clock.Now()))
+	select {
+	case <-r.executionEndedChan:
+	case err := <-s.errChan:
+		req.Fail(err.Error())
+	case <-time.After(10 * time.Second):
+		req.Fail("round execution didn't end")
+	}
+	return r
+}
+
+// waitTornDown waits for a round to be torn down.
+func waitTornDown(t *testing.T, r *round) {
+	select {
+	case <-r.tornDownChan:
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "round wasn't torn down")
+	}
+}
+
+func TestService_RoundIteration(t *testing.T) {
+	req := require.New(t)
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, clock, _ := startService(t, sig, &Config{N: 10, InitialRoundDuration: time.Hour})
+
+	challenges, err := genChallenges(8)
+	req.NoError(err)
+	info, err := s.Info()
+	req.NoError(err)
+
+	// Submit challenges, which all join the same round instance.
+	var r *round
+	for _, ch := range challenges {
+		round, err := s.Submit(ch, "")
 		req.NoError(err)
 		req.Equal(info.OpenRoundID, round.ID)
-		challenges[i].round = round
-
-		// Verify that all submissions returned the same round instance.
-		if i > 0 {
-			req.Equal(challenges[i].round, challenges[i-1].round)
+		if r != nil {
+			req.Equal(r, round)
 		}
+		r = round
 	}
 
-	// Verify that round is still open.
-	info, err = s.Info()
+	// Verify the open round status.
+	roundInfo, err := s.Round(info.OpenRoundID)
 	req.NoError(err)
-	req.Equal(info.OpenRoundID, info.OpenRoundID)
+	req.Equal(RoundPhaseOpen, roundInfo.Phase)
+	req.Equal(len(challenges), roundInfo.NumMembers)
+	req.False(roundInfo.Opened.IsZero())
+	req.True(roundInfo.ExecutionStarted.IsZero())
+
+	// The round is closed once its duration passes.
+	deadline := clock.waitTimer(t)
+	req.Equal(clock.Now().Add(time.Hour), deadline)
+	clock.advance(time.Hour)
 
-	// Wait for round to start execution.
 	select {
-	case <-challenges[0].round.executionStartedChan:
+	case <-r.executionStartedChan:
 	case err := <-s.errChan:
 		req.Fail(err.Error())
+	case <-time.After(10 * time.Second):
+		req.Fail("round execution didn't start")
 	}
 
 	// Verify that round iteration proceeded.
@@ -270,24 +404,346 @@ func TestNewService(t *testing.T) {
 	prevIndex, err := strconv.Atoi(prevInfo.OpenRoundID)
 	req.NoError(err)
 	req.Equal(fmt.Sprintf("%d", prevIndex+1), info.OpenRoundID)
-	req.Contains(info.ExecutingRoundsIds, prevInfo.OpenRoundID)
 
-	// Wait for end of execution.
+	// The round might have already finished executing, hence only its closure is verified.
+	roundInfo, err = s.Round(prevInfo.OpenRoundID)
+	req.NoError(err)
+	req.NotEqual(RoundPhaseOpen, roundInfo.Phase)
+	req.False(roundInfo.ExecutionStarted.IsZero())
+}
+
+func TestService_MembershipProof(t *testing.T) {
+	req := require.New(t)
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, clock, _ := startService(t, sig, &Config{N: 10, InitialRoundDuration: time.Hour, RoundsDuration: time.Hour})
+
+	challenges, err := genChallenges(8)
+	req.NoError(err)
+	r := executeRound(t, s, clock, challenges)
+
+	for _, ch := range challenges {
+		mproof, err := s.MembershipProof(r.ID, ch)
+		req.NoError(err)
+		req.Equal(r.execution.Statement, mproof.Root)
+
+		valid, err := merkle.ValidatePartialTree([]uint64{uint64(mproof.Index)}, [][]byte{ch}, mproof.Proof, mproof.Root, merkle.GetSha256Parent)
+		req.NoError(err)
+		req.True(valid)
+	}
+
+	info, err := s.Info()
+	req.NoError(err)
+	_, err = s.MembershipProof(r.ID, []byte("not a member"))
+	req.Equal(ErrNotMember, err)
+	_, err = s.MembershipProof(info.OpenRoundID, challenges[0])
+	req.Equal(ErrRoundMembersUnavailable, err)
+	_, err = s.MembershipProof("666", challenges[0])
+	req.Equal(ErrRoundNotFound, err)
+}
+
+func TestService_Proof(t *testing.T) {
+	req := require.New(t)
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, clock, _ := startService(t, sig, &Config{N: 10, InitialRoundDuration: time.Hour, RoundsDuration: time.Hour})
+
+	challenges, err := genChallenges(8)
+	req.NoError(err)
+	r := executeRound(t, s, clock, challenges)
+
+	proof, err := s.Proof(context.Background(), r.ID, true)
+	req.NoError(err)
+	req.Equal(uint(10), proof.N)
+	req.Equal(r.execution.NIP, proof.Proof)
+	req.Equal(r.execution.Statement, proof.Statement)
+	req.Len(proof.Members, len(challenges))
+
+	// Verify that waiting for the proof of the open round is bounded by the context.
+	info, err := s.Info()
+	req.NoError(err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = s.Proof(ctx, info.OpenRoundID, true)
+	req.Equal(context.DeadlineExceeded, err)
+	_, err = s.Proof(context.Background(), info.OpenRoundID, false)
+	req.Equal(ErrRoundOpen, err)
+}
+
+func TestService_ProofMessage(t *testing.T) {
+	req := require.New(t)
+
+	cfg := &Config{N: 10, SecurityParam: 300, InitialRoundDuration: time.Hour, RoundsDuration: time.Hour}
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, clock, broadcaster := startService(t, sig, cfg)
+
+	challenges, err := genChallenges(8)
+	req.NoError(err)
+	executeRound(t, s, clock, challenges)
+
+	var msg []byte
 	select {
-	case <-challenges[0].round.executionEndedChan:
+	case msg = <-broadcaster.receivedMessages:
+	case <-time.After(10 * time.Second):
+		req.Fail("proof message wasn't sent")
+	}
+	poetProof := PoetProofMessage{}
+	_, err = xdr.Unmarshal(bytes.NewReader(msg), &poetProof)
+	req.NoError(err)
+
+	// Verify that the proof message records the configured security param.
+	req.Equal(cfg.SecurityParam, poetProof.SecurityParam)
+	req.Len(poetProof.ProvenLeaves, int(cfg.SecurityParam))
+
+	// Verify the proof message signature.
+	req.Equal([]byte(s.PubKey), poetProof.ServicePubKey)
+	req.NoError(shared.VerifyProofMessageSignature(&poetProof))
+
+	// Verify that a tampered proof message is rejected.
+	poetProof.RoundID = "forged"
+	req.Equal(shared.ErrInvalidSignature, shared.VerifyProofMessageSignature(&poetProof))
+}
+
+func TestService_ArchivedRound(t *testing.T) {
+	req := require.New(t)
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, clock, _ := startService(t, sig, &Config{N: 10, InitialRoundDuration: time.Hour, RoundsDuration: time.Hour})
+
+	challenges, err := genChallenges(8)
+	req.NoError(err)
+	r := executeRound(t, s, clock, challenges)
+	proof, err := s.Proof(context.Background(), r.ID, false)
+	req.NoError(err)
+
+	// The round is archived before it's torn down, and its data directory is removed.
+	waitTornDown(t, r)
+	archived, err := s.ArchivedRound(r.ID)
+	req.NoError(err)
+	req.Equal(proof.Proof, archived.NIP)
+	req.Equal(proof.Statement, archived.Statement)
+	req.Equal(proof.Members, archived.Members)
+	_, err = s.roundState(r.ID)
+	req.Equal(ErrRoundNotFound, err)
+
+	// Verify that the proofs are available from the archive.
+	archivedProof, err := s.Proof(context.Background(), r.ID, false)
+	req.NoError(err)
+	req.Equal(proof, archivedProof)
+
+	mproof, err := s.MembershipProof(r.ID, challenges[0])
+	req.NoError(err)
+	req.Equal(proof.Statement, mproof.Root)
+
+	// Verify the rounds status.
+	roundInfo, err := s.Round(r.ID)
+	req.NoError(err)
+	req.Equal(RoundPhaseBroadcasted, roundInfo.Phase)
+	req.Equal(len(challenges), roundInfo.NumMembers)
+	req.Equal(roundInfo.NumLeaves, roundInfo.NextLeafID)
+	req.False(roundInfo.ExecutionStarted.IsZero())
+	req.False(roundInfo.LastBroadcastAttempt.IsZero())
+
+	info, err := s.Info()
+	req.NoError(err)
+	rounds, err := s.Rounds()
+	req.NoError(err)
+	req.Len(rounds, 2)
+	req.Equal(r.ID, rounds[0].ID)
+	req.Equal(RoundPhaseBroadcasted, rounds[0].Phase)
+	req.Equal(info.OpenRoundID, rounds[1].ID)
+	req.Equal(RoundPhaseOpen, rounds[1].Phase)
+
+	_, err = s.Round("666")
+	req.Equal(ErrRoundNotFound, err)
+}
+
+func TestService_Events(t *testing.T) {
+	req := require.New(t)
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, clock, _ := startService(t, sig, &Config{N: 10, InitialRoundDuration: time.Hour, RoundsDuration: time.Hour})
+
+	challenges, err := genChallenges(1)
+	req.NoError(err)
+	r := executeRound(t, s, clock, challenges)
+	proof, err := s.Proof(context.Background(), r.ID, false)
+	req.NoError(err)
+
+	// Verify the round lifecycle events, resumed from the first one.
+	s.events.Lock()
+	firstSeq := s.events.history[0].Seq
+	s.events.Unlock()
+	sub, err := s.SubscribeEvents(firstSeq)
+	req.NoError(err)
+	defer sub.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var roundEvents []*Event
+	for len(roundEvents) < 4 {
+		event, err := sub.Next(ctx)
+		req.NoError(err)
+		if event.RoundID == r.ID {
+			roundEvents = append(roundEvents, event)
+		}
+	}
+	req.Equal(EventRoundOpened, roundEvents[0].Type)
+	req.Equal(EventRoundExecutionStarted, roundEvents[1].Type)
+	req.Equal(EventRoundExecutionEnded, roundEvents[2].Type)
+	req.Equal(proof.Proof.Root, roundEvents[2].Payload)
+	req.Equal(EventRoundBroadcasted, roundEvents[3].Type)
+}
+
+func TestService_EpochSchedule(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	// Align the rounds to 1 hour epochs, such that epoch 5 ends in 30 minutes.
+	epochDuration := time.Hour
+	now := time.Now()
+	genesis := now.Add(-5*epochDuration - 30*time.Minute)
+
+	cfg := new(Config)
+	cfg.N = 10
+	cfg.GenesisTime = genesis.Format(time.RFC3339Nano)
+	cfg.EpochDuration = epochDuration
+	cfg.InitialRoundDuration = 10 * time.Minute
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, err := NewService(sig, cfg, tempdir)
+	req.NoError(err)
+	clock := newMockClock(now)
+	s.clock = clock
+	sub, err := s.SubscribeEvents(0)
+	req.NoError(err)
+	defer sub.Close()
+	req.NoError(s.Start(&MockBroadcaster{receivedMessages: make(chan []byte, 1)}))
+
+	info, err := s.Info()
+	req.NoError(err)
+	req.Equal("5", info.OpenRoundID)
+
+	ch, err := genChallenges(1)
+	req.NoError(err)
+	r, err := s.Submit(ch[0], "")
+	req.NoError(err)
+
+	// Verify that the round is closed at the end of its epoch, regardless of the initial round duration.
+	deadline := clock.waitTimer(t)
+	req.True(s.schedule.epochStart(6).Equal(deadline))
+	clock.advance(deadline.Sub(clock.Now()))
+
+	select {
+	case <-r.executionStartedChan:
 	case err := <-s.errChan:
 		req.Fail(err.Error())
+	case <-time.After(10 * time.Second):
+		req.Fail("round execution didn't start")
 	}
 
-	// Wait for proof message broadcast.
-	select {
-	case msg := <-proofBroadcaster.receivedMessages:
-		poetProof := PoetProofMessage{}
-		_, err := xdr.Unmarshal(bytes.NewReader(msg), &poetProof)
+	info, err = s.Info()
+	req.NoError(err)
+	req.Equal("6", info.OpenRoundID)
+
+	// Verify that an empty round is replaced at the end of its epoch.
+	emptyRound := s.openRound
+	deadline = clock.waitTimer(t)
+	req.True(s.schedule.epochStart(7).Equal(deadline))
+	clock.advance(deadline.Sub(clock.Now()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for {
+		e, err := sub.Next(ctx)
 		req.NoError(err)
-	case <-time.After(100 * time.Millisecond):
-		req.Fail("proof message wasn't sent")
+		if e.Type == EventRoundOpened && e.RoundID == "7" {
+			break
+		}
 	}
+	<-emptyRound.discardedChan
+}
+
+func TestService_EpochScheduleRecoveredRound(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	epochDuration := time.Hour
+	now := time.Now()
+	genesis := now.Add(-5*epochDuration - 30*time.Minute)
+
+	cfg := new(Config)
+	cfg.N = 10
+	cfg.GenesisTime = genesis.Format(time.RFC3339Nano)
+	cfg.EpochDuration = epochDuration
+	cfg.InitialRoundDuration = time.Hour
+
+	// Leave a round with the current epoch ID in executing state, to be recovered.
+	req.NoError(os.Mkdir(filepath.Join(tempdir, "5"), 0700))
+	req.NoError(persist(filepath.Join(tempdir, "5", roundStateFileBaseName), &roundState{
+		Opened:           now.Add(-time.Minute),
+		ExecutionStarted: now,
+		Execution:        &executionState{NumLeaves: uint64(1) << cfg.N, SecurityParam: shared.T},
+	}))
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, err := NewService(sig, cfg, tempdir)
+	req.NoError(err)
+	s.clock = newMockClock(now)
+	req.NoError(s.Start(&MockBroadcaster{receivedMessages: make(chan []byte, 1)}))
+
+	// The new open round is assigned to the next epoch, rather than reusing the recovered round ID.
+	info, err := s.Info()
+	req.NoError(err)
+	req.Equal("6", info.OpenRoundID)
+}
+
+func TestService_EpochScheduleLegacyRound(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	epochDuration := time.Hour
+	now := time.Now()
+	genesis := now.Add(-5*epochDuration - 30*time.Minute)
+
+	cfg := new(Config)
+	cfg.N = 10
+	cfg.GenesisTime = genesis.Format(time.RFC3339Nano)
+	cfg.EpochDuration = epochDuration
+	cfg.InitialRoundDuration = time.Hour
+
+	// Leave an open round with a legacy counter ID, which was opened before the rounds were epoch-aligned.
+	req.NoError(os.Mkdir(filepath.Join(tempdir, "1"), 0700))
+	req.NoError(persist(filepath.Join(tempdir, "1", roundStateFileBaseName), &roundState{
+		Opened:    now.Add(-time.Minute),
+		Execution: &executionState{NumLeaves: uint64(1) << cfg.N, SecurityParam: shared.T},
+	}))
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, err := NewService(sig, cfg, tempdir)
+	req.NoError(err)
+	clock := newMockClock(now)
+	s.clock = clock
+	req.NoError(s.Start(&MockBroadcaster{receivedMessages: make(chan []byte, 1)}))
+
+	// The round is closed at the end of the current epoch, rather than at the end of epoch 1,
+	// which already passed.
+	_, aligned, err := s.openRound.epoch()
+	req.NoError(err)
+	req.False(aligned)
+	req.True(s.schedule.epochStart(6).Equal(clock.waitTimer(t)))
+
+	info, err := s.Info()
+	req.NoError(err)
+	req.Equal("1", info.OpenRoundID)
 }
 
 func genChallenges(num int) ([][]byte, error) {
@@ -302,3 +758,474 @@ func genChallenges(num int) ([][]byte, error) {
 
 	return ch, nil
 }
+
+// GatewaysMockBroadcaster reports the broadcast results of two gateway nodes, one of which fails
+// while failing is set. The gateway nodes which acknowledged a proof are skipped.
+type GatewaysMockBroadcaster struct {
+	receivedMessages chan []byte
+	failing          int32
+}
+
+func (b *GatewaysMockBroadcaster) BroadcastProof(msg []byte, roundID string, members [][]byte) error {
+	_, err := b.BroadcastProofToGateways(msg, roundID, members, nil)
+	return err
+}
+
+func (b *GatewaysMockBroadcaster) BroadcastProofToGateways(msg []byte, roundID string, members [][]byte, acked map[string]bool) (map[string]error, error) {
+	var err error
+	results := make(map[string]error)
+	for _, gateway := range []string{"gateway-a", "gateway-b"} {
+		if acked[gateway] {
+			continue
+		}
+		if gateway == "gateway-b" && atomic.LoadInt32(&b.failing) == 1 {
+			err = errors.New("gateway unavailable")
+		}
+		results[gateway] = err
+	}
+	if err == nil {
+		b.receivedMessages <- msg
+	}
+	return results, err
+}
+
+func TestService_BroadcastOutbox(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	cfg := new(Config)
+	cfg.N = 10
+	cfg.InitialRoundDuration = 100 * time.Millisecond
+	cfg.RoundsDuration = time.Hour
+	cfg.BroadcastNumRetries = 1
+	cfg.BroadcastRetriesInterval = 50 * time.Millisecond
+
+	broadcaster := &GatewaysMockBroadcaster{receivedMessages: make(chan []byte, 1), failing: 1}
+
+	sig := signal.NewSignal()
+	s, err := NewService(sig, cfg, tempdir)
+	req.NoError(err)
+	req.NoError(s.Start(broadcaster))
+
+	ch, err := genChallenges(1)
+	req.NoError(err)
+	r, err := s.Submit(ch[0], "")
+	req.NoError(err)
+	roundID := r.ID
+
+	// Wait for the broadcast retries to run out.
+	waitExhausted := func(s *Service) *RoundInfo {
+		for i := 0; i < 100; i++ {
+			info, err := s.Round(roundID)
+			if err == nil && info.Outbox != nil && info.Outbox.Exhausted {
+				return info
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		req.Fail("broadcast retries didn't run out")
+		return nil
+	}
+	info := waitExhausted(s)
+	req.Equal(RoundPhaseExecuted, info.Phase)
+	req.Equal(uint(2), info.Outbox.NumAttempts)
+	req.Equal([]string{"gateway-a"}, info.Outbox.Acked)
+
+	// The retry targets only the gateway node which failed.
+	req.Len(info.Outbox.Attempts, 3)
+	for i, attempt := range info.Outbox.Attempts {
+		if i == 0 {
+			req.Equal("gateway-a", attempt.Gateway)
+			req.Empty(attempt.Error)
+		} else {
+			req.Equal("gateway-b", attempt.Gateway)
+			req.Equal("gateway unavailable", attempt.Error)
+		}
+	}
+
+	// Restart the service, once its rounds released their databases, and verify that the outbox is recovered.
+	openRound := s.openRound
+	sig.RequestShutdown()
+	waitTornDown(t, r)
+	waitTornDown(t, openRound)
+
+	sig = signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, err = NewService(sig, cfg, tempdir)
+	req.NoError(err)
+	req.NoError(s.Start(broadcaster))
+
+	info = waitExhausted(s)
+	req.Len(info.Outbox.Attempts, 3)
+	req.Equal([]string{"gateway-a"}, info.Outbox.Acked)
+
+	// Force a re-broadcast.
+	req.Equal(ErrRoundNotBroadcasting, s.Rebroadcast("unknown"))
+	atomic.StoreInt32(&broadcaster.failing, 0)
+	req.NoError(s.Rebroadcast(roundID))
+
+	select {
+	case <-broadcaster.receivedMessages:
+	case <-time.After(time.Second):
+		req.Fail("proof message wasn't sent")
+	}
+
+	// Wait for the round to be archived.
+	for i := 0; i < 20; i++ {
+		if info, err = s.Round(roundID); err == nil && info.Phase == RoundPhaseBroadcasted {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	req.NoError(err)
+	req.Equal(RoundPhaseBroadcasted, info.Phase)
+}
+
+func TestService_Sinks(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+	sinkdir, _ := ioutil.TempDir("", "poet-test")
+
+	cfg := new(Config)
+	cfg.N = 10
+	cfg.InitialRoundDuration = 100 * time.Millisecond
+	cfg.RoundsDuration = time.Hour
+	cfg.Sinks = []string{"dir:" + sinkdir}
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, err := NewService(sig, cfg, tempdir)
+	req.NoError(err)
+	req.True(s.Started())
+
+	// The sinks are kept when the gateway nodes are updated.
+	gateways := &MockBroadcaster{receivedMessages: make(chan []byte, 1)}
+	req.NoError(s.SetBroadcaster(&MockBroadcaster{receivedMessages: make(chan []byte, 1)}))
+	req.NoError(s.SetBroadcaster(gateways))
+
+	ch, err := genChallenges(1)
+	req.NoError(err)
+	r, err := s.Submit(ch[0], "")
+	req.NoError(err)
+
+	// Wait for the proof to be written to the sink directory.
+	select {
+	case <-r.broadcastedChan:
+	case <-time.After(5 * time.Second):
+		req.Fail("proof wasn't broadcast")
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(sinkdir, r.ID+".bin"))
+	req.NoError(err)
+	proofMsg := PoetProofMessage{}
+	_, err = xdr.Unmarshal(bytes.NewReader(data), &proofMsg)
+	req.NoError(err)
+	req.Equal(r.ID, proofMsg.RoundID)
+	req.NoError(shared.VerifyProofMessageSignature(&proofMsg))
+	req.Equal(data, <-gateways.receivedMessages)
+}
+
+func TestService_ArchiveFailure(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	cfg := new(Config)
+	cfg.N = 10
+	cfg.InitialRoundDuration = 100 * time.Millisecond
+	cfg.RoundsDuration = time.Hour
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, err := NewService(sig, cfg, tempdir)
+	req.NoError(err)
+	req.NoError(s.Start(&MockBroadcaster{receivedMessages: make(chan []byte, 1)}))
+
+	// Fail the archiving, by pointing the archive to a missing directory.
+	s.archive.dir = filepath.Join(tempdir, "missing", "archive")
+
+	ch, err := genChallenges(1)
+	req.NoError(err)
+	r, err := s.Submit(ch[0], "")
+	req.NoError(err)
+
+	select {
+	case err := <-s.errChan:
+		req.Contains(err.Error(), "archiving failure")
+	case <-time.After(5 * time.Second):
+		req.Fail("archiving failure wasn't reported")
+	}
+	<-r.broadcastedChan
+
+	// The round is torn down, but its data directory, which holds the proof, is kept,
+	// and the round is marked as broadcast.
+	waitTornDown(t, r)
+	state, err := r.state()
+	req.NoError(err)
+	req.True(state.isExecuted())
+	outbox, err := r.loadOutbox()
+	req.NoError(err)
+	req.True(outbox.Broadcasted)
+}
+
+func TestService_RecoverBroadcastedRound(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	cfg := new(Config)
+	cfg.N = 10
+	cfg.InitialRoundDuration = time.Hour
+
+	// Leave an executed round which was broadcast, but which archiving failed.
+	datadir := filepath.Join(tempdir, "0")
+	req.NoError(os.Mkdir(datadir, 0700))
+	req.NoError(persist(filepath.Join(datadir, roundStateFileBaseName), &roundState{
+		Opened:           time.Now().Add(-time.Second),
+		ExecutionStarted: time.Now(),
+		Execution: &executionState{
+			NumLeaves:     uint64(1) << cfg.N,
+			SecurityParam: shared.T,
+			Statement:     []byte("statement"),
+			NIP:           &shared.MerkleProof{Root: []byte("root")},
+		},
+	}))
+	req.NoError(persist(filepath.Join(datadir, outboxFileBaseName), &Outbox{NumAttempts: 1, Broadcasted: true}))
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, err := NewService(sig, cfg, tempdir)
+	req.NoError(err)
+	sub, err := s.SubscribeEvents(0)
+	req.NoError(err)
+	defer sub.Close()
+	broadcaster := &MockBroadcaster{receivedMessages: make(chan []byte, 1)}
+	req.NoError(s.Start(broadcaster))
+
+	info, err := s.Round("0")
+	req.NoError(err)
+	req.Equal(RoundPhaseBroadcasted, info.Phase)
+
+	// The round is archived without being broadcast again.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for {
+		e, err := sub.Next(ctx)
+		req.NoError(err)
+		if e.RoundID == "0" && e.Type == EventRoundBroadcasted {
+			break
+		}
+	}
+	req.Len(broadcaster.receivedMessages, 0)
+
+	_, err = s.ArchivedRound("0")
+	req.NoError(err)
+
+	// The proof reports the N which the round was executed with, rather than the configured one.
+	req.NoError(s.archive.put(&ArchivedRound{ID: "100", NumLeaves: uint64(1) << 8, SecurityParam: shared.T}))
+	proof, err := s.Proof(context.Background(), "100", false)
+	req.NoError(err)
+	req.Equal(uint(8), proof.N)
+}
+
+func TestService_SubmitLimits(t *testing.T) {
+	req := require.New(t)
+
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	cfg := new(Config)
+	cfg.N = 10
+	cfg.InitialRoundDuration = time.Hour
+	cfg.RoundsDuration = time.Hour
+	cfg.MaxChallengeSize = 32
+	cfg.MaxRoundMembers = 2
+	cfg.PeerSubmitRate = 0.001
+	cfg.PeerSubmitBurst = 3
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	s, err := NewService(sig, cfg, tempdir)
+	req.NoError(err)
+	req.NoError(s.Start(&MockBroadcaster{receivedMessages: make(chan []byte, 1)}))
+
+	var limitErr *SubmitLimitError
+	_, err = s.Submit(make([]byte, 33), "peer")
+	req.True(errors.As(err, &limitErr))
+	req.Equal(LimitChallengeSize, limitErr.Limit)
+	req.False(limitErr.RetryNextRound)
+
+	ch, err := genChallenges(3)
+	req.NoError(err)
+	for i := 0; i < 2; i++ {
+		_, err = s.Submit(ch[i], "peer")
+		req.NoError(err)
+	}
+
+	// A full round rejects new challenges, but accepts a resubmitted one.
+	_, err = s.Submit(ch[2], "peer")
+	req.True(errors.As(err, &limitErr))
+	req.Equal(LimitRoundMembers, limitErr.Limit)
+	req.True(limitErr.RetryNextRound)
+
+	// The rejected submission didn't take a rate limit token.
+	_, err = s.Submit(ch[0], "peer")
+	req.NoError(err)
+	req.Equal(2, s.openRound.numChallenges())
+
+	_, err = s.Submit(ch[1], "peer")
+	req.True(errors.As(err, &limitErr))
+	req.Equal(LimitPeerRate, limitErr.Limit)
+}
+
+func TestService_SubmitSigned(t *testing.T) {
+	req := require.New(t)
+
+	_, priv, err := ed25519.GenerateKey(nil)
+	req.NoError(err)
+	_, otherPriv, err := ed25519.GenerateKey(nil)
+	req.NoError(err)
+	_, deniedPriv, err := ed25519.GenerateKey(nil)
+	req.NoError(err)
+
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+	newService := func(cfg *Config) *Service {
+		tempdir, _ := ioutil.TempDir("", "poet-test")
+		cfg.N = 10
+		cfg.InitialRoundDuration = time.Hour
+		cfg.RoundsDuration = time.Hour
+
+		s, err := NewService(sig, cfg, tempdir)
+		req.NoError(err)
+		req.NoError(s.Start(&MockBroadcaster{receivedMessages: make(chan []byte, 1)}))
+		return s
+	}
+
+	ch, err := genChallenges(3)
+	req.NoError(err)
+
+	s := newService(&Config{SubmitDenylist: []string{hex.EncodeToString(deniedPriv.Public().(ed25519.PublicKey))}})
+	roundID := s.openRound.ID
+
+	// A denylist requires signatures, since the denied identities could otherwise submit unsigned.
+	_, err = s.Submit(ch[0], "peer")
+	req.True(errors.Is(err, ErrSubmissionSignatureRequired))
+
+	r, err := s.SubmitSigned(ch[1], "peer", SignSubmission(priv, roundID, ch[1]))
+	req.NoError(err)
+	req.Equal(roundID, r.ID)
+
+	// An identity may resubmit its challenge, but not submit a different one.
+	_, err = s.SubmitSigned(ch[1], "peer", SignSubmission(priv, roundID, ch[1]))
+	req.NoError(err)
+	_, err = s.SubmitSigned(ch[2], "peer", SignSubmission(priv, roundID, ch[2]))
+	req.True(errors.Is(err, ErrIdentityAlreadySubmitted))
+	req.Equal(1, s.openRound.numChallenges())
+
+	badSig := SignSubmission(otherPriv, roundID, ch[2])
+	badSig.Signature[0] ^= 1
+	_, err = s.SubmitSigned(ch[2], "peer", badSig)
+	req.True(errors.Is(err, ErrInvalidSubmissionSignature))
+
+	// The signature must be of the submitted challenge.
+	_, err = s.SubmitSigned(ch[2], "peer", SignSubmission(otherPriv, roundID, ch[0]))
+	req.True(errors.Is(err, ErrInvalidSubmissionSignature))
+
+	_, err = s.SubmitSigned(ch[2], "peer", SignSubmission(otherPriv, "not-open", ch[2]))
+	req.True(errors.Is(err, ErrSubmissionRoundNotOpen))
+
+	_, err = s.SubmitSigned(ch[2], "peer", SignSubmission(deniedPriv, roundID, ch[2]))
+	req.True(errors.Is(err, ErrIdentityNotAllowed))
+
+	_, err = s.SubmitSigned(ch[2], "peer", SignSubmission(otherPriv, roundID, ch[2]))
+	req.NoError(err)
+	req.Equal(2, s.openRound.numChallenges())
+
+	// An allowlist admits only the listed identities, and requires signatures.
+	s = newService(&Config{SubmitAllowlist: []string{hex.EncodeToString(priv.Public().(ed25519.PublicKey))}})
+	roundID = s.openRound.ID
+
+	_, err = s.Submit(ch[0], "peer")
+	req.True(errors.Is(err, ErrSubmissionSignatureRequired))
+	_, err = s.SubmitSigned(ch[0], "peer", SignSubmission(otherPriv, roundID, ch[0]))
+	req.True(errors.Is(err, ErrIdentityNotAllowed))
+	_, err = s.SubmitSigned(ch[0], "peer", SignSubmission(priv, roundID, ch[0]))
+	req.NoError(err)
+
+	// Unsigned submissions are accepted unless signatures are required.
+	s = newService(&Config{})
+	_, err = s.Submit(ch[0], "peer")
+	req.NoError(err)
+
+	s = newService(&Config{RequireSignedSubmissions: true})
+	_, err = s.Submit(ch[0], "peer")
+	req.True(errors.Is(err, ErrSubmissionSignatureRequired))
+	_, err = s.SubmitSigned(ch[0], "peer", SignSubmission(otherPriv, s.openRound.ID, ch[0]))
+	req.NoError(err)
+
+	// An identity whose challenge was rejected isn't marked as used, and may submit another challenge.
+	s = newService(&Config{MaxRoundMembers: 1})
+	_, err = s.Submit(ch[0], "peer")
+	req.NoError(err)
+	_, err = s.SubmitSigned(ch[1], "peer", SignSubmission(priv, s.openRound.ID, ch[1]))
+	var limitErr *SubmitLimitError
+	req.True(errors.As(err, &limitErr))
+	req.Equal(LimitRoundMembers, limitErr.Limit)
+	_, err = s.SubmitSigned(ch[0], "peer", SignSubmission(priv, s.openRound.ID, ch[0]))
+	req.NoError(err)
+}
+
+func TestNewIdentityFilter(t *testing.T) {
+	req := require.New(t)
+
+	key := hex.EncodeToString(make([]byte, ed25519.PublicKeySize))
+	_, err := newIdentityFilter([]string{key}, []string{key})
+	req.Error(err)
+	_, err = newIdentityFilter([]string{"zz"}, nil)
+	req.Error(err)
+	_, err = newIdentityFilter(nil, []string{key[2:]})
+	req.Error(err)
+
+	f, err := newIdentityFilter(nil, nil)
+	req.NoError(err)
+	req.False(f.requiresSignature())
+	req.True(f.admits(make([]byte, ed25519.PublicKeySize)))
+}
+
+func TestNewService_SecurityParam(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+
+	// The default security param exceeds 2^7 leaves.
+	_, err := NewService(sig, &Config{N: 7}, tempdir)
+	req.EqualError(err, fmt.Sprintf("security param (%d) must not exceed the number of leaves (128)", shared.T))
+
+	_, err = NewService(sig, &Config{N: 10, NumLeaves: 100, SecurityParam: 101}, tempdir)
+	req.Error(err)
+
+	_, err = NewService(sig, &Config{N: 7, SecurityParam: 128}, tempdir)
+	req.NoError(err)
+}
+
+func TestNewService_GatewayTLS(t *testing.T) {
+	req := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+	sinkdir, _ := ioutil.TempDir("", "poet-test")
+	sig := signal.NewSignal()
+	defer sig.RequestShutdown()
+
+	// TLS configs must be of either a gateway node or a gateway sink.
+	cfg := &Config{N: 10, Sinks: []string{"dir:" + sinkdir}, GatewayTLS: []string{"localhost:9092"}}
+	_, err := NewService(sig, cfg, tempdir)
+	req.EqualError(err, "TLS config of unknown gateway address \"localhost:9092\"")
+
+	gatewaysTLS, sinksTLS, err := parseTLSConfigs(&Config{
+		GatewayAddresses: []string{"localhost:9091"},
+		Sinks:            []string{"gateway:localhost:9092"},
+		GatewayTLS:       []string{"localhost:9091,server-name=a", "localhost:9092,server-name=b"},
+	})
+	req.NoError(err)
+	req.Len(gatewaysTLS, 1)
+	req.Equal("a", gatewaysTLS["localhost:9091"].ServerName)
+	req.Len(sinksTLS, 1)
+	req.Equal("b", sinksTLS["localhost:9092"].ServerName)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/ed25519"
+)
+
+// submissionDomain separates the signed submission messages from other messages which the node identities sign.
+const submissionDomain = "poet-submission"
+
+var (
+	ErrSubmissionSignatureRequired = errors.New("submission must be signed by the node identity")
+	ErrInvalidSubmissionSignature  = errors.New("invalid submission signature")
+	ErrSubmissionRoundNotOpen      = errors.New("signed round is not open")
+	ErrIdentityNotAllowed          = errors.New("node identity is not allowed to submit")
+	ErrIdentityAlreadySubmitted    = errors.New("node identity already submitted a different challenge to the round")
+)
+
+// SubmissionSignature is the signature of a submission by the submitting node identity,
+// over the challenge and the round which it's submitted to.
+type SubmissionSignature struct {
+	PubKey    ed25519.PublicKey
+	RoundID   string
+	Signature []byte
+}
+
+// SubmissionMessage returns the message which is signed by a node identity, to submit a challenge to a given round.
+func SubmissionMessage(roundID string, challenge []byte) []byte {
+	msg := make([]byte, 0, len(submissionDomain)+4+len(roundID)+len(challenge))
+	msg = append(msg, submissionDomain...)
+	msg = append(msg, make([]byte, 4)...)
+	binary.BigEndian.PutUint32(msg[len(submissionDomain):], uint32(len(roundID)))
+	msg = append(msg, roundID...)
+	return append(msg, challenge...)
+}
+
+// SignSubmission signs the submission of a challenge to a given round by a node identity.
+func SignSubmission(privKey ed25519.PrivateKey, roundID string, challenge []byte) *SubmissionSignature {
+	return &SubmissionSignature{
+		PubKey:    privKey.Public().(ed25519.PublicKey),
+		RoundID:   roundID,
+		Signature: ed25519.Sign(privKey, SubmissionMessage(roundID, challenge)),
+	}
+}
+
+// identityFilter admits the node identities by an allowlist or a denylist.
+type identityFilter struct {
+	allowed map[string]bool // allowed is nil if there is no allowlist.
+	denied  map[string]bool
+}
+
+// newIdentityFilter parses the hex-encoded public keys of the allowlist or the denylist. Only one of them may be set.
+func newIdentityFilter(allowlist, denylist []string) (*identityFilter, error) {
+	if len(allowlist) > 0 && len(denylist) > 0 {
+		return nil, errors.New("submit allowlist and denylist can't be set together")
+	}
+
+	parse := func(keys []string) (map[string]bool, error) {
+		set := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			pubKey, err := hex.DecodeString(key)
+			if err != nil || len(pubKey) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("invalid node identity %q: expected a hex-encoded %d bytes public key", key, ed25519.PublicKeySize)
+			}
+			set[string(pubKey)] = true
+		}
+		return set, nil
+	}
+
+	f := new(identityFilter)
+	var err error
+	if len(allowlist) > 0 {
+		if f.allowed, err = parse(allowlist); err != nil {
+			return nil, err
+		}
+	}
+	if f.denied, err = parse(denylist); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// requiresSignature returns whether unsigned submissions are rejected, since only the allowed identities may submit,
+// or since the denied identities could otherwise submit unsigned.
+func (f *identityFilter) requiresSignature() bool {
+	return f.allowed != nil || len(f.denied) > 0
+}
+
+func (f *identityFilter) admits(pubKey ed25519.PublicKey) bool {
+	if f.allowed != nil {
+		return f.allowed[string(pubKey)]
+	}
+	return !f.denied[string(pubKey)]
+}
+
+// verifySubmission verifies the signature of a submission to a given round, and that its identity is admitted.
+// sig is nil if the submission is unsigned.
+func (s *Service) verifySubmission(r *round, challenge []byte, sig *SubmissionSignature) error {
+	if sig == nil {
+		if s.cfg.RequireSignedSubmissions || s.identities.requiresSignature() {
+			return ErrSubmissionSignatureRequired
+		}
+		return nil
+	}
+
+	if sig.RoundID != r.ID {
+		return fmt.Errorf("%w: round %v (open round: %v)", ErrSubmissionRoundNotOpen, sig.RoundID, r.ID)
+	}
+	if len(sig.PubKey) != ed25519.PublicKeySize || !ed25519.Verify(sig.PubKey, SubmissionMessage(sig.RoundID, challenge), sig.Signature) {
+		return ErrInvalidSubmissionSignature
+	}
+	if !s.identities.admits(sig.PubKey) {
+		return ErrIdentityNotAllowed
+	}
+	return nil
+}
@@ -16,13 +16,18 @@ func persist(filename string, v interface{}) error {
 		return fmt.Errorf("serialization failure: %v", err)
 	}
 
-	err = ioutil.WriteFile(filename, w.Bytes(), shared.OwnerReadWrite)
+	// Write to a temporary file and rename it, so that concurrent readers never observe a partially written file.
+	tmpFilename := filename + ".tmp"
+	err = ioutil.WriteFile(tmpFilename, w.Bytes(), shared.OwnerReadWrite)
 	if err != nil {
 		return fmt.Errorf("write to disk failure: %v", err)
 	}
 
-	return nil
+	if err := os.Rename(tmpFilename, filename); err != nil {
+		return fmt.Errorf("write to disk failure: %v", err)
+	}
 
+	return nil
 }
 
 func load(filename string, v interface{}) error {
@@ -0,0 +1,87 @@
+package shared
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/nullstyle/go-xdr/xdr3"
+	"golang.org/x/crypto/ed25519"
+)
+
+var ErrInvalidSignature = errors.New("invalid proof message signature")
+
+type GossipPoetProof struct {
+	// The actual proof.
+	MerkleProof
+
+	// Members is the ordered list of miners challenges which are included
+	// in the proof (by using the list hash digest as the proof generation input (the statement)).
+	Members [][]byte
+
+	// NumLeaves is the width of the proof-generation tree.
+	NumLeaves uint64
+
+	// SecurityParam is the number of proven leaves, which the verifiers should check.
+	SecurityParam uint32
+}
+
+type PoetProofMessage struct {
+	GossipPoetProof
+	ServicePubKey []byte
+	RoundID       string
+	Signature     []byte
+}
+
+// signedProofMessage is the canonical encoding of the PoetProofMessage fields which are covered by its signature.
+type signedProofMessage struct {
+	GossipPoetProof
+	ServicePubKey []byte
+	RoundID       string
+}
+
+// proofMessageSigningPayload returns the canonical encoding of a proof message which is signed by the service,
+// consisting of the XDR-serialized GossipPoetProof, ServicePubKey and RoundID.
+func proofMessageSigningPayload(msg *PoetProofMessage) ([]byte, error) {
+	payload := signedProofMessage{
+		GossipPoetProof: msg.GossipPoetProof,
+		ServicePubKey:   msg.ServicePubKey,
+		RoundID:         msg.RoundID,
+	}
+
+	var buf bytes.Buffer
+	if _, err := xdr.Marshal(&buf, payload); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SignProofMessage sets the signature of a proof message, which was created by the service of a given private key.
+func SignProofMessage(privKey ed25519.PrivateKey, msg *PoetProofMessage) error {
+	payload, err := proofMessageSigningPayload(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal proof message signing payload: %v", err)
+	}
+	msg.Signature = ed25519.Sign(privKey, payload)
+
+	return nil
+}
+
+// VerifyProofMessageSignature verifies that a proof message was signed by the service public key it declares.
+// Callers should additionally compare ServicePubKey with the identity of the PoET service they expect the proof from.
+func VerifyProofMessageSignature(msg *PoetProofMessage) error {
+	if len(msg.ServicePubKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid service public key size: %d", len(msg.ServicePubKey))
+	}
+
+	payload, err := proofMessageSigningPayload(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal proof message signing payload: %v", err)
+	}
+
+	if !ed25519.Verify(msg.ServicePubKey, payload, msg.Signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
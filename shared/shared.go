@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/spacemeshos/merkle-tree"
 	"github.com/spacemeshos/sha256-simd"
+	"math"
 	"os"
 	"time"
 )
@@ -13,26 +14,37 @@ import (
 const (
 	// T is the security param which determines the number of leaves
 	// to be included in a non-interactive proof.
-	T uint8 = 150
+	T uint32 = 150
 
 	// OwnerReadWrite is a standard owner read / write file permission.
 	OwnerReadWrite = os.FileMode(0600)
 )
 
 // FiatShamir generates a set of indices to include in a non-interactive proof.
-func FiatShamir(challenge []byte, spaceSize uint64, securityParam uint8) map[uint64]bool {
+func FiatShamir(challenge []byte, spaceSize uint64, securityParam uint32) map[uint64]bool {
 	if uint64(securityParam) > spaceSize {
-		securityParam = uint8(spaceSize)
+		securityParam = uint32(spaceSize)
 	}
 	ret := make(map[uint64]bool, securityParam)
-	for i := uint8(0); len(ret) < int(securityParam); i++ {
-		result := sha256.Sum256(append(challenge, i))
+	for i := uint32(0); len(ret) < int(securityParam); i++ {
+		result := sha256.Sum256(append(challenge, fiatShamirCounter(i)...))
 		id := binary.BigEndian.Uint64(result[:8]) % spaceSize
 		ret[id] = true
 	}
 	return ret
 }
 
+// fiatShamirCounter encodes the FiatShamir hash input counter. Counters below 256 are encoded as a single byte,
+// so that the indices of security params which fit in a byte remain unchanged.
+func fiatShamirCounter(i uint32) []byte {
+	if i <= math.MaxUint8 {
+		return []byte{byte(i)}
+	}
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, i)
+	return b
+}
+
 // MakeLabelFunc returns a function which generates a PoET DAG label by concatenating a representation
 // of the labelID with the list of left siblings and then hashing the result using the provided hash function.
 //
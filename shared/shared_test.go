@@ -3,6 +3,7 @@ package shared
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"github.com/spacemeshos/sha256-simd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"math"
@@ -14,7 +15,7 @@ func TestFiatShamir(t *testing.T) {
 
 	occurrences := make(map[uint64]uint)
 	rounds := 5000
-	indicesPerRound := uint8(255)
+	indicesPerRound := uint32(255)
 	spaceSize := uint64(30000000000)
 	buckets := 10
 	bucketDivisor := spaceSize / uint64(buckets)
@@ -49,6 +50,22 @@ func TestFiatShamirReturnsCorrectNumOfIndices(t *testing.T) {
 
 	// if the space is big enough, return the requested index count
 	require.Len(t, FiatShamir(challenge, 20, 15), 15)
+
+	// supports security params which don't fit in a byte
+	require.Len(t, FiatShamir(challenge, 1<<20, 1000), 1000)
+}
+
+func TestFiatShamirSingleByteCounter(t *testing.T) {
+	challenge := []byte("challenge")
+	spaceSize := uint64(1 << 20)
+
+	// Security params which fit in a byte derive the indices from single-byte counters.
+	expected := make(map[uint64]bool)
+	for i := 0; len(expected) < int(T); i++ {
+		result := sha256.Sum256(append(challenge, byte(i)))
+		expected[binary.BigEndian.Uint64(result[:8])%spaceSize] = true
+	}
+	require.Equal(t, expected, FiatShamir(challenge, spaceSize, T))
 }
 
 func TestMakeLabel(t *testing.T) {
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/spacemeshos/smutil/log"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// certReloader holds the TLS certificate of the RPC and REST listeners, and the CA certificates which
+// their clients certificates are verified against, and reloads them when their files are rotated.
+type certReloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string // clientCAFile is empty if clients certificates aren't verified.
+
+	sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  []time.Time
+}
+
+// newCertReloader loads the listeners certificate and, if clientCAFile is specified, the CA certificates
+// which clients certificates are verified against.
+func newCertReloader(certFile, keyFile, clientCAFile string) (*certReloader, error) {
+	r := &certReloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+	return files
+}
+
+func (r *certReloader) fileModTimes() ([]time.Time, error) {
+	var modTimes []time.Time
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+	return modTimes, nil
+}
+
+// reload loads the certificate files. If any of them fails to load, the previously loaded ones are kept.
+func (r *certReloader) reload() error {
+	modTimes, err := r.fileModTimes()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		pem, err := ioutil.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read TLS client CA certificates: %v", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no TLS client CA certificates found in %v", r.clientCAFile)
+		}
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	return nil
+}
+
+// changed returns whether any of the certificate files was modified since it was loaded.
+func (r *certReloader) changed() bool {
+	modTimes, err := r.fileModTimes()
+	if err != nil {
+		// A file which is being rotated may be missing momentarily.
+		return false
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+	for i, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// watch checks the certificate files for modifications at a given interval, and reloads them, until stop is closed.
+func (r *certReloader) watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+
+		if !r.changed() {
+			continue
+		}
+		if err := r.reload(); err != nil {
+			log.Error("Failed to reload TLS certificates, keeping the previous ones: %v", err)
+			continue
+		}
+		log.Info("TLS certificates reloaded")
+	}
+}
+
+func (r *certReloader) certificate() *tls.Certificate {
+	r.RLock()
+	defer r.RUnlock()
+	return r.cert
+}
+
+// serverConfig returns the TLS config of the listeners. If clients certificates are verified, they are
+// verified against the CA certificates which are loaded at the time of each handshake.
+func (r *certReloader) serverConfig() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.certificate(), nil
+		},
+	}
+	if r.clientCAFile != "" {
+		// The certificate chain is verified by verifyClientCert rather than by the TLS stack,
+		// so that reloaded CA certificates take effect.
+		cfg.ClientAuth = tls.RequireAnyClientCert
+		cfg.VerifyPeerCertificate = r.verifyClientCert
+	}
+	return cfg
+}
+
+func (r *certReloader) verifyClientCert(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("client certificate is missing")
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse client certificate: %v", err)
+		}
+		certs[i] = cert
+	}
+
+	r.RLock()
+	opts := x509.VerifyOptions{
+		Roots:         r.clientCAs,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	r.RUnlock()
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(opts)
+	return err
+}
+
+// clientConfig returns the TLS config of the REST proxy connection to the RPC listener. The listener is
+// authenticated by its current certificate, and the proxy presents the same certificate to it,
+// in case clients certificates are verified.
+func (r *certReloader) clientConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// The listener certificate is pinned by VerifyPeerCertificate instead, since it may not be valid for the dialed address.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			cert := r.certificate()
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], cert.Certificate[0]) {
+				return errors.New("RPC listener certificate doesn't match the configured certificate")
+			}
+			return nil
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.certificate(), nil
+		},
+	}
+}
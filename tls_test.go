@@ -0,0 +1,125 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCert writes a certificate and its private key to given files, and returns them.
+// The certificate is signed by a given CA, or is a self-signed CA certificate if ca is nil.
+func writeTestCert(t *testing.T, certFile, keyFile string, ca *tls.Certificate) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "poet"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	var parent *x509.Certificate
+	var signer interface{}
+	if ca == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, signer = template, key
+	} else {
+		parent, signer = ca.Leaf, ca.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+	cert.Leaf, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &cert
+}
+
+// handshake performs a TLS handshake between a given client and server configs.
+func handshake(clientCfg, serverCfg *tls.Config) error {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- tls.Server(serverConn, serverCfg).Handshake()
+		serverConn.Close()
+	}()
+	clientErr := tls.Client(clientConn, clientCfg).Handshake()
+	clientConn.Close()
+	if err := <-errs; err != nil {
+		return err
+	}
+	return clientErr
+}
+
+func TestCertReloader(t *testing.T) {
+	req := require.New(t)
+
+	tempdir, err := ioutil.TempDir("", "poet-test")
+	req.NoError(err)
+	defer os.RemoveAll(tempdir)
+
+	caFile := filepath.Join(tempdir, "ca.pem")
+	certFile := filepath.Join(tempdir, "cert.pem")
+	keyFile := filepath.Join(tempdir, "key.pem")
+	ca := writeTestCert(t, caFile, filepath.Join(tempdir, "ca.key"), nil)
+	writeTestCert(t, certFile, keyFile, ca)
+
+	certs, err := newCertReloader(certFile, keyFile, caFile)
+	req.NoError(err)
+	req.False(certs.changed())
+
+	// The REST proxy is accepted by the RPC listener, as it presents the listener certificate.
+	req.NoError(handshake(certs.clientConfig(), certs.serverConfig()))
+
+	// A client certificate which isn't signed by the client CA is rejected.
+	otherCA := writeTestCert(t, filepath.Join(tempdir, "other-ca.pem"), filepath.Join(tempdir, "other-ca.key"), nil)
+	clientCert := writeTestCert(t, filepath.Join(tempdir, "client.pem"), filepath.Join(tempdir, "client.key"), otherCA)
+	clientCfg := certs.clientConfig()
+	clientCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return clientCert, nil
+	}
+	req.Error(handshake(clientCfg, certs.serverConfig()))
+
+	// A rotated certificate is reloaded, and the REST proxy pins the new one.
+	prevCert := certs.certificate()
+	prevClientCfg := certs.clientConfig()
+	rotated := writeTestCert(t, certFile, keyFile, ca)
+	future := time.Now().Add(time.Minute)
+	req.NoError(os.Chtimes(certFile, future, future))
+	req.True(certs.changed())
+	req.NoError(certs.reload())
+	req.False(certs.changed())
+	req.Equal(rotated.Certificate, certs.certificate().Certificate)
+	req.NotEqual(prevCert.Certificate, certs.certificate().Certificate)
+	req.NoError(handshake(prevClientCfg, certs.serverConfig()))
+
+	// A failed reload keeps the previous certificate.
+	req.NoError(ioutil.WriteFile(keyFile, []byte("not a key"), 0600))
+	req.Error(certs.reload())
+	req.Equal(rotated.Certificate, certs.certificate().Certificate)
+}
@@ -0,0 +1,62 @@
+package verifier
+
+import (
+	"context"
+	"github.com/spacemeshos/poet/shared"
+	"runtime"
+	"sync"
+)
+
+// BatchItem is a proof to validate in a batch, along with its validation params.
+// Its hash functions aren't required to be thread-safe, hence they must not be shared with other items.
+type BatchItem struct {
+	Proof          shared.MerkleProof
+	LabelHashFunc  func(data []byte) []byte
+	MerkleHashFunc func(lChild, rChild []byte) []byte
+	NumLeaves      uint64
+	SecurityParam  uint32
+}
+
+// ValidateBatch validates a batch of proofs concurrently, using a pool of numWorkers workers (or one per CPU, if
+// numWorkers isn't positive). It returns the validation result of each proof, in the batch order, so that a nil
+// result indicates a valid proof. An invalid proof doesn't stop the validation of the others. Once ctx is done,
+// the remaining proofs aren't validated, and their result is ctx.Err().
+func ValidateBatch(ctx context.Context, items []*BatchItem, numWorkers int) []error {
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+	if numWorkers > len(items) {
+		numWorkers = len(items)
+	}
+
+	results := make([]error, len(items))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				item := items[i]
+				results[i] = Validate(item.Proof, item.LabelHashFunc, item.MerkleHashFunc, item.NumLeaves, item.SecurityParam)
+			}
+		}()
+	}
+
+	for i := range items {
+		// A done ctx is checked first, since the select below picks randomly if a worker is ready as well.
+		if err := ctx.Err(); err != nil {
+			results[i] = err
+			continue
+		}
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			results[i] = ctx.Err()
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,58 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"github.com/spacemeshos/poet/hash"
+	"github.com/spacemeshos/poet/prover"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"testing"
+)
+
+func TestValidateBatch(t *testing.T) {
+	r := require.New(t)
+
+	numLeaves := uint64(1 << 10)
+	securityParam := uint32(8)
+	var items []*BatchItem
+	for i := 0; i < 10; i++ {
+		tempdir, _ := ioutil.TempDir("", "poet-test")
+		challenge := []byte(fmt.Sprintf("challenge %d", i))
+		merkleProof, err := prover.GenerateProofWithoutPersistency(tempdir, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam, prover.LowestMerkleMinMemoryLayer)
+		r.NoError(err)
+
+		items = append(items, &BatchItem{
+			Proof:          *merkleProof,
+			LabelHashFunc:  hash.GenLabelHashFunc(challenge),
+			MerkleHashFunc: hash.GenMerkleHashFunc(challenge),
+			NumLeaves:      numLeaves,
+			SecurityParam:  securityParam,
+		})
+	}
+
+	// Invalidate some of the proofs.
+	items[3].LabelHashFunc = BadLabelHashFunc
+	items[7].SecurityParam = securityParam + 1
+
+	for _, numWorkers := range []int{0, 1, 3, 100} {
+		results := ValidateBatch(context.Background(), items, numWorkers)
+		r.Len(results, len(items))
+		for i, err := range results {
+			if i == 3 || i == 7 {
+				r.Error(err, "item %d, workers: %d", i, numWorkers)
+			} else {
+				r.NoError(err, "item %d, workers: %d", i, numWorkers)
+			}
+		}
+	}
+
+	r.Empty(ValidateBatch(context.Background(), nil, 0))
+
+	// The proofs aren't validated once the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range ValidateBatch(ctx, items, 1) {
+		r.Equal(context.Canceled, err)
+	}
+}
@@ -0,0 +1,63 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidMerkleProof indicates that the Merkle proof doesn't match its root.
+var ErrInvalidMerkleProof = errors.New("merkle proof not valid")
+
+// SecurityParamMismatchError indicates that the number of proven leaves doesn't match the security param.
+type SecurityParamMismatchError struct {
+	NumProvenLeaves int
+	SecurityParam   uint32
+}
+
+func (e *SecurityParamMismatchError) Error() string {
+	return fmt.Sprintf("number of proven leaves (%d) must be equal to security param (%d)", e.NumProvenLeaves, e.SecurityParam)
+}
+
+// MalformedMerkleProofError indicates that the Merkle proof couldn't be validated, since it's malformed.
+type MalformedMerkleProofError struct {
+	Err error
+}
+
+func (e *MalformedMerkleProofError) Error() string {
+	return fmt.Sprintf("error while validating merkle proof: %v", e.Err)
+}
+
+func (e *MalformedMerkleProofError) Unwrap() error {
+	return e.Err
+}
+
+// LabelMismatchError indicates that a proven leaf label isn't derived from its left cousins in the Merkle tree.
+// Index is the position of the leaf in the proven leaves list, and LeafID is its position in the tree.
+type LabelMismatchError struct {
+	Index    int
+	LeafID   uint64
+	Expected []byte
+	Actual   []byte
+}
+
+func (e *LabelMismatchError) Error() string {
+	return fmt.Sprintf("label at index %d incorrect - expected: %x actual: %x", e.Index, e.Expected, e.Actual)
+}
+
+// messageError is a proof message validation failure of a given kind, which wraps its cause.
+type messageError struct {
+	kind error
+	err  error
+}
+
+func (e *messageError) Error() string {
+	return fmt.Sprintf("%v: %v", e.kind, e.err)
+}
+
+func (e *messageError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *messageError) Unwrap() error {
+	return e.err
+}
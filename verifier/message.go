@@ -0,0 +1,86 @@
+package verifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/nullstyle/go-xdr/xdr3"
+	"github.com/spacemeshos/merkle-tree"
+	"github.com/spacemeshos/poet/hash"
+	"github.com/spacemeshos/poet/shared"
+	"golang.org/x/crypto/ed25519"
+)
+
+var (
+	ErrMalformedMessage          = errors.New("malformed proof message")
+	ErrUnexpectedService         = errors.New("proof message is not from the expected service")
+	ErrInsufficientSecurityParam = errors.New("proof message security param is lower than required")
+	ErrInsufficientNumLeaves     = errors.New("proof message number of leaves is lower than required")
+	ErrInvalidMembers            = errors.New("invalid proof message members")
+	ErrInvalidProof              = errors.New("invalid proof")
+)
+
+// ValidateProofMessage decodes an XDR-serialized PoetProofMessage, as broadcast by a PoET service, and verifies it.
+// It verifies the service signature, recomputes the members Merkle root, and validates the proof using the root as
+// its statement, so that the proof is bound to the members. If servicePubKey is specified, the message is required to be
+// signed by it. The message number of leaves, which is the sequential work that the proof attests to, is required to be
+// at least minNumLeaves (e.g. 2^n for an expected n param), and its security param is required to be at least
+// minSecurityParam. The returned errors match the package Err* errors with errors.Is, according to the kind of failure, and wrap
+// their cause, such as the proof validation error.
+func ValidateProofMessage(data []byte, servicePubKey ed25519.PublicKey, minNumLeaves uint64, minSecurityParam uint32) (*shared.PoetProofMessage, error) {
+	msg := new(shared.PoetProofMessage)
+	if _, err := xdr.Unmarshal(bytes.NewReader(data), msg); err != nil {
+		return nil, &messageError{kind: ErrMalformedMessage, err: err}
+	}
+
+	if servicePubKey != nil && !bytes.Equal(servicePubKey, msg.ServicePubKey) {
+		return nil, &messageError{kind: ErrUnexpectedService, err: fmt.Errorf("%x", msg.ServicePubKey)}
+	}
+	if err := shared.VerifyProofMessageSignature(msg); err != nil {
+		return nil, &messageError{kind: shared.ErrInvalidSignature, err: err}
+	}
+
+	if err := ValidatePoetProof(&msg.GossipPoetProof, minNumLeaves, minSecurityParam); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// ValidatePoetProof verifies a proof, as included in a proof message, without its service signature. It recomputes
+// the members Merkle root, and validates the proof using the root as its statement. The proof number of leaves is
+// required to be at least minNumLeaves, and its security param is required to be at least minSecurityParam.
+func ValidatePoetProof(proof *shared.GossipPoetProof, minNumLeaves uint64, minSecurityParam uint32) error {
+	if proof.NumLeaves < minNumLeaves {
+		return &messageError{kind: ErrInsufficientNumLeaves, err: fmt.Errorf("%d < %d", proof.NumLeaves, minNumLeaves)}
+	}
+	if proof.SecurityParam < minSecurityParam {
+		return &messageError{kind: ErrInsufficientSecurityParam, err: fmt.Errorf("%d < %d", proof.SecurityParam, minSecurityParam)}
+	}
+
+	statement, err := MembersRoot(proof.Members)
+	if err != nil {
+		return &messageError{kind: ErrInvalidMembers, err: err}
+	}
+
+	err = Validate(proof.MerkleProof, hash.GenLabelHashFunc(statement), hash.GenMerkleHashFunc(statement), proof.NumLeaves, proof.SecurityParam)
+	if err != nil {
+		return &messageError{kind: ErrInvalidProof, err: err}
+	}
+
+	return nil
+}
+
+// MembersRoot calculates the Merkle root of the members list, which the service uses as the proof statement.
+func MembersRoot(members [][]byte) ([]byte, error) {
+	mtree, err := merkle.NewTree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize merkle tree: %v", err)
+	}
+	for _, member := range members {
+		if err := mtree.AddLeaf(member); err != nil {
+			return nil, err
+		}
+	}
+	return mtree.Root(), nil
+}
@@ -0,0 +1,103 @@
+package verifier
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"github.com/nullstyle/go-xdr/xdr3"
+	"github.com/spacemeshos/poet/hash"
+	"github.com/spacemeshos/poet/prover"
+	"github.com/spacemeshos/poet/shared"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+	"io/ioutil"
+	"testing"
+)
+
+func TestValidateProofMessage(t *testing.T) {
+	r := require.New(t)
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	r.NoError(err)
+
+	members := [][]byte{[]byte("member 1"), []byte("member 2"), []byte("member 3")}
+	statement, err := MembersRoot(members)
+	r.NoError(err)
+
+	numLeaves := uint64(1 << 10)
+	securityParam := uint32(8)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+	merkleProof, err := prover.GenerateProofWithoutPersistency(tempdir, hash.GenLabelHashFunc(statement), hash.GenMerkleHashFunc(statement), numLeaves, securityParam, prover.LowestMerkleMinMemoryLayer)
+	r.NoError(err)
+
+	newMessage := func() *shared.PoetProofMessage {
+		return &shared.PoetProofMessage{
+			GossipPoetProof: shared.GossipPoetProof{
+				MerkleProof:   *merkleProof,
+				Members:       members,
+				NumLeaves:     numLeaves,
+				SecurityParam: securityParam,
+			},
+			ServicePubKey: pubKey,
+			RoundID:       "1",
+		}
+	}
+	serialize := func(msg *shared.PoetProofMessage) []byte {
+		var buf bytes.Buffer
+		_, err := xdr.Marshal(&buf, msg)
+		r.NoError(err)
+		return buf.Bytes()
+	}
+
+	msg := newMessage()
+	r.NoError(shared.SignProofMessage(privKey, msg))
+	data := serialize(msg)
+
+	validated, err := ValidateProofMessage(data, pubKey, numLeaves, securityParam)
+	r.NoError(err)
+	r.Equal(msg, validated)
+
+	_, err = ValidateProofMessage(data, nil, 0, 0)
+	r.NoError(err)
+
+	_, err = ValidateProofMessage(data[:len(data)/2], nil, 0, 0)
+	r.True(errors.Is(err, ErrMalformedMessage), err)
+
+	otherPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	r.NoError(err)
+	_, err = ValidateProofMessage(data, otherPubKey, 0, 0)
+	r.True(errors.Is(err, ErrUnexpectedService), err)
+
+	_, err = ValidateProofMessage(data, pubKey, numLeaves, securityParam+1)
+	r.True(errors.Is(err, ErrInsufficientSecurityParam), err)
+
+	// A tampered message isn't covered by the signature.
+	msg = newMessage()
+	r.NoError(shared.SignProofMessage(privKey, msg))
+	msg.RoundID = "2"
+	_, err = ValidateProofMessage(serialize(msg), nil, 0, 0)
+	r.True(errors.Is(err, shared.ErrInvalidSignature), err)
+
+	// A signed proof which wasn't generated from the members list is rejected.
+	msg = newMessage()
+	msg.Members = members[:2]
+	r.NoError(shared.SignProofMessage(privKey, msg))
+	_, err = ValidateProofMessage(serialize(msg), nil, 0, 0)
+	r.True(errors.Is(err, ErrInvalidProof), err)
+	var mismatch *LabelMismatchError
+	r.True(errors.As(err, &mismatch) || errors.Is(err, ErrInvalidMerkleProof), err)
+
+	// A correctly signed proof of a lower number of leaves than required, i.e. of less sequential work, is rejected.
+	smallNumLeaves := uint64(1 << 4)
+	smallTempdir, _ := ioutil.TempDir("", "poet-test")
+	smallProof, err := prover.GenerateProofWithoutPersistency(smallTempdir, hash.GenLabelHashFunc(statement), hash.GenMerkleHashFunc(statement), smallNumLeaves, securityParam, prover.LowestMerkleMinMemoryLayer)
+	r.NoError(err)
+	msg = newMessage()
+	msg.MerkleProof = *smallProof
+	msg.NumLeaves = smallNumLeaves
+	r.NoError(shared.SignProofMessage(privKey, msg))
+	_, err = ValidateProofMessage(serialize(msg), pubKey, 0, securityParam)
+	r.NoError(err)
+	_, err = ValidateProofMessage(serialize(msg), pubKey, numLeaves, securityParam)
+	r.True(errors.Is(err, ErrInsufficientNumLeaves), err)
+}
@@ -2,7 +2,6 @@ package verifier
 
 import (
 	"bytes"
-	"fmt"
 	"github.com/spacemeshos/merkle-tree"
 	"github.com/spacemeshos/poet/shared"
 	"sort"
@@ -10,29 +9,29 @@ import (
 
 // Validate verifies that a Merkle proof was generated by an honest PoET prover. It validates that the number of proven
 // leaves matches the security param, validates the Merkle proof itself and verifies the labels are derived from the
-// left cousins in the Merkle tree.
+// left cousins in the Merkle tree. Its errors are a *SecurityParamMismatchError, a *MalformedMerkleProofError,
+// ErrInvalidMerkleProof or a *LabelMismatchError, respectively.
 func Validate(proof shared.MerkleProof, labelHashFunc func(data []byte) []byte,
-	merkleHashFunc func(lChild, rChild []byte) []byte, numLeaves uint64, securityParam uint8) error {
+	merkleHashFunc func(lChild, rChild []byte) []byte, numLeaves uint64, securityParam uint32) error {
 
 	if int(securityParam) != len(proof.ProvenLeaves) {
-		return fmt.Errorf("number of proven leaves (%d) must be equal to security param (%d)",
-			len(proof.ProvenLeaves), securityParam)
+		return &SecurityParamMismatchError{NumProvenLeaves: len(proof.ProvenLeaves), SecurityParam: securityParam}
 	}
 	provenLeafIndices := asSortedSlice(shared.FiatShamir(proof.Root, numLeaves, securityParam))
 	valid, parkingSnapshots, err := merkle.ValidatePartialTreeWithParkingSnapshots(provenLeafIndices,
 		proof.ProvenLeaves, proof.ProofNodes, proof.Root, merkleHashFunc)
 	if err != nil {
-		return fmt.Errorf("error while validating merkle proof: %v", err)
+		return &MalformedMerkleProofError{Err: err}
 	}
 	if !valid {
-		return fmt.Errorf("merkle proof not valid")
+		return ErrInvalidMerkleProof
 	}
 
 	makeLabel := shared.MakeLabelFunc()
 	for id, label := range proof.ProvenLeaves {
 		expectedLabel := makeLabel(labelHashFunc, provenLeafIndices[id], parkingSnapshots[id])
 		if !bytes.Equal(expectedLabel, label) {
-			return fmt.Errorf("label at index %d incorrect - expected: %x actual: %x", id, expectedLabel, label)
+			return &LabelMismatchError{Index: id, LeafID: provenLeafIndices[id], Expected: expectedLabel, Actual: label}
 		}
 	}
 
@@ -1,6 +1,7 @@
 package verifier
 
 import (
+	"errors"
 	"github.com/spacemeshos/poet/hash"
 	"github.com/spacemeshos/poet/prover"
 	"github.com/spacemeshos/poet/shared"
@@ -15,7 +16,7 @@ func TestValidate(t *testing.T) {
 
 	challenge := []byte("challenge")
 	numLeaves := uint64(16)
-	securityParam := uint8(4)
+	securityParam := uint32(4)
 	merkleProof, err := prover.GenerateProofWithoutPersistency(tempdir, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam, prover.LowestMerkleMinMemoryLayer)
 	r.NoError(err)
 
@@ -31,9 +32,13 @@ func TestValidateWrongSecParam(t *testing.T) {
 	}
 	challenge := []byte("challenge")
 	numLeaves := uint64(16)
-	securityParam := uint8(4)
+	securityParam := uint32(4)
 	err := Validate(merkleProof, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam)
 	require.EqualError(t, err, "number of proven leaves (2) must be equal to security param (4)")
+
+	var mismatch *SecurityParamMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	require.Equal(t, &SecurityParamMismatchError{NumProvenLeaves: 2, SecurityParam: securityParam}, mismatch)
 }
 
 func TestValidateWrongMerkleValidationError(t *testing.T) {
@@ -44,9 +49,12 @@ func TestValidateWrongMerkleValidationError(t *testing.T) {
 	}
 	challenge := []byte("challenge")
 	numLeaves := uint64(16)
-	securityParam := uint8(0)
+	securityParam := uint32(0)
 	err := Validate(merkleProof, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam)
 	require.EqualError(t, err, "error while validating merkle proof: at least one leaf is required for validation")
+
+	var malformed *MalformedMerkleProofError
+	require.True(t, errors.As(err, &malformed))
 }
 
 func TestValidateWrongRoot(t *testing.T) {
@@ -55,7 +63,7 @@ func TestValidateWrongRoot(t *testing.T) {
 
 	challenge := []byte("challenge")
 	numLeaves := uint64(16)
-	securityParam := uint8(4)
+	securityParam := uint32(4)
 	merkleProof, err := prover.GenerateProofWithoutPersistency(tempdir, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam, prover.LowestMerkleMinMemoryLayer)
 	r.NoError(err)
 
@@ -63,6 +71,7 @@ func TestValidateWrongRoot(t *testing.T) {
 
 	err = Validate(*merkleProof, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam)
 	r.EqualError(err, "merkle proof not valid")
+	r.Equal(ErrInvalidMerkleProof, err)
 }
 
 func BadLabelHashFunc(data []byte) []byte {
@@ -75,11 +84,49 @@ func TestValidateFailLabelValidation(t *testing.T) {
 
 	challenge := []byte("challenge")
 	numLeaves := uint64(16)
-	securityParam := uint8(4)
+	securityParam := uint32(4)
 	merkleProof, err := prover.GenerateProofWithoutPersistency(tempdir, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam, prover.LowestMerkleMinMemoryLayer)
 	r.NoError(err)
 
 	err = Validate(*merkleProof, BadLabelHashFunc, hash.GenMerkleHashFunc(challenge), numLeaves, securityParam)
 	r.Error(err)
 	r.Regexp("label at index 0 incorrect - expected: [0-f]* actual: [0-f]*", err.Error())
+
+	var mismatch *LabelMismatchError
+	r.True(errors.As(err, &mismatch))
+	r.Equal(0, mismatch.Index)
+	r.Equal(BadLabelHashFunc(nil), mismatch.Expected)
+	r.Equal(merkleProof.ProvenLeaves[0], mismatch.Actual)
+}
+
+func TestValidateNonPowerOfTwoLeaves(t *testing.T) {
+	r := require.New(t)
+
+	challenge := []byte("challenge")
+	securityParam := uint32(4)
+	for _, numLeaves := range []uint64{17, 33, 100, 1000, 5000} {
+		for _, minMemoryLayer := range []uint{prover.LowestMerkleMinMemoryLayer, 4, 64} {
+			tempdir, _ := ioutil.TempDir("", "poet-test")
+			merkleProof, err := prover.GenerateProofWithoutPersistency(tempdir, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam, minMemoryLayer)
+			r.NoError(err)
+
+			err = Validate(*merkleProof, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam)
+			r.NoError(err, "numLeaves: %d, minMemoryLayer: %d", numLeaves, minMemoryLayer)
+		}
+	}
+}
+
+func TestValidateLargeSecParam(t *testing.T) {
+	r := require.New(t)
+	tempdir, _ := ioutil.TempDir("", "poet-test")
+
+	challenge := []byte("challenge")
+	numLeaves := uint64(1 << 12)
+	securityParam := uint32(1000)
+	merkleProof, err := prover.GenerateProofWithoutPersistency(tempdir, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam, prover.LowestMerkleMinMemoryLayer)
+	r.NoError(err)
+	r.Len(merkleProof.ProvenLeaves, int(securityParam))
+
+	err = Validate(*merkleProof, hash.GenLabelHashFunc(challenge), hash.GenMerkleHashFunc(challenge), numLeaves, securityParam)
+	r.NoError(err)
 }